@@ -0,0 +1,27 @@
+package batchflow
+
+import "context"
+
+// AfterFlushFunc 每个 flush 分组执行结束（成功或失败）后的回调。rowCount 为该组提交的请求数，
+// err 为该组的执行错误（成功为 nil）；ctx 为 flush 的上下文，返回后不应再使用。
+type AfterFlushFunc func(ctx context.Context, schema SchemaInterface, rowCount int, err error)
+
+// WithAfterFlush 注册 flush 分组执行后的回调，用于提交后的副作用（如发送消息通知），无需包装执行器。
+// 回调在 flush goroutine 中同步调用，不持有 BatchFlow 内部锁，但会阻塞当前 flush：
+// 慢回调会占用 MaxConcurrentFlushes 名额并延后后续分组，耗时操作请自行异步化。
+// 回调中的 panic 与执行器 panic 一样被恢复为 *PanicError。传入 nil 取消注册。
+func (b *BatchFlow) WithAfterFlush(fn AfterFlushFunc) *BatchFlow {
+	if fn == nil {
+		b.afterFlush.Store(nil)
+		return b
+	}
+	b.afterFlush.Store(&fn)
+	return b
+}
+
+func (b *BatchFlow) notifyAfterFlush(ctx context.Context, schema SchemaInterface, rowCount int, err error) {
+	b.drain.record(schema, rowCount, err)
+	if fn := b.afterFlush.Load(); fn != nil {
+		(*fn)(ctx, schema, rowCount, err)
+	}
+}
@@ -0,0 +1,114 @@
+package batchflow
+
+import (
+	"time"
+)
+
+// forcedFlushInterval 强制 flush 时临时使用的管道定时间隔
+const forcedFlushInterval = time.Millisecond
+
+// trackEnqueue 在主通道入队前登记请求（仅 MaxBatchAge > 0 时调用）。
+// 先登记后发送，保证 flush 统计的条数不会超过已登记条数。
+func (b *BatchFlow) trackEnqueue(enqueuedAt time.Time) {
+	b.ageMu.Lock()
+	b.agePending++
+	wake := b.oldestPending.IsZero()
+	if wake {
+		b.oldestPending = enqueuedAt
+	}
+	b.ageMu.Unlock()
+	if wake {
+		select {
+		case b.ageSignal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// untrackEnqueue 撤销入队失败的登记
+func (b *BatchFlow) untrackEnqueue() {
+	b.ageMu.Lock()
+	b.agePending--
+	if b.agePending == 0 {
+		b.oldestPending = time.Time{}
+	}
+	b.ageMu.Unlock()
+}
+
+// trackFlush 在主通道 flush 开始时扣减已取出的请求，并在强制 flush 后恢复配置的 FlushInterval。
+// 缓冲区仍有剩余请求时，以本批最晚入队时间近似剩余请求中最早的入队时间（偏保守）。
+func (b *BatchFlow) trackFlush(batchData []*queuedRequest) {
+	var n int
+	var latest time.Time
+	for _, q := range batchData {
+		if q == nil || q.priority {
+			continue
+		}
+		n++
+		if q.enqueuedAt.After(latest) {
+			latest = q.enqueuedAt
+		}
+	}
+	if n == 0 {
+		return
+	}
+
+	b.ageMu.Lock()
+	b.agePending -= n
+	if b.agePending <= 0 {
+		b.agePending = 0
+		b.oldestPending = time.Time{}
+	} else if latest.After(b.oldestPending) {
+		b.oldestPending = latest
+	}
+	b.ageMu.Unlock()
+
+	// 内存压力期间保持强制间隔，由 watchMemoryPressure 在压力解除后恢复
+	if b.ageForcing.CompareAndSwap(true, false) && !b.memPressure.Load() {
+		b.pipeline.UpdateFlushInterval(b.EffectiveFlushInterval())
+	}
+	select {
+	case b.ageSignal <- struct{}{}:
+	default:
+	}
+}
+
+// watchBatchAge 在最早未 flush 请求的等待时间达到 MaxBatchAge 时强制主通道 flush。
+// go-pipeline 没有公开的立即 flush 接口，这里将定时间隔临时缩短为 forcedFlushInterval，
+// 由下一次 flush 的 trackFlush 恢复为配置值。
+func (b *BatchFlow) watchBatchAge() {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		b.ageMu.Lock()
+		oldest := b.oldestPending
+		b.ageMu.Unlock()
+
+		if !oldest.IsZero() {
+			wait := time.Until(oldest.Add(b.maxBatchAge))
+			if wait <= 0 {
+				if b.ageForcing.CompareAndSwap(false, true) {
+					b.pipeline.UpdateFlushInterval(forcedFlushInterval)
+				}
+				// 等待 trackFlush 唤醒；兜底轮询避免与并发 flush 的恢复交错后错过强制 flush
+				wait = max(b.maxBatchAge/4, forcedFlushInterval)
+			}
+			timer.Reset(wait)
+		}
+
+		select {
+		case <-b.done:
+			return
+		case <-b.ageSignal:
+		case <-timer.C:
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package batchflow
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+)
+
+type batchIDKey struct{}
+
+var (
+	// batchIDPrefix 进程级随机前缀，避免多实例日志汇聚后 ID 冲突
+	batchIDPrefix = func() string {
+		var b [4]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return "00000000"
+		}
+		return hex.EncodeToString(b[:])
+	}()
+	batchIDSeq atomic.Uint64
+)
+
+// newBatchID 生成形如 "1a2b3c4d-000000000000002a" 的批次 ID：进程随机前缀 + 单调递增序号
+func newBatchID() string {
+	return fmt.Sprintf("%s-%016x", batchIDPrefix, batchIDSeq.Add(1))
+}
+
+func withBatchID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, batchIDKey{}, id)
+}
+
+// BatchIDFromContext 返回 flush 为当前 schema 组分配的批次 ID，用于在自定义处理器、
+// 观测回调与日志中关联同一批次。同一 flush 内同一 schema 组的所有 ExecuteBatch 调用
+// （MaxGroupRows/MaxGroupBytes 拆分、SparseColumns 分区及执行器重试）共享同一个 ID。
+func BatchIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(batchIDKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,63 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// lowLimitDriver 复用 MockDriver 的 SQL 生成，但声明极低的参数上限
+type lowLimitDriver struct {
+	*batchflow.MockDriver
+	limit int
+}
+
+func (d lowLimitDriver) Capabilities() batchflow.DriverCapabilities {
+	caps := d.MockDriver.Capabilities()
+	caps.MaxParameters = d.limit
+	return caps
+}
+
+func wideRows(n int) []map[string]any {
+	rows := make([]map[string]any, n)
+	for i := range rows {
+		rows[i] = map[string]any{"c1": i, "c2": i, "c3": i, "c4": i, "c5": i}
+	}
+	return rows
+}
+
+func TestBatchTooLarge_TypedErrorFromExecutors(t *testing.T) {
+	driver := lowLimitDriver{MockDriver: batchflow.NewMockDriver("mysql"), limit: 10}
+	schema := batchflow.NewSQLSchema("wide", batchflow.ConflictIgnoreOperationConfig, "c1", "c2", "c3", "c4", "c5")
+
+	// 2 行 * 5 列 = 10 个参数，恰好在上限内
+	if err := batchflow.NewMockExecutorWithDriver(driver).ExecuteBatch(context.Background(), schema, wideRows(2)); err != nil {
+		t.Fatalf("batch within limit should succeed: %v", err)
+	}
+
+	err := batchflow.NewMockExecutorWithDriver(driver).ExecuteBatch(context.Background(), schema, wideRows(3))
+	var tooLarge *batchflow.BatchTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected *BatchTooLargeError, got %v", err)
+	}
+	if tooLarge.Params != 15 || tooLarge.Limit != 10 || tooLarge.Schema != "wide" {
+		t.Fatalf("unexpected error detail: %+v", tooLarge)
+	}
+
+	db, fake := openFlakyDB(t, 0)
+	exec := batchflow.NewThrottledBatchExecutor(batchflow.NewSQLBatchProcessor(db, driver)).
+		WithRetryConfig(batchflow.RetryConfig{Enabled: true, MaxAttempts: 3, BackoffBase: time.Millisecond})
+	err = exec.ExecuteBatch(context.Background(), schema, wideRows(3))
+	if !errors.Is(err, batchflow.ErrBatchTooLarge) {
+		t.Fatalf("expected ErrBatchTooLarge from SQL executor, got %v", err)
+	}
+	if fake.execs.Load() != 0 {
+		t.Fatalf("oversized batch must not reach the database, got %d execs", fake.execs.Load())
+	}
+	if retryable, _ := batchflow.ClassifyError(err); retryable {
+		t.Fatal("batch too large must not be retryable")
+	}
+}
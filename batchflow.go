@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,12 +32,71 @@ import (
 */
 type BatchFlow struct {
 	pipeline        *gopipeline.StandardPipeline[*queuedRequest] // 异步批量处理管道
+	priorityLane    *gopipeline.StandardPipeline[*queuedRequest] // 可选高优先级通道（PriorityFlushInterval > 0 时启用）
 	executor        BatchExecutor                                // 批量执行器（数据库特定）
 	metricsReporter MetricsReporter                              // 指标上报器（默认 Noop）
+	logger          Logger                                       // 诊断日志（默认 Noop）
 	closed          atomic.Bool                                  // 当创建时上下文被取消后置为 true，拒绝后续提交
 	closeOnce       sync.Once
+	submitMu        sync.RWMutex // Submit 入队期间持有读锁；Close 持写锁关闭数据通道，保证不会向已关闭的通道发送
 	done            chan struct{}
 
+	onError     atomic.Pointer[func(error)] // OnError 注册的回调
+	onErrorOnce sync.Once
+
+	afterFlush   atomic.Pointer[AfterFlushFunc]   // WithAfterFlush 注册的回调
+	rowTransform atomic.Pointer[RowTransformFunc] // WithRowTransform 注册的行变换钩子
+
+	recentErrs *recentErrors // 最近的 flush 错误（PipelineConfig.RecentErrorsSize）；nil 表示关闭
+
+	nonFinite NonFiniteFloatPolicy // NaN/±Inf 浮点值处理策略（PipelineConfig.NonFiniteFloats）
+
+	mergedErrOnce sync.Once // 启用优先通道时合并两条管道的错误通道
+	mergedErrs    chan error
+	mergedDone    chan struct{} // 两个转发协程都已退出（不再写入 mergedErrs）后关闭
+
+	pauseMu       sync.Mutex
+	resumeCh      chan struct{} // 非 nil 表示已暂停；Resume 时关闭以唤醒等待中的 flush
+	cancelPaused  bool          // 创建时的 ctx 取消时处于暂停状态：尚未进入等待的批次同样按收尾处理
+	drainOnCancel bool          // PipelineConfig.DrainOnCancel：暂停中等待的批次在创建时的 ctx 取消后仍执行
+	drainGrace    time.Duration // PipelineConfig.DrainGracePeriod：上述批次使用的收尾时限
+
+	encryptColumns  map[string]ColumnEncryptFunc // 组装时按列加密（PipelineConfig.EncryptColumns）
+	compressColumns map[string]CompressionType   // 组装时按列压缩（PipelineConfig.CompressColumns）
+
+	queueHighWater atomic.Int64 // 当前窗口内观测到的数据通道最大长度
+
+	errAggWindow time.Duration // 相同错误聚合窗口（PipelineConfig.ErrorAggregationWindow）
+	errChanSize  int           // 固定的错误通道缓冲大小（PipelineConfig.ErrorChanSize）；0 表示由首次 ErrorChan 调用决定
+	aggErrOnce   sync.Once
+	aggErrs      chan error
+	aggDone      chan struct{} // 聚合协程退出（不再写入 aggErrs）后关闭
+
+	maxGroupRows    int  // 单次 ExecuteBatch 的行数上限（PipelineConfig.MaxGroupRows）
+	maxGroupBytes   int  // 单次 ExecuteBatch 的近似字节上限（PipelineConfig.MaxGroupBytes）
+	groupByIdentity bool // 按 schema 逻辑标识而非实例分组（PipelineConfig.GroupSchemasByIdentity）
+
+	maxBatchAge time.Duration // 主通道请求最长等待时间（PipelineConfig.MaxBatchAge）
+
+	effectiveFlushInterval atomic.Int64 // 应用 FlushIntervalJitter 后主通道实际使用的间隔，强制 flush 后恢复为该值
+	flushJitter            float64      // PipelineConfig.FlushIntervalJitter；UpdateConfig 调整间隔时重新应用
+	bufferSize             uint32       // 生效的 BufferSize，UpdateConfig 据此校验 FlushSize
+	concurrencyLimit       atomic.Int64 // 当前 ConcurrencyLimit，供 ConfigMetricsReporter 导出
+	ageMu                  sync.Mutex
+	oldestPending          time.Time // 主通道最早未 flush 请求的入队时间；零值表示没有
+	agePending             int       // 主通道已入队但尚未 flush 的请求数
+	ageForcing             atomic.Bool
+	ageSignal              chan struct{}
+
+	drain drainRecorder // Close 开始后各分组的执行结果（CloseWithResult）
+
+	pending     pendingTracker // 已入队但尚未处理完成的请求数（WaitUntilEmpty）
+	errDelivery pendingTracker // 已失败但错误尚未写入（或丢弃于）管道错误通道的批次数
+	settled     chan struct{}  // 后台管道退出且在途异步批次及其错误均已处理后关闭，错误消费者据此退出
+
+	flushSize   atomic.Uint32 // 主通道配置的 FlushSize，内存压力解除后恢复
+	memPressure atomic.Bool   // 堆占用是否超过 MemoryPressureThreshold
+
 	runErrMu sync.RWMutex
 	runErr   error
 }
@@ -42,6 +104,7 @@ type BatchFlow struct {
 type queuedRequest struct {
 	request    *Request
 	enqueuedAt time.Time
+	priority   bool // 是否进入优先通道
 }
 
 // NewBatchFlow 创建 BatchFlow 实例
@@ -57,6 +120,16 @@ func NewBatchFlow(ctx context.Context, buffSize uint32, flushSize uint32, flushI
 	}, executor)
 }
 
+// NewBatchFlowWithCancel 与 NewBatchFlow 相同，另返回停止函数 stop：stop 即 Close，
+// 停止接收新请求、flush 剩余数据并等待后台 goroutine 退出，可重复调用。
+// 适合嵌入 BatchFlow 的库在自身关闭时优雅停止，而无需为其单独派生并取消 ctx。
+func NewBatchFlowWithCancel(ctx context.Context, buffSize uint32, flushSize uint32, flushInterval time.Duration, executor BatchExecutor) (*BatchFlow, func() error) {
+	b := NewBatchFlow(ctx, buffSize, flushSize, flushInterval, executor)
+	return b, b.Close
+}
+
+var _ io.Closer = (*BatchFlow)(nil)
+
 func newBatchFlow(ctx context.Context, config PipelineConfig, executor BatchExecutor) *BatchFlow {
 	// 确保 BatchFlow 始终拥有可用 reporter，但不误覆盖自定义执行器的已有配置
 	var reporter MetricsReporter
@@ -74,14 +147,81 @@ func newBatchFlow(ctx context.Context, config PipelineConfig, executor BatchExec
 		reporter = NewNoopMetricsReporter()
 	}
 
+	// 日志优先使用配置；否则沿用执行器已有的 Logger（同样采用只读探测）
+	logger := config.Logger
+	if logger == nil {
+		if lp, ok := executor.(interface{ Logger() Logger }); ok {
+			logger = lp.Logger()
+		}
+	}
+
+	// 不报错的构造函数对不兼容的组合做钳制并记录警告；E 后缀构造函数在此之前已返回错误
+	config = config.clampIncompatible(loggerOrNoop(logger))
+
+	// 每个实例在构造时确定一次抖动后的间隔，后续 ticker、MaxBatchAge 恢复与配置导出均使用该值
+	if config.FlushIntervalJitter > 0 {
+		config.FlushInterval = jitterFlushInterval(config.withDefaults().FlushInterval, config.FlushIntervalJitter)
+	}
+
+	if cmr, ok := reporter.(ConfigMetricsReporter); ok && cmr != nil {
+		cmr.SetConfig(config.BufferSize, config.FlushSize, config.FlushInterval, config.ConcurrencyLimit)
+	}
+
 	batchFlow := &BatchFlow{
 		executor:        executor,
 		metricsReporter: reporter,
+		logger:          loggerOrNoop(logger),
 		done:            make(chan struct{}),
+		settled:         make(chan struct{}),
+		encryptColumns:  maps.Clone(config.EncryptColumns),
+		compressColumns: maps.Clone(config.CompressColumns),
+		errAggWindow:    config.ErrorAggregationWindow,
+		errChanSize:     config.ErrorChanSize,
+		maxGroupRows:    config.MaxGroupRows,
+		maxGroupBytes:   config.MaxGroupBytes,
+		groupByIdentity: config.GroupSchemasByIdentity,
+		recentErrs:      newRecentErrors(config.RecentErrorsSize),
+		nonFinite:       config.NonFiniteFloats,
+		flushJitter:     config.FlushIntervalJitter,
+		bufferSize:      config.withDefaults().BufferSize,
+		drainOnCancel:   config.DrainOnCancel,
+		drainGrace:      config.withDefaults().DrainGracePeriod,
+	}
+	batchFlow.effectiveFlushInterval.Store(int64(config.withDefaults().FlushInterval))
+	batchFlow.flushSize.Store(config.withDefaults().FlushSize)
+	batchFlow.concurrencyLimit.Store(int64(config.ConcurrencyLimit))
+	if cl, ok := executor.(interface{ ConcurrencyLimit() int }); ok && forwardsConcurrencyLimit(executor) {
+		batchFlow.concurrencyLimit.Store(int64(cl.ConcurrencyLimit()))
+	}
+	if config.MaxBatchAge > 0 && config.MaxBatchAge < config.FlushInterval {
+		batchFlow.maxBatchAge = config.MaxBatchAge
+		batchFlow.ageSignal = make(chan struct{}, 1)
 	}
 
 	// 创建 flush 函数，使用批量执行器处理数据
 	flushFunc := func(ctx context.Context, batchData []*queuedRequest) (err error) {
+		// 最先注册、最后执行：整批（含 AfterFlush 回调）处理完成后才扣减待处理计数
+		defer batchFlow.pending.done(len(batchData))
+		// 与错误通道同源：flush 返回的错误（含恢复的 panic）写入最近错误环形缓冲
+		var failedSchema string
+		defer func() {
+			if err != nil {
+				batchFlow.recentErrs.add(failedSchema, err)
+				// 返回后由 go-pipeline 写入错误通道，写入（或丢弃）后经 MetricsHook.Error 扣减
+				batchFlow.errDelivery.add(1)
+			}
+		}()
+		if batchFlow.maxBatchAge > 0 {
+			batchFlow.trackFlush(batchData)
+		}
+		// 池化请求在整批处理结束（含失败）后归还
+		defer releasePooledRequests(batchData)
+		// 暂停期间阻塞 flush（Submit 按待处理总数限流）；等待中创建时的 ctx 被取消且开启 DrainOnCancel 时改用收尾 ctx 执行
+		var stopDrain context.CancelFunc
+		if ctx, stopDrain, err = batchFlow.waitIfPaused(ctx); err != nil {
+			return err
+		}
+		defer stopDrain()
 		// 管道级处理耗时（与执行器级 ObserveExecuteDuration 区分）
 		processStart := time.Now()
 		defer func() {
@@ -94,6 +234,12 @@ func newBatchFlow(ctx context.Context, config PipelineConfig, executor BatchExec
 				pmr.ObserveProcessDuration(time.Since(processStart), status)
 			}
 		}()
+		// 执行器/驱动的 panic 转为错误投递到错误通道，避免单个批次拖垮进程（先于指标 defer 执行，状态记为 fail）
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
 
 		if pmr, ok := batchFlow.metricsReporter.(PipelineMetricsReporter); ok && pmr != nil {
 			now := time.Now()
@@ -108,56 +254,119 @@ func newBatchFlow(ctx context.Context, config PipelineConfig, executor BatchExec
 		if bmr, ok := batchFlow.metricsReporter.(BatchFlowMetricsReporter); ok && bmr != nil {
 			bmr.ObservePipelineFlushSize(len(batchData))
 		}
-		// 按schema分组处理
+		if qmr, ok := batchFlow.metricsReporter.(QueueMetricsReporter); ok && qmr != nil {
+			qmr.ObserveQueueHighWater(batchFlow.ResetQueueHighWater())
+		}
+		// 按schema分组处理（保留首次出现顺序，便于观测与排查）
 		schemaGroups := make(map[SchemaInterface][]*Request)
+		schemaOrder := make([]SchemaInterface, 0, 1)
+		var canonicalizer *schemaCanonicalizer
+		if batchFlow.groupByIdentity {
+			canonicalizer = &schemaCanonicalizer{}
+		}
 		for _, item := range batchData {
 			if item == nil || item.request == nil {
 				continue
 			}
 			request := item.request
 			schema := request.Schema()
+			if canonicalizer != nil {
+				schema = canonicalizer.canonical(schema)
+			}
+			if _, exists := schemaGroups[schema]; !exists {
+				schemaOrder = append(schemaOrder, schema)
+			}
 			schemaGroups[schema] = append(schemaGroups[schema], request)
 		}
 		if bmr, ok := batchFlow.metricsReporter.(BatchFlowMetricsReporter); ok && bmr != nil {
 			bmr.ObserveSchemaGroupsPerFlush(len(schemaGroups))
 		}
-
-		// 处理每个schema组
-		for schema, requests := range schemaGroups {
-			assembleStart := time.Now()
-			// 在开始耗时操作前快速检查
-			if err := ctx.Err(); err != nil {
-				return err
+		if fgr, ok := batchFlow.metricsReporter.(FlushGroupsMetricsReporter); ok && fgr != nil {
+			sizes := make([]int, len(schemaOrder))
+			for i, schema := range schemaOrder {
+				sizes[i] = len(schemaGroups[schema])
 			}
+			fgr.ObserveFlushGroups(len(schemaOrder), sizes)
+		}
 
-			// 转换为数据格式
-			data := make([]map[string]any, len(requests))
-			for i, request := range requests {
-				// 如果单个schema的数据量很大，可以定期检查
-				if len(requests) > 10000 && i%1000 == 0 {
+		// 处理每个schema组；超过 MaxGroupRows/MaxGroupBytes 的组拆为多次顺序执行，
+		// 配置 PartitionFunc 的组按目标表拆分，SparseColumns 的组再按已赋值列集合拆分
+		for _, group := range schemaOrder {
+			// 每个 schema 组分配一个批次 ID，随 ctx 传给执行器与 after-flush 回调
+			groupCtx := withBatchID(ctx, newBatchID())
+			for _, chunk := range splitGroup(schemaGroups[group], batchFlow.maxGroupRows, batchFlow.maxGroupBytes) {
+				for _, partition := range groupPartitions(group, chunk) {
+					schema, requests := partition.schema, partition.requests
+					failedSchema = schema.Name()
+					if len(requests) == 0 {
+						continue
+					}
+					assembleStart := time.Now()
+					// 在开始耗时操作前快速检查
 					if err := ctx.Err(); err != nil {
 						return err
 					}
-				}
-				rowData := make(map[string]any)
-				values := request.GetOrderedValues()
-				columns := schema.Columns()
 
-				for j, col := range columns {
-					if j < len(values) {
-						rowData[col] = values[j]
+					// 转换为数据格式（复用池化缓冲，ExecuteBatch 返回后归还）
+					columns := schema.Columns()
+					rows := acquireRows(len(requests))
+					data := *rows
+					for start := 0; start < len(requests); start += 1000 {
+						// 如果单个schema的数据量很大，可以定期检查
+						if len(requests) > 10000 {
+							if err := ctx.Err(); err != nil {
+								releaseRows(rows)
+								return err
+							}
+						}
+						end := min(start+1000, len(requests))
+						err := assembleRows(columns, requests[start:end], data[start:end])
+						if err == nil {
+							err = applyNonFinitePolicy(schema, data[start:end], batchFlow.nonFinite)
+						}
+						if err == nil {
+							err = batchFlow.transformRows(schema, data[start:end])
+						}
+						if err == nil {
+							err = compressRows(data[start:end], batchFlow.compressColumns)
+						}
+						if err == nil {
+							err = encryptRows(data[start:end], batchFlow.encryptColumns)
+						}
+						if err != nil {
+							releaseRows(rows)
+							err = batchErrorFromError(BatchStageValidate, OperationPreview{
+								Backend:    BackendCustom,
+								Operation:  OperationCustom,
+								Schema:     schema.Name(),
+								InputItems: len(requests),
+							}, len(requests), err)
+							batchFlow.notifyAfterFlush(groupCtx, schema, len(requests), err)
+							return err
+						}
 					}
-				}
-				data[i] = rowData
-			}
 
-			// 组装完成指标（批大小 + 组装耗时）
-			batchFlow.metricsReporter.ObserveBatchSize(len(requests))
-			batchFlow.metricsReporter.ObserveBatchAssemble(time.Since(assembleStart))
+					// 组装完成指标（批大小 + 组装耗时）
+					if smr, ok := batchFlow.metricsReporter.(SchemaMetricsReporter); ok && smr != nil {
+						smr.ObserveSchemaBatchSize(schema.Name(), len(requests))
+						smr.ObserveSchemaBatchAssemble(schema.Name(), time.Since(assembleStart))
+					} else {
+						batchFlow.metricsReporter.ObserveBatchSize(len(requests))
+						batchFlow.metricsReporter.ObserveBatchAssemble(time.Since(assembleStart))
+					}
+					if bbr, ok := batchFlow.metricsReporter.(BatchBytesMetricsReporter); ok && bbr != nil {
+						bbr.ObserveBatchBytes(approxRowsBytes(data))
+					}
 
-			// 执行批量操作
-			if err := batchFlow.executor.ExecuteBatch(ctx, schema, data); err != nil {
-				return err
+					// 执行批量操作（请求标签随 ctx 传给执行器与 after-flush 回调）
+					execCtx := withRowTags(groupCtx, requests, data)
+					err := batchFlow.executor.ExecuteBatch(execCtx, schema, data)
+					releaseRows(rows)
+					batchFlow.notifyAfterFlush(execCtx, schema, len(requests), err)
+					if err != nil {
+						return err
+					}
+				}
 			}
 		}
 		return nil
@@ -171,30 +380,214 @@ func newBatchFlow(ctx context.Context, config PipelineConfig, executor BatchExec
 	batchFlow.pipeline = pipeline
 
 	// 预留：挂接 go-pipeline v2.2.0 的 WithMetrics 到我们的 Reporter 扩展接口
-	attachPipelineMetrics(pipeline, reporter)
+	attachPipelineMetrics(pipeline, reporter, batchFlow.logger, batchFlow.errorDelivered)
+
+	// 可选优先通道：与主管道共享 flushFunc，仅 flush 间隔更短
+	lanes := []*gopipeline.StandardPipeline[*queuedRequest]{pipeline}
+	if config.PriorityFlushInterval > 0 {
+		laneConfig := config.goPipelineConfig()
+		laneConfig.FlushInterval = config.PriorityFlushInterval
+		batchFlow.priorityLane = gopipeline.NewStandardPipeline(laneConfig, flushFunc)
+		attachPipelineMetrics(batchFlow.priorityLane, reporter, batchFlow.logger, batchFlow.errorDelivered)
+		lanes = append(lanes, batchFlow.priorityLane)
+	}
+
+	var lanesWG sync.WaitGroup
+	laneErrs := make([]error, len(lanes))
+	for i, lane := range lanes {
+		lanesWG.Add(1)
+		go func() {
+			defer lanesWG.Done()
+			laneErrs[i] = lane.AsyncPerform(ctx)
+		}()
+	}
 	go func() {
 		defer close(batchFlow.done)
-		batchFlow.setRunErr(pipeline.AsyncPerform(ctx))
+		lanesWG.Wait()
+		if len(laneErrs) == 1 {
+			// 单通道保持原始错误值，避免改变调用方的等值判断
+			batchFlow.setRunErr(laneErrs[0])
+			return
+		}
+		batchFlow.setRunErr(errors.Join(laneErrs...))
+	}()
+	go func() {
+		<-batchFlow.done
+		batchFlow.awaitFlushes()
+		close(batchFlow.settled)
 	}()
-	// 标记管道生命周期：创建时 ctx 一旦取消，后续 Submit 均应拒绝
+	if batchFlow.errChanSize > 0 {
+		// 立即按配置初始化错误通道，避免首个错误先于 ErrorChan 调用到达时使用 go-pipeline 的默认容量
+		for _, lane := range lanes {
+			lane.ErrorChan(batchFlow.errChanSize)
+		}
+	}
+	if batchFlow.maxBatchAge > 0 {
+		go batchFlow.watchBatchAge()
+	}
+	if config.MemoryPressureThreshold > 0 {
+		interval := config.MemoryPressureCheckInterval
+		if interval <= 0 {
+			interval = defaultMemoryPressureCheckInterval
+		}
+		heapAlloc := config.HeapAllocFunc
+		if heapAlloc == nil {
+			heapAlloc = runtimeHeapAlloc
+		}
+		go batchFlow.watchMemoryPressure(config.MemoryPressureThreshold, interval, heapAlloc)
+	}
+	// 标记管道生命周期：创建时 ctx 一旦取消，后续 Submit 均应拒绝。
+	// 同时监听 done：仅调用 Close 而从不取消 ctx 时，该协程也必须退出，避免短生命周期的 BatchFlow 泄漏协程。
+	// 取消视同关闭：解除暂停，让 go-pipeline 的收尾 flush 与暂停中等待的批次不再等待 Resume。
 	go func() {
-		<-ctx.Done()
-		batchFlow.closed.Store(true)
+		select {
+		case <-ctx.Done():
+			batchFlow.closed.Store(true)
+			batchFlow.resumeOnCancel()
+		case <-batchFlow.done:
+		}
 	}()
 
 	return batchFlow
 }
 
+// Logger 返回 BatchFlow 使用的诊断日志
+func (b *BatchFlow) Logger() Logger {
+	return b.logger
+}
+
 // ErrorChan 获取错误通道
+// 配置了 PipelineConfig.ErrorChanSize 时忽略 size，始终使用配置值。
 func (b *BatchFlow) ErrorChan(size int) <-chan error {
-	return b.pipeline.ErrorChan(size)
+	if b.errChanSize > 0 {
+		size = b.errChanSize
+	}
+	if b.errAggWindow <= 0 {
+		return b.rawErrorChan(size)
+	}
+	b.aggErrOnce.Do(func() {
+		b.aggErrs = make(chan error, max(size, 1))
+		b.aggDone = make(chan struct{})
+		src := b.rawErrorChan(size)
+		go b.aggregateErrors(src, b.rawErrorsFinished(), b.aggErrs, b.errAggWindow)
+	})
+	return b.aggErrs
+}
+
+// rawErrorChan 返回未经聚合的错误通道（启用优先通道时合并两条管道）
+func (b *BatchFlow) rawErrorChan(size int) <-chan error {
+	if b.priorityLane == nil {
+		return b.pipeline.ErrorChan(size)
+	}
+	b.mergedErrOnce.Do(func() {
+		b.mergedErrs = make(chan error, max(size, 1))
+		b.mergedDone = make(chan struct{})
+		// 退出后仍在执行的异步批次会继续写入各自的错误通道：转发到 settled 为止，再转发已缓冲的错误
+		var forwarders sync.WaitGroup
+		forward := func(src <-chan error) {
+			defer forwarders.Done()
+			for {
+				select {
+				case err := <-src:
+					select {
+					case b.mergedErrs <- err:
+					case <-b.settled:
+						b.sendOrDrop(b.mergedErrs, err)
+					}
+				case <-b.settled:
+					for {
+						select {
+						case err := <-src:
+							b.sendOrDrop(b.mergedErrs, err)
+						default:
+							return
+						}
+					}
+				}
+			}
+		}
+		forwarders.Add(2)
+		go forward(b.pipeline.ErrorChan(size))
+		go forward(b.priorityLane.ErrorChan(size))
+		go func() {
+			forwarders.Wait()
+			close(b.mergedDone)
+		}()
+	})
+	return b.mergedErrs
+}
+
+// defaultOnErrorBufferSize OnError 首次初始化错误通道时使用的缓冲大小
+const defaultOnErrorBufferSize = 128
+
+// OnError 注册错误回调：内部启动一个协程消费 ErrorChan，对每个错误调用 fn；
+// BatchFlow 退出后继续消费，直到退出前已触发的异步批次全部完成并投递错误后停止。
+// 重复调用会替换回调（始终只有一个消费协程）；fn 为 nil 时忽略。
+// 注意：与直接读取 ErrorChan 共用同一通道，二者择一使用；fn 应尽快返回，避免错误通道积压后被丢弃。
+func (b *BatchFlow) OnError(fn func(error)) {
+	if fn == nil {
+		return
+	}
+	b.onError.Store(&fn)
+	b.onErrorOnce.Do(func() {
+		go b.consumeErrors(b.ErrorChan(defaultOnErrorBufferSize), b.errorsFinished())
+	})
+}
+
+// errorsFinished 返回 ErrorChan 不再有新错误写入时关闭的通道；须在 ErrorChan 初始化之后调用
+func (b *BatchFlow) errorsFinished() <-chan struct{} {
+	if b.errAggWindow > 0 {
+		return b.aggDone
+	}
+	return b.rawErrorsFinished()
+}
+
+// rawErrorsFinished 返回未聚合的错误通道不再有新错误写入时关闭的通道
+func (b *BatchFlow) rawErrorsFinished() <-chan struct{} {
+	if b.priorityLane != nil {
+		return b.mergedDone
+	}
+	return b.settled
+}
+
+// errorDelivered 由 MetricsHook.Error 调用：失败批次的错误已写入管道错误通道或被丢弃
+func (b *BatchFlow) errorDelivered() {
+	b.errDelivery.done(1)
+}
+
+func (b *BatchFlow) consumeErrors(errs <-chan error, finished <-chan struct{}) {
+	for {
+		select {
+		case err := <-errs:
+			b.dispatchError(err)
+		case <-finished:
+			// 退出前消费已缓冲的错误
+			for {
+				select {
+				case err := <-errs:
+					b.dispatchError(err)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *BatchFlow) dispatchError(err error) {
+	if err == nil {
+		return
+	}
+	if fn := b.onError.Load(); fn != nil {
+		(*fn)(err)
+	}
 }
 
 // Submit 提交请求到批量处理管道
 func (b *BatchFlow) Submit(ctx context.Context, request *Request) error {
 	// 优先尊重取消，避免 select 在多就绪时随机选择发送路径
 	if err := ctx.Err(); err != nil {
-		b.reportSubmitRejected(reasonFromContextErr(err))
+		b.reportSubmitRejected(submitRejectReason(ctx))
 		return err
 	}
 	// 若 BatchFlow 所属生命周期已结束（创建时的 ctx 已取消），直接拒绝提交
@@ -205,46 +598,255 @@ func (b *BatchFlow) Submit(ctx context.Context, request *Request) error {
 
 	if request == nil {
 		b.reportSubmitRejected("empty_request")
-		return ErrEmptyRequest
+		return &SchemaError{Reason: "request is nil", Err: ErrEmptyRequest}
 	}
 
 	schema := request.Schema()
 	if schema == nil {
 		b.reportSubmitRejected("invalid_schema")
-		return ErrInvalidSchema
+		return &SchemaError{Reason: "request has no schema", Err: ErrInvalidSchema}
 	}
 	if schema.Columns() == nil || len(schema.Columns()) == 0 {
 		b.reportSubmitRejected("missing_column")
-		return ErrMissingColumn
+		return &ColumnError{SchemaName: schema.Name(), Reason: "schema declares no columns", Err: ErrMissingColumn}
 	}
 	if len(schema.Name()) == 0 {
 		b.reportSubmitRejected("empty_schema_name")
-		return ErrEmptySchemaName
+		return &SchemaError{Reason: "schema name is empty", Err: ErrEmptySchemaName}
+	}
+	if err := request.checkUnknownColumns(); err != nil {
+		b.reportSubmitRejected("unknown_column")
+		return err
+	}
+	if err := request.checkDeleteSupported(); err != nil {
+		b.reportSubmitRejected("delete_unsupported")
+		return err
+	}
+	// 超长值在入队前拒绝，避免大值占用缓冲区直到 flush 才失败
+	if err := request.checkColumnMaxLengths(); err != nil {
+		b.reportSubmitRejected("value_too_long")
+		return err
+	}
+	if b.nonFinite == NonFiniteReject {
+		if err := request.checkNonFiniteFloats(); err != nil {
+			b.reportSubmitRejected("non_finite_float")
+			return err
+		}
+	}
+
+	// 与 Close 互斥：Close 等待在途的入队完成后才关闭数据通道
+	b.submitMu.RLock()
+	defer b.submitMu.RUnlock()
+	if b.closed.Load() {
+		b.reportSubmitRejected("batchflow_closed")
+		return context.Canceled
 	}
 
+	queued := &queuedRequest{request: request}
 	dataChan := b.pipeline.DataChan()
+	if b.priorityLane != nil && request.Priority() > 0 {
+		dataChan = b.priorityLane.DataChan()
+		queued.priority = true
+	}
 	enqueueStart := time.Now()
+	queued.enqueuedAt = enqueueStart
+	if err := b.admitPending(ctx); err != nil {
+		return err
+	}
+	tracked := b.maxBatchAge > 0 && !queued.priority
+	if tracked {
+		b.trackEnqueue(queued.enqueuedAt)
+	}
 
+	// 先尝试非阻塞入队；通道已满时进入阻塞等待，并单独统计阻塞时长（背压）
 	select {
-	case dataChan <- &queuedRequest{request: request, enqueuedAt: time.Now()}:
-		// 入队成功后记录入队耗时与队列长度
-		// 注意：len(dataChan) 是近似观测，仅用于指标参考
-		// 这里将耗时统计放在调用方路径内，默认 Noop 不引入开销
-		b.metricsReporter.ObserveEnqueueLatency(time.Since(enqueueStart))
-		b.metricsReporter.SetQueueLength(len(dataChan))
+	case dataChan <- queued:
+		b.observeEnqueued(dataChan, enqueueStart)
+		return nil
+	default:
+	}
+	blockedStart := time.Now()
+	select {
+	case dataChan <- queued:
+		b.observeSubmitBlocked(time.Since(blockedStart))
+		b.observeEnqueued(dataChan, enqueueStart)
 		return nil
 	case <-ctx.Done():
-		b.reportSubmitRejected(reasonFromContextErr(ctx.Err()))
+		b.observeSubmitBlocked(time.Since(blockedStart))
+		if tracked {
+			b.untrackEnqueue()
+		}
+		b.pending.done(1)
+		b.reportSubmitRejected(submitRejectReason(ctx))
 		return ctx.Err()
+	case <-b.done:
+		// 创建时的 ctx 已取消、管道退出后不再消费数据通道；不能一直阻塞，否则 Close 会在 submitMu 上永久等待
+		b.observeSubmitBlocked(time.Since(blockedStart))
+		if tracked {
+			b.untrackEnqueue()
+		}
+		b.pending.done(1)
+		b.reportSubmitRejected("batchflow_closed")
+		return context.Canceled
+	}
+}
+
+// observeEnqueued 入队成功后记录入队耗时与队列长度
+// 注意：len(dataChan) 是近似观测，仅用于指标参考
+// 这里将耗时统计放在调用方路径内，默认 Noop 不引入开销
+func (b *BatchFlow) observeEnqueued(dataChan chan<- *queuedRequest, enqueueStart time.Time) {
+	b.metricsReporter.ObserveEnqueueLatency(time.Since(enqueueStart))
+	queueLen := len(dataChan)
+	b.metricsReporter.SetQueueLength(queueLen)
+	b.observeQueueLength(queueLen)
+}
+
+func (b *BatchFlow) observeSubmitBlocked(d time.Duration) {
+	if sbr, ok := b.metricsReporter.(SubmitBlockedMetricsReporter); ok && sbr != nil {
+		sbr.ObserveSubmitBlocked(d)
+	}
+}
+
+// QueueHighWater 返回当前窗口内数据通道的最大观测长度（入队后采样，近似值），用于调优 BufferSize
+func (b *BatchFlow) QueueHighWater() int {
+	return int(b.queueHighWater.Load())
+}
+
+// ResetQueueHighWater 返回当前窗口的高水位并开始新窗口。
+// 若 MetricsReporter 实现了 QueueMetricsReporter，每次 flush 会自动调用本方法上报，窗口即两次 flush 之间。
+func (b *BatchFlow) ResetQueueHighWater() int {
+	return int(b.queueHighWater.Swap(0))
+}
+
+func (b *BatchFlow) observeQueueLength(n int) {
+	for {
+		cur := b.queueHighWater.Load()
+		if int64(n) <= cur || b.queueHighWater.CompareAndSwap(cur, int64(n)) {
+			return
+		}
+	}
+}
+
+// Pause 暂停批次执行：Submit 仍可继续入队，已入队与已取出等待执行的请求合计达到 BufferSize 后阻塞；
+// 已触发的 flush 在执行前等待。适用于数据库维护窗口。重复调用无副作用。
+// 创建时的 ctx 取消会解除暂停：开启 DrainOnCancel 时等待中的批次在 DrainGracePeriod 内执行，否则丢弃。
+func (b *BatchFlow) Pause() {
+	b.pauseMu.Lock()
+	defer b.pauseMu.Unlock()
+	if b.resumeCh == nil {
+		b.resumeCh = make(chan struct{})
+	}
+}
+
+// Resume 恢复批次执行，暂停期间积累的请求按正常节奏 flush。未暂停时无副作用。
+func (b *BatchFlow) Resume() {
+	b.pauseMu.Lock()
+	defer b.pauseMu.Unlock()
+	if b.resumeCh != nil {
+		close(b.resumeCh)
+		b.resumeCh = nil
+	}
+}
+
+// resumeOnCancel 在创建时的 ctx 取消后解除暂停，并记录取消时是否处于暂停状态
+func (b *BatchFlow) resumeOnCancel() {
+	b.pauseMu.Lock()
+	defer b.pauseMu.Unlock()
+	if b.resumeCh != nil {
+		b.cancelPaused = true
+		close(b.resumeCh)
+		b.resumeCh = nil
+	}
+}
+
+// Paused 返回当前是否处于暂停状态
+func (b *BatchFlow) Paused() bool {
+	return b.pauseChan() != nil
+}
+
+func (b *BatchFlow) pauseChan() <-chan struct{} {
+	b.pauseMu.Lock()
+	defer b.pauseMu.Unlock()
+	return b.resumeCh
+}
+
+// admitPending 为一次 Submit 计入待处理请求。暂停期间 go-pipeline 仍会从数据通道取出数据组批（批次在执行前等待），
+// 缓冲区不会写满，因此按待处理总数形成背压：达到 BufferSize 时阻塞，直到 Resume、有批次完成、ctx 结束或后台管道退出
+func (b *BatchFlow) admitPending(ctx context.Context) error {
+	var blockedStart time.Time
+	for {
+		resume := b.pauseChan()
+		if resume == nil {
+			b.pending.add(1)
+			break
+		}
+		freed, ok := b.pending.tryAdd(int(b.bufferSize))
+		if ok {
+			break
+		}
+		if blockedStart.IsZero() {
+			blockedStart = time.Now()
+		}
+		select {
+		case <-resume:
+		case <-freed:
+		case <-ctx.Done():
+			b.observeSubmitBlocked(time.Since(blockedStart))
+			b.reportSubmitRejected(submitRejectReason(ctx))
+			return ctx.Err()
+		case <-b.done:
+			b.observeSubmitBlocked(time.Since(blockedStart))
+			b.reportSubmitRejected("batchflow_closed")
+			return context.Canceled
+		}
+	}
+	if !blockedStart.IsZero() {
+		b.observeSubmitBlocked(time.Since(blockedStart))
+	}
+	return nil
+}
+
+// waitIfPaused 暂停时等待 Resume。等待期间（或取消时处于暂停、批次尚未进入等待）ctx 被取消时：开启 DrainOnCancel 则返回独立的限时收尾 ctx
+// （与 go-pipeline 取消时的收尾 flush 一致），否则返回 ctx 错误、丢弃该批次
+func (b *BatchFlow) waitIfPaused(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	b.pauseMu.Lock()
+	ch, cancelPaused := b.resumeCh, b.cancelPaused
+	b.pauseMu.Unlock()
+	if ch == nil && !cancelPaused {
+		return ctx, func() {}, nil
 	}
+	if ch != nil {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+		}
+	}
+	if ctx.Err() == nil {
+		return ctx, func() {}, nil
+	}
+	if !b.drainOnCancel {
+		return ctx, func() {}, ctx.Err()
+	}
+	drainCtx, cancel := context.WithTimeout(context.Background(), b.drainGrace)
+	return drainCtx, cancel, nil
 }
 
 // Close 停止接收新请求，触发最终 flush，并等待后台 pipeline 退出。
 // 它是幂等的；首次调用会关闭内部数据通道，后续调用仅等待同一个退出结果。
+// 若处于暂停状态，Close 会先 Resume，保证缓冲数据被排空。
+// 可与 Submit 并发调用（如由 DrainOnSignal 触发）：Close 等待在途的 Submit 入队完成后才关闭通道，之后的 Submit 返回 context.Canceled。
 func (b *BatchFlow) Close() error {
 	b.closeOnce.Do(func() {
+		b.drain.start()
+		b.Resume()
 		b.closed.Store(true)
+		// 先拒绝新提交，再等待在途 Submit 入队完成（管道仍在消费，阻塞的 Submit 终会返回）
+		b.submitMu.Lock()
 		close(b.pipeline.DataChan())
+		if b.priorityLane != nil {
+			close(b.priorityLane.DataChan())
+		}
+		b.submitMu.Unlock()
 	})
 	return b.Wait()
 }
@@ -282,11 +884,68 @@ type PipelineConfig struct {
 	// 可选通用观测配置（结构化日志、采样、脱敏、trace hook）
 	Observability ObservabilityConfig
 
+	// 可选诊断日志（重试、最终失败、错误丢弃等；零值=Noop）
+	Logger Logger
+
 	// 可选并发限制（零值=无限制，向后兼容）
 	ConcurrencyLimit int
 
 	// 可选批内合并/去重策略。SQL 默认仍使用 SQLOperationConfig 的 conflict-key 合并。
 	Coalescer Coalescer
+
+	// 可选优先通道 flush 间隔（零值=关闭）。启用后 Priority() > 0 的请求进入独立缓冲，
+	// 按该间隔 flush，不必等待主通道的 FlushInterval；两条通道共用 BufferSize/FlushSize。
+	PriorityFlushInterval time.Duration
+
+	// 可选列级加密（零值=关闭）。flush 组装行数据时，对列名命中的值（[]byte/string）调用对应函数，
+	// 以密文 []byte 交给驱动；nil 值保持 NULL。仅加密写入路径，读取解密不在此范围。
+	EncryptColumns map[string]ColumnEncryptFunc
+
+	// 可选列级压缩（零值=关闭）。flush 组装行数据时，将列名命中的值（[]byte/string）压缩为 []byte
+	// 后再绑定，nil 保持 NULL；与 EncryptColumns 同时命中时先压缩后加密。读取时需自行解压。
+	CompressColumns map[string]CompressionType
+
+	// 可选错误聚合窗口（零值=关闭）。启用后窗口内 Error() 文本相同的错误合并为一个 *AggregatedError
+	// 投递到 ErrorChan/OnError，窗口内只出现一次的错误原样投递，用于抑制故障期间的错误风暴。
+	ErrorAggregationWindow time.Duration
+
+	// 可选最大攒批时长（零值=关闭）。主通道中最早的请求等待超过该时长时强制 flush，
+	// 不必等到 FlushInterval；仅在小于 FlushInterval 时生效，不作用于优先通道。
+	MaxBatchAge time.Duration
+
+	// 可选单组上限（零值=不限）。一次 flush 中同一 schema 的行数或近似字节数超过上限时，
+	// 拆为多次顺序 ExecuteBatch，避免单个 schema 独占 flush 或生成超大语句。
+	// 字节数按值长度粗略估算（字符串/[]byte 按长度，数值按定长），单行超限时独占一次执行。
+	MaxGroupRows  int
+	MaxGroupBytes int
+
+	// 可选：按 schema 逻辑标识（名称 + 有序列名 + 冲突配置等，见 IdentifiableSchema）而非实例指针分组，
+	// 在多处分别构造的相同 schema 合并为同一批，减少语句数。默认 false，保持按指针分组。
+	GroupSchemasByIdentity bool
+
+	// 可选错误通道缓冲大小（零值=沿用旧行为：由首次 ErrorChan/OnError 调用的 size 决定，
+	// 若错误先于该调用到达则使用 go-pipeline 按 FlushSize/BufferSize 推算的默认值）。
+	// 设置后构造时即按该值创建错误通道，ErrorChan 的 size 参数被忽略。
+	ErrorChanSize int
+
+	// 可选最近错误记录条数（零值=关闭）。保留最近 N 条 flush 错误及时间、schema，
+	// 通过 BatchFlow.RecentErrors 读取，便于诊断端点展示。
+	RecentErrorsSize int
+
+	// 可选 NaN/±Inf 浮点值处理策略（零值=NonFinitePassThrough，原样交给执行器）。
+	// NonFiniteReject 在 Submit 时拒绝并返回 ErrNonFiniteFloat，NonFiniteCoerceToNull 在 flush 组装时写入 NULL。
+	NonFiniteFloats NonFiniteFloatPolicy
+
+	// 可选 FlushInterval 抖动比例（零值=关闭，取值 [0, 1)）。例如 0.1 表示每个实例构造时在
+	// FlushInterval 的 ±10% 内随机选定实际间隔，避免共享配置的多个副本同步 flush、集中冲击数据库。
+	FlushIntervalJitter float64
+
+	// 可选内存压力阈值（零值=关闭，单位字节）。按 MemoryPressureCheckInterval（默认 100ms）读取堆占用，
+	// 超过阈值时立即 flush 主通道，并在压力期间把 FlushSize 缩小为 1/4、以最短间隔持续 flush，
+	// 让缓冲数据尽快落库释放内存；回落到阈值以下后恢复。HeapAllocFunc 为空时使用 runtime.ReadMemStats 的 HeapAlloc。
+	MemoryPressureThreshold     uint64
+	MemoryPressureCheckInterval time.Duration
+	HeapAllocFunc               HeapAllocFunc
 }
 
 // BatchFlowConfig is the v2 constructor config for a fully assembled BatchFlow.
@@ -373,6 +1032,41 @@ func (c PipelineConfig) Validate() error {
 	if c.FinalFlushOnCloseTimeout < 0 {
 		return &ConfigError{Field: "FinalFlushOnCloseTimeout", Cause: errors.New("must be >= 0")}
 	}
+	if c.PriorityFlushInterval < 0 {
+		return &ConfigError{Field: "PriorityFlushInterval", Cause: errors.New("must be >= 0")}
+	}
+	if c.ErrorAggregationWindow < 0 {
+		return &ConfigError{Field: "ErrorAggregationWindow", Cause: errors.New("must be >= 0")}
+	}
+	if c.MaxBatchAge < 0 {
+		return &ConfigError{Field: "MaxBatchAge", Cause: errors.New("must be >= 0")}
+	}
+	if c.MaxGroupRows < 0 {
+		return &ConfigError{Field: "MaxGroupRows", Cause: errors.New("must be >= 0")}
+	}
+	if c.MaxGroupBytes < 0 {
+		return &ConfigError{Field: "MaxGroupBytes", Cause: errors.New("must be >= 0")}
+	}
+	for col, t := range c.CompressColumns {
+		if _, ok := lookupCompressor(t); !ok {
+			return &ConfigError{Field: "CompressColumns", Cause: fmt.Errorf("column %s: compression %q is not registered", col, t)}
+		}
+	}
+	if c.FlushIntervalJitter < 0 || c.FlushIntervalJitter >= 1 {
+		return &ConfigError{Field: "FlushIntervalJitter", Cause: errors.New("must be in [0, 1)")}
+	}
+	if c.ErrorChanSize < 0 {
+		return &ConfigError{Field: "ErrorChanSize", Cause: errors.New("must be >= 0")}
+	}
+	if c.NonFiniteFloats > NonFiniteCoerceToNull {
+		return &ConfigError{Field: "NonFiniteFloats", Cause: fmt.Errorf("unknown policy %d", c.NonFiniteFloats)}
+	}
+	if c.RecentErrorsSize < 0 {
+		return &ConfigError{Field: "RecentErrorsSize", Cause: errors.New("must be >= 0")}
+	}
+	if c.MemoryPressureCheckInterval < 0 {
+		return &ConfigError{Field: "MemoryPressureCheckInterval", Cause: errors.New("must be >= 0")}
+	}
 	return nil
 }
 
@@ -415,6 +1109,9 @@ func NewSQLBatchFlowWithDriver(ctx context.Context, db *sql.DB, config PipelineC
 	if observer := config.Observability.observer(); observer != nil {
 		executor.WithObserver(observer)
 	}
+	if config.Logger != nil {
+		executor.WithLogger(config.Logger)
+	}
 	if config.ConcurrencyLimit > 0 {
 		executor.WithConcurrencyLimit(config.ConcurrencyLimit)
 	}
@@ -429,8 +1126,12 @@ func NewSQLBatchFlowWithDriver(ctx context.Context, db *sql.DB, config PipelineC
 内部架构：BatchFlow -> ThrottledBatchExecutor -> SQLBatchProcessor -> MySQLDriver -> MySQL
 */
 // 这是推荐的使用方式，使用MySQL优化的默认配置
-func NewMySQLBatchFlow(ctx context.Context, db *sql.DB, config PipelineConfig) *BatchFlow {
-	return NewSQLBatchFlowWithDriver(ctx, db, config, DefaultMySQLDriver)
+// opts 配置驱动（版本、行别名、标识符引用等），不传时使用 DefaultMySQLDriver，行为与以往一致。
+func NewMySQLBatchFlow(ctx context.Context, db *sql.DB, config PipelineConfig, opts ...MySQLOption) *BatchFlow {
+	if len(opts) == 0 {
+		return NewSQLBatchFlowWithDriver(ctx, db, config, DefaultMySQLDriver)
+	}
+	return NewSQLBatchFlowWithDriver(ctx, db, config, NewMySQLDriver(opts...))
 }
 
 // NewPostgreSQLBatchFlow 创建PostgreSQL BatchFlow实例（使用默认Driver）
@@ -439,10 +1140,22 @@ func NewPostgreSQLBatchFlow(ctx context.Context, db *sql.DB, config PipelineConf
 }
 
 // NewSQLiteBatchFlow 创建SQLite BatchFlow实例（使用默认Driver）
+// SQLite 同一时刻只允许一个写事务：未显式设置 ConcurrencyLimit 时默认取 1，
+// 让批次串行执行，避免并发 ExecuteBatch 触发 SQLITE_BUSY。
+// 偶发的 SQLITE_BUSY 会被默认分类器判为可重试，启用 Retry 后按退避重试。
 func NewSQLiteBatchFlow(ctx context.Context, db *sql.DB, config PipelineConfig) *BatchFlow {
+	if config.ConcurrencyLimit == 0 {
+		config.ConcurrencyLimit = 1
+	}
 	return NewSQLBatchFlowWithDriver(ctx, db, config, DefaultSQLiteDriver)
 }
 
+// NewDuckDBBatchFlow 创建DuckDB BatchFlow实例（使用默认Driver）
+// db 由调用方通过 DuckDB 的 database/sql 驱动（如 github.com/marcboeker/go-duckdb）打开；冲突策略限制见 DuckDBDriver。
+func NewDuckDBBatchFlow(ctx context.Context, db *sql.DB, config PipelineConfig) *BatchFlow {
+	return NewSQLBatchFlowWithDriver(ctx, db, config, DefaultDuckDBDriver)
+}
+
 // NewRedisBatchFlow 创建Redis BatchFlow实例
 /*
 内部架构（NoSQL）：BatchFlow -> ThrottledBatchExecutor -> RedisBatchProcessor -> RedisDriver -> Redis
@@ -467,6 +1180,9 @@ func NewRedisBatchFlowWithDriver(ctx context.Context, db *redisV9.Client, config
 	if observer := config.Observability.observer(); observer != nil {
 		executor.WithObserver(observer)
 	}
+	if config.Logger != nil {
+		executor.WithLogger(config.Logger)
+	}
 	if config.ConcurrencyLimit > 0 {
 		executor.WithConcurrencyLimit(config.ConcurrencyLimit)
 	}
@@ -517,6 +1233,14 @@ func (b *BatchFlow) reportSubmitRejected(reason string) {
 	}
 }
 
+// submitRejectReason 返回 Submit 因 ctx 结束被拒绝的原因；SubmitWithTimeout 的超时表示缓冲区持续满载
+func submitRejectReason(ctx context.Context) string {
+	if errors.Is(context.Cause(ctx), ErrSubmitTimeout) {
+		return "buffer_full"
+	}
+	return reasonFromContextErr(ctx.Err())
+}
+
 func reasonFromContextErr(err error) string {
 	switch {
 	case errors.Is(err, context.DeadlineExceeded):
@@ -0,0 +1,88 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// schemaFailExecutor 对指定 schema 返回错误，其余成功
+type schemaFailExecutor struct {
+	failSchema string
+	err        error
+}
+
+func (e schemaFailExecutor) ExecuteBatch(_ context.Context, schema batchflow.SchemaInterface, _ []map[string]any) error {
+	if schema.Name() == e.failSchema {
+		return e.err
+	}
+	return nil
+}
+
+func TestBatchFlow_WithAfterFlushReportsRowCountAndError(t *testing.T) {
+	errBoom := errors.New("boom")
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 5, FlushInterval: time.Hour},
+		Executor: schemaFailExecutor{failSchema: "orders", err: errBoom},
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+
+	type call struct {
+		schema string
+		rows   int
+		err    error
+	}
+	var (
+		mu    sync.Mutex
+		calls []call
+	)
+	b.WithAfterFlush(func(_ context.Context, schema batchflow.SchemaInterface, rowCount int, err error) {
+		mu.Lock()
+		calls = append(calls, call{schema: schema.Name(), rows: rowCount, err: err})
+		mu.Unlock()
+	})
+
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	orders := batchflow.NewSQLSchema("orders", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 3; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(users).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(orders).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		n := len(calls)
+		mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 after-flush calls, got %d", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls[0].schema != "users" || calls[0].rows != 3 || calls[0].err != nil {
+		t.Fatalf("unexpected success call: %+v", calls[0])
+	}
+	if calls[1].schema != "orders" || calls[1].rows != 2 || !errors.Is(calls[1].err, errBoom) {
+		t.Fatalf("unexpected failure call: %+v", calls[1])
+	}
+}
@@ -0,0 +1,71 @@
+package batchflow_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type batchBytesMetrics struct {
+	batchflow.NoopMetricsReporter
+
+	mu    sync.Mutex
+	bytes []int
+}
+
+func (m *batchBytesMetrics) ObserveBatchBytes(n int) {
+	m.mu.Lock()
+	m.bytes = append(m.bytes, n)
+	m.mu.Unlock()
+}
+
+func (m *batchBytesMetrics) snapshot() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]int(nil), m.bytes...)
+}
+
+// flushBatchBytes 提交一批 name 长度为 nameLen 的请求，返回上报的批次字节数
+func flushBatchBytes(t *testing.T, nameLen int) int {
+	t.Helper()
+	reporter := &batchBytesMetrics{}
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 100, FlushSize: 100, FlushInterval: time.Hour},
+		Executor: batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	for i := 0; i < 10; i++ {
+		req := batchflow.NewRequest(schema).SetInt64("id", int64(i)).SetString("name", strings.Repeat("x", nameLen))
+		if err := b.Submit(ctx, req); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	got := reporter.snapshot()
+	if len(got) != 1 {
+		t.Fatalf("expected one batch observation, got %v", got)
+	}
+	return got[0]
+}
+
+func TestBatchFlow_ObserveBatchBytesGrowsWithValues(t *testing.T) {
+	small := flushBatchBytes(t, 10)
+	large := flushBatchBytes(t, 1000)
+	// 10 行：列名 "id"+"name" 共 6 字节，int64 8 字节，加上字符串长度
+	if want := 10 * (6 + 8 + 10); small != want {
+		t.Fatalf("small batch: got %d bytes, want %d", small, want)
+	}
+	if large-small != 10*990 {
+		t.Fatalf("expected bytes to grow by the string length delta, small=%d large=%d", small, large)
+	}
+}
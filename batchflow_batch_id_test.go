@@ -0,0 +1,87 @@
+package batchflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type batchIDCall struct {
+	schema string
+	id     string
+	ok     bool
+}
+
+// batchIDRecorder 记录每次 ExecuteBatch 收到的批次 ID
+type batchIDRecorder struct {
+	mu    sync.Mutex
+	calls []batchIDCall
+}
+
+func (r *batchIDRecorder) ExecuteBatch(ctx context.Context, schema batchflow.SchemaInterface, _ []map[string]any) error {
+	id, ok := batchflow.BatchIDFromContext(ctx)
+	r.mu.Lock()
+	r.calls = append(r.calls, batchIDCall{schema: schema.Name(), id: id, ok: ok})
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *batchIDRecorder) snapshot() []batchIDCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]batchIDCall(nil), r.calls...)
+}
+
+func TestBatchFlow_BatchIDInExecutorContext(t *testing.T) {
+	ctx := context.Background()
+	recorder := &batchIDRecorder{}
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 100, FlushSize: 100, FlushInterval: time.Hour, MaxGroupRows: 2},
+		Executor: recorder,
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	orders := batchflow.NewSQLSchema("orders", batchflow.ConflictIgnoreOperationConfig, "id")
+	// 单次 flush：users 6 行拆为 3 次执行，orders 2 行 1 次执行
+	for i := 0; i < 6; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(users).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(orders).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	calls := recorder.snapshot()
+	if len(calls) != 4 {
+		t.Fatalf("expected 4 executions, got %d", len(calls))
+	}
+	ids := map[string]string{}
+	for _, call := range calls {
+		if !call.ok || call.id == "" {
+			t.Fatalf("batch ID missing from executor ctx: %+v", call)
+		}
+		if prev, seen := ids[call.schema]; seen && prev != call.id {
+			t.Fatalf("batch ID changed within %s group: %s != %s", call.schema, prev, call.id)
+		}
+		ids[call.schema] = call.id
+	}
+	if ids["users"] == ids["orders"] {
+		t.Fatalf("schema groups should get distinct batch IDs, both %s", ids["users"])
+	}
+
+	if _, ok := batchflow.BatchIDFromContext(context.Background()); ok {
+		t.Fatalf("plain context should carry no batch ID")
+	}
+}
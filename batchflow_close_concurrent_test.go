@@ -0,0 +1,113 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestClose_ConcurrentSubmit(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    4,
+		FlushSize:     8,
+		FlushInterval: time.Hour,
+	})
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	var accepted atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", g*100+i))
+				if err == nil {
+					accepted.Add(1)
+					continue
+				}
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("unexpected submit error: %v", err)
+				}
+				return
+			}
+		}()
+	}
+	time.Sleep(time.Millisecond)
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	wg.Wait()
+
+	// Close 不等待异步 flush 完成，轮询执行结果
+	deadline := time.Now().Add(5 * time.Second)
+	for int64(len(flattenBatches(mock))) != accepted.Load() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected every accepted request to be flushed, accepted=%d flushed=%d", accepted.Load(), len(flattenBatches(mock)))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSubmit_BlockedSubmitReturnsWhenPipelineExits(t *testing.T) {
+	processor := &gateProcessor{release: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// 单个 flush 槽被占用时 performer 停止读取数据通道，缓冲写满后 Submit 阻塞
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:           1,
+			FlushSize:            1,
+			FlushInterval:        time.Hour,
+			MaxConcurrentFlushes: 1,
+		},
+		Executor: batchflow.NewThrottledBatchExecutor(processor),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 0)); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	waitForRunning(t, processor, 1)
+
+	// 这些 Submit 使用不会取消的 ctx，只能依靠 BatchFlow 退出来唤醒
+	const blocked = 6
+	results := make(chan error, blocked)
+	for i := 1; i <= blocked; i++ {
+		go func() {
+			results <- b.Submit(context.Background(), batchflow.NewRequest(schema).SetInt("id", i))
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	timeout := time.After(3 * time.Second)
+	for i := 0; i < blocked; i++ {
+		select {
+		case err := <-results:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected nil or context.Canceled, got %v", err)
+			}
+		case <-timeout:
+			t.Fatal("Submit stayed blocked after the pipeline exited")
+		}
+	}
+	closed := make(chan struct{})
+	go func() {
+		_ = b.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Close hung behind a blocked Submit")
+	}
+}
@@ -0,0 +1,110 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_CloseWithResultReportsPerSchemaOutcomes(t *testing.T) {
+	errBoom := errors.New("boom")
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 100, FlushSize: 100, FlushInterval: time.Hour},
+		Executor: schemaFailExecutor{failSchema: "orders", err: errBoom},
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	orders := batchflow.NewSQLSchema("orders", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 3; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(users).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(orders).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	result, _ := b.CloseWithResult()
+	if len(result.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", result.Groups)
+	}
+	byName := map[string]batchflow.GroupResult{}
+	for _, g := range result.Groups {
+		byName[g.SchemaName] = g
+	}
+	if g := byName["users"]; g.RowCount != 3 || g.Err != nil {
+		t.Fatalf("unexpected users result: %+v", g)
+	}
+	if g := byName["orders"]; g.RowCount != 2 || !errors.Is(g.Err, errBoom) {
+		t.Fatalf("unexpected orders result: %+v", g)
+	}
+	if !errors.Is(result.Err(), errBoom) {
+		t.Fatalf("FlushResult.Err should include the failed group, got %v", result.Err())
+	}
+
+	// 重复调用返回同一结果
+	again, _ := b.CloseWithResult()
+	if len(again.Groups) != 2 {
+		t.Fatalf("repeated CloseWithResult should return the same groups, got %+v", again.Groups)
+	}
+}
+
+func TestFlushResult_ErrNilWhenAllGroupsSucceed(t *testing.T) {
+	result := batchflow.FlushResult{Groups: []batchflow.GroupResult{{SchemaName: "users", RowCount: 1}}}
+	if err := result.Err(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+// sizeDelayExecutor 行数为 slowSize 的批次延迟 delay 后成功，其余立即成功
+type sizeDelayExecutor struct {
+	slowSize int
+	delay    time.Duration
+}
+
+func (e sizeDelayExecutor) ExecuteBatch(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) error {
+	if len(data) == e.slowSize {
+		time.Sleep(e.delay)
+	}
+	return nil
+}
+
+func TestBatchFlow_CloseWithResultWaitsForInflightFullFlushes(t *testing.T) {
+	ctx := context.Background()
+	// 满批（2 行）异步执行且较慢，关闭时的最终 flush（1 行）很快完成
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 16, FlushSize: 2, FlushInterval: time.Hour},
+		Executor: sizeDelayExecutor{slowSize: 2, delay: 200 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 5; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(users).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	result, err := b.CloseWithResult()
+	if err != nil {
+		t.Fatalf("CloseWithResult failed: %v", err)
+	}
+	rows := 0
+	for _, g := range result.Groups {
+		rows += g.RowCount
+	}
+	if len(result.Groups) != 3 || rows != 5 {
+		t.Fatalf("expected 3 groups covering 5 rows, got %+v", result.Groups)
+	}
+}
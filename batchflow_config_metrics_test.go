@@ -0,0 +1,49 @@
+package batchflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type configMetrics struct {
+	batchflow.NoopMetricsReporter
+
+	calls            int
+	bufferSize       uint32
+	flushSize        uint32
+	flushInterval    time.Duration
+	concurrencyLimit int
+}
+
+func (m *configMetrics) SetConfig(bufferSize, flushSize uint32, flushInterval time.Duration, concurrencyLimit int) {
+	m.calls++
+	m.bufferSize, m.flushSize, m.flushInterval, m.concurrencyLimit = bufferSize, flushSize, flushInterval, concurrencyLimit
+}
+
+func TestBatchFlow_SetConfigReportedOnConstruction(t *testing.T) {
+	reporter := &configMetrics{}
+	b, err := batchflow.NewBatchFlowWithConfig(context.Background(), batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:       128,
+			FlushSize:        32,
+			FlushInterval:    250 * time.Millisecond,
+			ConcurrencyLimit: 4,
+		},
+		Executor: batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+
+	if reporter.calls != 1 {
+		t.Fatalf("expected SetConfig to be called once, got %d", reporter.calls)
+	}
+	if reporter.bufferSize != 128 || reporter.flushSize != 32 ||
+		reporter.flushInterval != 250*time.Millisecond || reporter.concurrencyLimit != 4 {
+		t.Fatalf("unexpected config: %+v", *reporter)
+	}
+}
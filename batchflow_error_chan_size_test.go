@@ -0,0 +1,54 @@
+package batchflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_ErrorChanSizeHonoredBeforeFirstErrorChanCall(t *testing.T) {
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:           64,
+			FlushSize:            1,
+			FlushInterval:        time.Hour,
+			MaxConcurrentFlushes: 1,
+			ErrorChanSize:        8,
+		},
+		Executor: batchflow.NewThrottledBatchExecutor(nonRetryProcessor{}),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+
+	// 默认容量按 FlushSize/BufferSize 推算为 1，这里先产生 5 个错误再获取通道
+	schema := batchflow.NewSchema("events", "id")
+	for i := 0; i < 5; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	errs := b.ErrorChan(1)
+	if c := cap(errs); c != 8 {
+		t.Fatalf("expected error channel capacity 8, got %d", c)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(errs) != 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 5 buffered errors, got %d", len(errs))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPipelineConfig_ValidateRejectsNegativeErrorChanSize(t *testing.T) {
+	if err := (batchflow.PipelineConfig{ErrorChanSize: -1}).Validate(); err == nil {
+		t.Fatal("expected negative ErrorChanSize to be rejected")
+	}
+}
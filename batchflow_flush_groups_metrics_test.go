@@ -0,0 +1,64 @@
+package batchflow_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type flushGroupsMetrics struct {
+	batchflow.NoopMetricsReporter
+
+	mu     sync.Mutex
+	counts []int
+	sizes  [][]int
+}
+
+func (m *flushGroupsMetrics) ObserveFlushGroups(groupCount int, sizes []int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts = append(m.counts, groupCount)
+	m.sizes = append(m.sizes, append([]int(nil), sizes...))
+}
+
+func TestBatchFlow_ObserveFlushGroups(t *testing.T) {
+	ctx := context.Background()
+	reporter := &flushGroupsMetrics{}
+	exec := batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter)
+	b := batchflow.NewBatchFlow(ctx, 32, 100, time.Hour, exec)
+
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	orders := batchflow.NewSQLSchema("orders", batchflow.ConflictIgnoreOperationConfig, "id")
+	logs := batchflow.NewSQLSchema("logs", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	submit := func(schema *batchflow.SQLSchema, n int) {
+		for i := 0; i < n; i++ {
+			if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+				t.Fatalf("submit failed: %v", err)
+			}
+		}
+	}
+	submit(users, 2)
+	submit(orders, 3)
+	submit(logs, 1)
+
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if len(reporter.counts) != 1 {
+		t.Fatalf("expected one flush observation, got %d", len(reporter.counts))
+	}
+	if reporter.counts[0] != 3 {
+		t.Fatalf("expected 3 schema groups, got %d", reporter.counts[0])
+	}
+	if !reflect.DeepEqual(reporter.sizes[0], []int{2, 3, 1}) {
+		t.Fatalf("unexpected group sizes: %v", reporter.sizes[0])
+	}
+}
@@ -0,0 +1,56 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_FlushIntervalJitterStaysWithinBounds(t *testing.T) {
+	ctx := context.Background()
+	const interval = time.Second
+	lower, upper := 800*time.Millisecond, 1200*time.Millisecond
+
+	distinct := make(map[time.Duration]struct{})
+	for i := 0; i < 200; i++ {
+		b, _ := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+			BufferSize:          10,
+			FlushSize:           10,
+			FlushInterval:       interval,
+			FlushIntervalJitter: 0.2,
+		})
+		got := b.EffectiveFlushInterval()
+		_ = b.Close()
+		if got < lower || got > upper {
+			t.Fatalf("effective interval %v outside [%v, %v]", got, lower, upper)
+		}
+		distinct[got] = struct{}{}
+	}
+	if len(distinct) < 100 {
+		t.Fatalf("expected jitter to spread intervals, got %d distinct values", len(distinct))
+	}
+}
+
+func TestBatchFlow_FlushIntervalWithoutJitterIsUnchanged(t *testing.T) {
+	b, _ := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{
+		BufferSize:    10,
+		FlushSize:     10,
+		FlushInterval: 250 * time.Millisecond,
+	})
+	defer b.Close()
+	if got := b.EffectiveFlushInterval(); got != 250*time.Millisecond {
+		t.Fatalf("expected 250ms, got %v", got)
+	}
+}
+
+func TestPipelineConfig_FlushIntervalJitterValidation(t *testing.T) {
+	config := batchflow.DefaultPipelineConfig()
+	config.FlushIntervalJitter = 1
+	var configErr *batchflow.ConfigError
+	if err := config.Validate(); !errors.As(err, &configErr) || configErr.Field != "FlushIntervalJitter" {
+		t.Fatalf("expected FlushIntervalJitter ConfigError, got %v", err)
+	}
+}
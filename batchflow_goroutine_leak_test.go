@@ -0,0 +1,63 @@
+package batchflow_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_CloseReleasesGoroutines(t *testing.T) {
+	ctx := context.Background() // 从不取消：只依赖 Close 结束生命周期
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	cycle := func() {
+		b, _ := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+			BufferSize:    16,
+			FlushSize:     4,
+			FlushInterval: 10 * time.Millisecond,
+			MaxBatchAge:   5 * time.Millisecond,
+		})
+		b.OnError(func(error) {})
+		for i := 0; i < 3; i++ {
+			_ = b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i))
+		}
+		if err := b.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+	}
+
+	cycle() // 预热：排除首次创建时的惰性全局协程
+	settle := func() int {
+		var n int
+		for i := 0; i < 50; i++ {
+			runtime.GC()
+			n = runtime.NumGoroutine()
+			time.Sleep(10 * time.Millisecond)
+			if runtime.NumGoroutine() == n {
+				break
+			}
+		}
+		return n
+	}
+	before := settle()
+
+	const flows = 200
+	for i := 0; i < flows; i++ {
+		cycle()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		after := settle()
+		if after-before <= 5 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines grew from %d to %d after %d create/close cycles", before, after, flows)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,75 @@
+package batchflow_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_MaxGroupRowsSplitsExecutions(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    2000,
+		FlushSize:     1000,
+		FlushInterval: time.Hour,
+		MaxGroupRows:  100,
+	})
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 1000; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 1000 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 1000 rows executed, got %d", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	batches := mock.SnapshotExecutedBatches()
+	if len(batches) != 10 {
+		t.Fatalf("expected 10 executions, got %d", len(batches))
+	}
+	for i, batch := range batches {
+		if len(batch) != 100 {
+			t.Fatalf("execution %d: expected 100 rows, got %d", i, len(batch))
+		}
+	}
+}
+
+func TestBatchFlow_MaxGroupBytesSplitsExecutions(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    16,
+		FlushSize:     4,
+		FlushInterval: time.Hour,
+		MaxGroupBytes: 2048,
+	})
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("blobs", batchflow.ConflictIgnoreOperationConfig, "payload")
+	payload := strings.Repeat("x", 1500)
+	for i := 0; i < 4; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetString("payload", payload)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 4 rows executed, got %d", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := len(mock.SnapshotExecutedBatches()); n != 4 {
+		t.Fatalf("expected each oversized row in its own execution, got %d executions", n)
+	}
+}
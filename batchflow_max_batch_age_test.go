@@ -0,0 +1,47 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_MaxBatchAgeFlushesLoneRequest(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    16,
+		FlushSize:     16,
+		FlushInterval: time.Hour,
+		MaxBatchAge:   50 * time.Millisecond,
+	})
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for round := 1; round <= 2; round++ {
+		start := time.Now()
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", round)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+		deadline := start.Add(time.Second)
+		for executedRows(mock) != round {
+			if time.Now().After(deadline) {
+				t.Fatalf("round %d: expected %d rows within MaxBatchAge, got %d", round, round, executedRows(mock))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+			t.Fatalf("round %d: flushed after %v, earlier than MaxBatchAge", round, elapsed)
+		}
+	}
+}
+
+func TestPipelineConfig_ValidateRejectsNegativeMaxBatchAge(t *testing.T) {
+	err := batchflow.PipelineConfig{MaxBatchAge: -time.Second}.Validate()
+	var cfgErr *batchflow.ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "MaxBatchAge" {
+		t.Fatalf("expected MaxBatchAge ConfigError, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_MemoryPressureForcesFlush(t *testing.T) {
+	ctx := context.Background()
+	const threshold = 64 << 20
+	var heap atomic.Uint64
+	heap.Store(threshold / 2)
+
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:                  1000,
+		FlushSize:                   100,
+		FlushInterval:               time.Hour,
+		MemoryPressureThreshold:     threshold,
+		MemoryPressureCheckInterval: 5 * time.Millisecond,
+		HeapAllocFunc:               heap.Load,
+	})
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	submit := func(from, to int) {
+		for i := from; i < to; i++ {
+			if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+				t.Fatalf("submit failed: %v", err)
+			}
+		}
+	}
+	waitRows := func(want int) {
+		deadline := time.Now().Add(2 * time.Second)
+		for executedRows(mock) < want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d rows flushed, got %d", want, executedRows(mock))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// 低于阈值：未满 FlushSize 且 FlushInterval 为 1h，不应 flush
+	submit(0, 10)
+	time.Sleep(50 * time.Millisecond)
+	if got := executedRows(mock); got != 0 || b.UnderMemoryPressure() {
+		t.Fatalf("no flush expected below threshold, got %d rows (pressure=%v)", got, b.UnderMemoryPressure())
+	}
+
+	// 越过阈值：立即 flush 已缓冲的请求，压力期间新请求也持续落库
+	heap.Store(threshold + 1)
+	waitRows(10)
+	if !b.UnderMemoryPressure() {
+		t.Fatalf("expected memory pressure to be reported")
+	}
+	submit(10, 20)
+	waitRows(20)
+
+	// 回落：恢复配置的 FlushInterval，不再强制 flush
+	heap.Store(threshold / 2)
+	deadline := time.Now().Add(2 * time.Second)
+	for b.UnderMemoryPressure() {
+		if time.Now().After(deadline) {
+			t.Fatalf("memory pressure not cleared")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond) // 等待已触发的强制 tick 结束
+	before := executedRows(mock)
+	submit(20, 25)
+	time.Sleep(50 * time.Millisecond)
+	if got := executedRows(mock); got != before {
+		t.Fatalf("expected no forced flush after pressure cleared, got %d rows (was %d)", got, before)
+	}
+}
+
+func TestPipelineConfig_MemoryPressureCheckIntervalValidation(t *testing.T) {
+	err := batchflow.PipelineConfig{MemoryPressureCheckInterval: -time.Second}.Validate()
+	var cfgErr *batchflow.ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "MemoryPressureCheckInterval" {
+		t.Fatalf("expected ConfigError for MemoryPressureCheckInterval, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func nonFiniteValues() map[string]float64 {
+	return map[string]float64{"NaN": math.NaN(), "+Inf": math.Inf(1), "-Inf": math.Inf(-1)}
+}
+
+func flattenBatches(mock *batchflow.MockExecutor) []map[string]any {
+	var rows []map[string]any
+	for _, batch := range mock.SnapshotExecutedBatches() {
+		rows = append(rows, batch...)
+	}
+	return rows
+}
+
+func newNonFiniteFlow(t *testing.T, policy batchflow.NonFiniteFloatPolicy) (*batchflow.BatchFlow, *batchflow.MockExecutor) {
+	t.Helper()
+	b, mock := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{
+		BufferSize:      10,
+		FlushSize:       10,
+		FlushInterval:   time.Hour,
+		NonFiniteFloats: policy,
+	})
+	return b, mock
+}
+
+func TestNonFiniteFloats_PassThroughByDefault(t *testing.T) {
+	ctx := context.Background()
+	schema := batchflow.NewSQLSchema("metrics", batchflow.ConflictIgnoreOperationConfig, "id", "value")
+	for name, v := range nonFiniteValues() {
+		t.Run(name, func(t *testing.T) {
+			b, mock := newNonFiniteFlow(t, batchflow.NonFinitePassThrough)
+			if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 1).SetFloat64("value", v)); err != nil {
+				t.Fatalf("submit failed: %v", err)
+			}
+			b.Close()
+			rows := flattenBatches(mock)
+			if len(rows) != 1 {
+				t.Fatalf("expected 1 row, got %d", len(rows))
+			}
+			got, ok := rows[0]["value"].(float64)
+			if !ok || (math.IsNaN(v) != math.IsNaN(got)) || (!math.IsNaN(v) && got != v) {
+				t.Fatalf("expected %v to pass through, got %v", v, rows[0]["value"])
+			}
+		})
+	}
+}
+
+func TestNonFiniteFloats_RejectAtSubmit(t *testing.T) {
+	ctx := context.Background()
+	schema := batchflow.NewSQLSchema("metrics", batchflow.ConflictIgnoreOperationConfig, "id", "value", "ratio")
+	for name, v := range nonFiniteValues() {
+		t.Run(name, func(t *testing.T) {
+			b, mock := newNonFiniteFlow(t, batchflow.NonFiniteReject)
+			err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 1).SetFloat64("value", 1.5).SetFloat32("ratio", float32(v)))
+			if !errors.Is(err, batchflow.ErrNonFiniteFloat) {
+				t.Fatalf("expected ErrNonFiniteFloat, got %v", err)
+			}
+			var colErr *batchflow.ColumnError
+			if !errors.As(err, &colErr) || colErr.Column != "ratio" || colErr.SchemaName != "metrics" {
+				t.Fatalf("expected ColumnError for metrics.ratio, got %#v", err)
+			}
+			if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 2).SetFloat64("value", 2.5).SetFloat32("ratio", 0.5)); err != nil {
+				t.Fatalf("finite submit failed: %v", err)
+			}
+			b.Close()
+			if rows := flattenBatches(mock); len(rows) != 1 || rows[0]["id"] != 2 {
+				t.Fatalf("expected only the finite row, got %v", rows)
+			}
+		})
+	}
+}
+
+func TestNonFiniteFloats_CoerceToNull(t *testing.T) {
+	ctx := context.Background()
+	schema := batchflow.NewSQLSchema("metrics", batchflow.ConflictIgnoreOperationConfig, "id", "value", "ratio")
+	for name, v := range nonFiniteValues() {
+		t.Run(name, func(t *testing.T) {
+			b, mock := newNonFiniteFlow(t, batchflow.NonFiniteCoerceToNull)
+			if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 1).SetFloat64("value", v).SetFloat32("ratio", 0.25)); err != nil {
+				t.Fatalf("submit failed: %v", err)
+			}
+			b.Close()
+			rows := flattenBatches(mock)
+			if len(rows) != 1 {
+				t.Fatalf("expected 1 row, got %d", len(rows))
+			}
+			if rows[0]["value"] != nil {
+				t.Fatalf("expected %s coerced to NULL, got %v", name, rows[0]["value"])
+			}
+			if rows[0]["ratio"] != float32(0.25) {
+				t.Fatalf("expected finite value untouched, got %v", rows[0]["ratio"])
+			}
+		})
+	}
+}
+
+func TestPipelineConfig_UnknownNonFinitePolicy(t *testing.T) {
+	err := batchflow.PipelineConfig{BufferSize: 1, FlushSize: 1, FlushInterval: time.Hour, NonFiniteFloats: 9}.Validate()
+	var cfgErr *batchflow.ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "NonFiniteFloats" {
+		t.Fatalf("expected ConfigError for NonFiniteFloats, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_OnErrorReceivesExecutorErrors(t *testing.T) {
+	ctx := context.Background()
+	exec := batchflow.NewThrottledBatchExecutor(nonRetryProcessor{})
+	b := batchflow.NewBatchFlow(ctx, 16, 1, time.Hour, exec)
+
+	var mu sync.Mutex
+	var got []error
+	b.OnError(func(err error) {
+		mu.Lock()
+		got = append(got, err)
+		mu.Unlock()
+	})
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 3; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		mu.Lock()
+		n := len(got)
+		mu.Unlock()
+		if n == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 3 errors via OnError, got %d", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, err := range got {
+		if !strings.Contains(err.Error(), "syntax error") {
+			t.Fatalf("unexpected error delivered: %v", err)
+		}
+	}
+}
+
+func TestBatchFlow_OnErrorNilIgnored(t *testing.T) {
+	b, _ := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{
+		BufferSize:    8,
+		FlushSize:     8,
+		FlushInterval: time.Hour,
+	})
+	b.OnError(nil)
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+}
+
+// slowFailExecutor 延迟后返回失败，用于模拟 Close 返回后才结束的异步批次
+type slowFailExecutor struct{ delay time.Duration }
+
+func (e slowFailExecutor) ExecuteBatch(context.Context, batchflow.SchemaInterface, []map[string]any) error {
+	time.Sleep(e.delay)
+	return errors.New("slow batch failed")
+}
+
+// waitErrorCount 轮询直到 count 返回 want，超时失败
+func waitErrorCount(t *testing.T, count func() int, want int) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for count() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d errors, got %d", want, count())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchFlow_OnErrorReceivesErrorsFromFlushesFinishingAfterClose(t *testing.T) {
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 16, FlushSize: 2, FlushInterval: time.Hour},
+		Executor: slowFailExecutor{delay: 200 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	var mu sync.Mutex
+	var got []error
+	b.OnError(func(err error) {
+		mu.Lock()
+		got = append(got, err)
+		mu.Unlock()
+	})
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 4; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	// 两个满批仍在异步执行时 Close 即返回
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	waitErrorCount(t, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got)
+	}, 2)
+}
@@ -0,0 +1,69 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// panickyExecutor 第一次执行时 panic，之后正常计数
+type panickyExecutor struct {
+	calls atomic.Int32
+	rows  atomic.Int32
+}
+
+func (e *panickyExecutor) ExecuteBatch(_ context.Context, _ batchflow.SchemaInterface, data []map[string]any) error {
+	if e.calls.Add(1) == 1 {
+		panic("driver exploded")
+	}
+	e.rows.Add(int32(len(data)))
+	return nil
+}
+
+func TestBatchFlow_RecoversExecutorPanic(t *testing.T) {
+	ctx := context.Background()
+	executor := &panickyExecutor{}
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 1, FlushInterval: time.Hour},
+		Executor: executor,
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+	errs := b.ErrorChan(10)
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 1)); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		var panicErr *batchflow.PanicError
+		if !errors.Is(err, batchflow.ErrPanic) || !errors.As(err, &panicErr) {
+			t.Fatalf("expected PanicError, got %v", err)
+		}
+		if panicErr.Value != "driver exploded" || !strings.Contains(string(panicErr.Stack), "panickyExecutor") {
+			t.Fatalf("expected panic value and stack captured, got %v\n%s", panicErr.Value, panicErr.Stack)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected panic error on error channel")
+	}
+
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 2)); err != nil {
+		t.Fatalf("submit after panic failed: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for executor.rows.Load() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected flow to keep working after panic, executed %d rows", executor.rows.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,159 @@
+package batchflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func executedRows(mock *batchflow.MockExecutor) int {
+	rows := 0
+	for _, batch := range mock.SnapshotExecutedBatches() {
+		rows += len(batch)
+	}
+	return rows
+}
+
+func TestBatchFlow_PauseBuffersUntilResume(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    64,
+		FlushSize:     2,
+		FlushInterval: 10 * time.Millisecond,
+	})
+	defer b.Close()
+
+	b.Pause()
+	if !b.Paused() {
+		t.Fatal("expected flow to report paused")
+	}
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 6; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit while paused failed: %v", err)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if n := executedRows(mock); n != 0 {
+		t.Fatalf("expected nothing executed while paused, got %d rows", n)
+	}
+
+	b.Resume()
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 6 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 6 rows after resume, got %d", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchFlow_CloseWhilePausedDrains(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    16,
+		FlushSize:     16,
+		FlushInterval: time.Hour,
+	})
+	b.Pause()
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	_ = b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 1))
+
+	done := make(chan error, 1)
+	go func() { done <- b.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close must not hang while paused")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected paused data to drain on close, got %d rows", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchFlow_PauseBlocksSubmitAtBufferSize(t *testing.T) {
+	ctx := context.Background()
+	// FlushSize < BufferSize：暂停期间管道仍会取出满批，背压必须按待处理总数而非通道长度生效
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    4,
+		FlushSize:     2,
+		FlushInterval: time.Hour,
+	})
+	defer b.Close()
+
+	b.Pause()
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 4; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit while paused failed: %v", err)
+		}
+	}
+
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 4))
+	}()
+	select {
+	case err := <-submitted:
+		t.Fatalf("expected Submit to block once BufferSize requests are queued while paused, got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	b.Resume()
+	select {
+	case err := <-submitted:
+		if err != nil {
+			t.Fatalf("blocked submit failed after resume: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Submit stayed blocked after Resume")
+	}
+	// 第 5 个请求留在未满的批次中，由 Close 的最终 flush 执行
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the 4 paused rows executed after resume, got %d", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchFlow_PausedBatchesDrainOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:       8,
+		FlushSize:        2,
+		FlushInterval:    time.Hour,
+		DrainOnCancel:    true,
+		DrainGracePeriod: time.Second,
+	})
+
+	b.Pause()
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 5; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit while paused failed: %v", err)
+		}
+	}
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected paused batches to drain on cancel, got %d rows", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
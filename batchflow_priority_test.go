@@ -0,0 +1,119 @@
+package batchflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_PriorityLaneFlushesFirst(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:            64,
+		FlushSize:             100,
+		FlushInterval:         300 * time.Millisecond,
+		PriorityFlushInterval: 10 * time.Millisecond,
+	})
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 5; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit low priority failed: %v", err)
+		}
+	}
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 100).WithPriority(1)); err != nil {
+		t.Fatalf("submit high priority failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	var batches [][]map[string]any
+	for {
+		batches = mock.SnapshotExecutedBatches()
+		rows := 0
+		for _, batch := range batches {
+			rows += len(batch)
+		}
+		if rows == 6 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 6 executed rows, got %d", rows)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	if len(batches[0]) != 1 || batches[0][0]["id"] != 100 {
+		t.Fatalf("expected high priority request to execute first, got first batch %v", batches[0])
+	}
+}
+
+func TestBatchFlow_PriorityIgnoredWithoutLane(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    16,
+		FlushSize:     16,
+		FlushInterval: time.Hour,
+	})
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	_ = b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 1))
+	_ = b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 2).WithPriority(5))
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		batches := mock.SnapshotExecutedBatches()
+		if len(batches) == 1 && len(batches[0]) == 2 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a single batch with both requests, got %v", batches)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchFlow_PriorityLaneForwardsErrorsFromFlushesFinishingAfterClose(t *testing.T) {
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:            16,
+			FlushSize:             2,
+			FlushInterval:         time.Hour,
+			PriorityFlushInterval: time.Hour,
+		},
+		Executor: slowFailExecutor{delay: 200 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	errs := b.ErrorChan(8)
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 4; i++ {
+		request := batchflow.NewRequest(schema).SetInt("id", i)
+		if i >= 2 {
+			request.WithPriority(1)
+		}
+		if err := b.Submit(ctx, request); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	// 每条通道各有一个满批仍在异步执行时 Close 即返回
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-errs:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("expected 2 forwarded errors, got %d", i)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package batchflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type queueHighWaterMetrics struct {
+	batchflow.NoopMetricsReporter
+
+	mu    sync.Mutex
+	marks []int
+}
+
+func (m *queueHighWaterMetrics) ObserveQueueHighWater(n int) {
+	m.mu.Lock()
+	m.marks = append(m.marks, n)
+	m.mu.Unlock()
+}
+
+func (m *queueHighWaterMetrics) snapshot() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]int(nil), m.marks...)
+}
+
+func TestBatchFlow_QueueHighWaterReflectsBurstPeak(t *testing.T) {
+	ctx := context.Background()
+	reporter := &queueHighWaterMetrics{}
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:           64,
+			FlushSize:            2,
+			FlushInterval:        10 * time.Millisecond,
+			MaxConcurrentFlushes: 1,
+		},
+		Executor: batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+
+	// 暂停后 flush 占满并发上限，后续请求只能堆积在数据通道中
+	b.Pause()
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	const burst = 30
+	for i := 0; i < burst; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	peak := b.QueueHighWater()
+	if peak < burst/2 || peak > burst {
+		t.Fatalf("expected high-water mark near burst size %d, got %d", burst, peak)
+	}
+
+	b.Resume()
+	// 共 burst/FlushSize 次 flush，每次上报一个窗口的高水位
+	deadline := time.Now().Add(2 * time.Second)
+	for len(reporter.snapshot()) < burst/2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d reports, got %v", burst/2, reporter.snapshot())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	maxReported := 0
+	for _, n := range reporter.snapshot() {
+		maxReported = max(maxReported, n)
+	}
+	if maxReported != peak {
+		t.Fatalf("expected reported peak %d, got %d (marks=%v)", peak, maxReported, reporter.snapshot())
+	}
+	// 每次 flush 上报后重置窗口，队列排空后无新提交，高水位归零
+	if n := b.QueueHighWater(); n != 0 {
+		t.Fatalf("expected window reset after flush, got %d", n)
+	}
+}
+
+func TestBatchFlow_ResetQueueHighWater(t *testing.T) {
+	ctx := context.Background()
+	b, _ := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:           16,
+		FlushSize:            1,
+		FlushInterval:        10 * time.Millisecond,
+		MaxConcurrentFlushes: 1,
+	})
+	defer b.Close()
+
+	b.Pause()
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 8; i++ {
+		_ = b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i))
+	}
+	// 未实现 QueueMetricsReporter 时不会自动重置
+	if b.QueueHighWater() == 0 {
+		t.Fatal("expected non-zero high-water mark")
+	}
+	peak := b.ResetQueueHighWater()
+	if peak == 0 || b.QueueHighWater() != 0 {
+		t.Fatalf("expected reset to return peak and clear window, got peak=%d now=%d", peak, b.QueueHighWater())
+	}
+	b.Resume()
+}
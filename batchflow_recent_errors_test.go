@@ -0,0 +1,85 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// rowIDFailExecutor 总是失败，错误中带上批次首行的 id
+type rowIDFailExecutor struct{}
+
+func (rowIDFailExecutor) ExecuteBatch(_ context.Context, _ batchflow.SchemaInterface, data []map[string]any) error {
+	return fmt.Errorf("row %v failed", data[0]["id"])
+}
+
+func TestBatchFlow_RecentErrorsKeepsLastN(t *testing.T) {
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:           1,
+			FlushSize:            1,
+			FlushInterval:        time.Hour,
+			MaxConcurrentFlushes: 1,
+			RecentErrorsSize:     3,
+		},
+		Executor: rowIDFailExecutor{},
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	b.OnError(func(error) {})
+
+	if got := b.RecentErrors(); len(got) != 0 {
+		t.Fatalf("expected empty history, got %v", got)
+	}
+	schema := batchflow.NewSQLSchema("orders", batchflow.ConflictIgnoreOperationConfig, "id")
+	before := time.Now()
+	for i := 1; i <= 5; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	waitUntilEmpty(t, b)
+	b.Close()
+
+	records := b.RecentErrors()
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d: %v", len(records), records)
+	}
+	for i, record := range records {
+		want := fmt.Sprintf("row %d failed", i+3)
+		if record.Err == nil || record.Err.Error() != want {
+			t.Fatalf("record %d: expected %q, got %v", i, want, record.Err)
+		}
+		if record.SchemaName != "orders" {
+			t.Fatalf("record %d: expected schema orders, got %q", i, record.SchemaName)
+		}
+		if record.Time.Before(before) || (i > 0 && record.Time.Before(records[i-1].Time)) {
+			t.Fatalf("record %d: timestamps out of order", i)
+		}
+	}
+}
+
+func TestBatchFlow_RecentErrorsDisabledByDefault(t *testing.T) {
+	b, _ := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{BufferSize: 1, FlushSize: 1, FlushInterval: time.Hour})
+	defer b.Close()
+	if b.RecentErrors() != nil {
+		t.Fatalf("expected nil history when RecentErrorsSize is 0")
+	}
+}
+
+func TestPipelineConfig_RecentErrorsSizeMustBeNonNegative(t *testing.T) {
+	_, err := batchflow.NewBatchFlowWithConfig(context.Background(), batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 1, FlushSize: 1, FlushInterval: time.Hour, RecentErrorsSize: -1},
+		Executor: rowIDFailExecutor{},
+	})
+	var cfgErr *batchflow.ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "RecentErrorsSize" {
+		t.Fatalf("expected RecentErrorsSize ConfigError, got %v", err)
+	}
+}
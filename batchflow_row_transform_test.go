@@ -0,0 +1,116 @@
+package batchflow_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_WithRowTransformAddsDerivedColumn(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:batchflow_row_transform_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, email_hash TEXT)"); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).
+		WithExecuteHook(func(_ context.Context, query string, _ []any, next func() error) error {
+			mu.Lock()
+			queries = append(queries, query)
+			mu.Unlock()
+			return next()
+		})
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 2, FlushInterval: time.Hour},
+		Executor: batchflow.NewThrottledBatchExecutor(processor),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	b.WithRowTransform(func(_ batchflow.SchemaInterface, row map[string]any) error {
+		sum := sha256.Sum256([]byte(row["email"].(string)))
+		row["email_hash"] = hex.EncodeToString(sum[:])
+		return nil
+	})
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "email", "email_hash")
+	for i, email := range []string{"a@example.com", "b@example.com"} {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i+1).SetString("email", email)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	waitUntilEmpty(t, b)
+	b.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(queries) != 1 || !strings.Contains(queries[0], "email_hash") {
+		t.Fatalf("expected derived column in generated SQL, got %v", queries)
+	}
+	var hash string
+	if err := db.QueryRow("SELECT email_hash FROM users WHERE id = 2").Scan(&hash); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	want := sha256.Sum256([]byte("b@example.com"))
+	if hash != hex.EncodeToString(want[:]) {
+		t.Fatalf("unexpected email_hash %q", hash)
+	}
+}
+
+func TestBatchFlow_WithRowTransformErrorAbortsBatch(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{BufferSize: 10, FlushSize: 2, FlushInterval: time.Hour})
+	errBad := errors.New("bad row")
+	b.WithRowTransform(func(_ batchflow.SchemaInterface, row map[string]any) error {
+		if row["id"] == 2 {
+			return errBad
+		}
+		return nil
+	})
+	var (
+		mu       sync.Mutex
+		flushErr error
+	)
+	b.WithAfterFlush(func(_ context.Context, _ batchflow.SchemaInterface, _ int, err error) {
+		mu.Lock()
+		flushErr = err
+		mu.Unlock()
+	})
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 1; i <= 2; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	waitUntilEmpty(t, b)
+	b.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	var batchErr *batchflow.BatchError
+	if !errors.Is(flushErr, errBad) || !errors.As(flushErr, &batchErr) || batchErr.Stage != batchflow.BatchStageValidate {
+		t.Fatalf("expected validate-stage BatchError wrapping transform error, got %v", flushErr)
+	}
+	if n := len(mock.SnapshotExecutedBatches()); n != 0 {
+		t.Fatalf("expected no executed batches, got %d", n)
+	}
+}
@@ -0,0 +1,85 @@
+package batchflow_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type schemaMetrics struct {
+	batchflow.NoopMetricsReporter
+
+	mu        sync.Mutex
+	sizes     map[string]int
+	assembles map[string]int
+	plain     int
+}
+
+func newSchemaMetrics() *schemaMetrics {
+	return &schemaMetrics{sizes: map[string]int{}, assembles: map[string]int{}}
+}
+
+func (m *schemaMetrics) ObserveSchemaBatchSize(schema string, n int) {
+	m.mu.Lock()
+	m.sizes[schema] += n
+	m.mu.Unlock()
+}
+
+func (m *schemaMetrics) ObserveSchemaBatchAssemble(schema string, _ time.Duration) {
+	m.mu.Lock()
+	m.assembles[schema]++
+	m.mu.Unlock()
+}
+
+func (m *schemaMetrics) ObserveBatchSize(int) {
+	m.mu.Lock()
+	m.plain++
+	m.mu.Unlock()
+}
+
+func TestSchemaMetricsReporter_ReceivesSchemaNames(t *testing.T) {
+	reporter := newSchemaMetrics()
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 100, FlushSize: 100, FlushInterval: time.Hour},
+		Executor: batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	orders := batchflow.NewSQLSchema("orders", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 5; i++ {
+		schema := users
+		if i%2 == 1 {
+			schema = orders
+		}
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if reporter.sizes["users"] != 3 || reporter.sizes["orders"] != 2 || len(reporter.sizes) != 2 {
+		t.Fatalf("unexpected per-schema sizes: %v", reporter.sizes)
+	}
+	names := make([]string, 0, len(reporter.assembles))
+	for name := range reporter.assembles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "orders" || names[1] != "users" {
+		t.Fatalf("expected assemble observations for both schemas, got %v", reporter.assembles)
+	}
+	if reporter.plain != 0 {
+		t.Fatalf("expected ObserveBatchSize to be replaced, got %d calls", reporter.plain)
+	}
+}
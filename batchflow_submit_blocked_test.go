@@ -0,0 +1,93 @@
+package batchflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type submitBlockedMetrics struct {
+	batchflow.NoopMetricsReporter
+
+	mu      sync.Mutex
+	blocked []time.Duration
+}
+
+func (m *submitBlockedMetrics) ObserveSubmitBlocked(d time.Duration) {
+	m.mu.Lock()
+	m.blocked = append(m.blocked, d)
+	m.mu.Unlock()
+}
+
+func (m *submitBlockedMetrics) max() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var longest time.Duration
+	for _, d := range m.blocked {
+		longest = max(longest, d)
+	}
+	return longest
+}
+
+func TestBatchFlow_ObserveSubmitBlockedUnderBackpressure(t *testing.T) {
+	reporter := &submitBlockedMetrics{}
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:           1,
+			FlushSize:            1,
+			FlushInterval:        time.Hour,
+			MaxConcurrentFlushes: 1,
+		},
+		Executor: batchflow.NewThrottledBatchExecutor(slowProcessor{delay: 50 * time.Millisecond}).WithMetricsReporter(reporter),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 6; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if longest := reporter.max(); longest < 10*time.Millisecond {
+		t.Fatalf("expected blocked time under backpressure, longest observed %v", longest)
+	}
+}
+
+func TestBatchFlow_ObserveSubmitBlockedOnCancelledWait(t *testing.T) {
+	reporter := &submitBlockedMetrics{}
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 1, FlushSize: 1, FlushInterval: time.Hour, MaxConcurrentFlushes: 1},
+		Executor: batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+	b.Pause()
+	defer b.Resume()
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	// 填满缓冲后，带超时的提交会阻塞直至放弃
+	for i := 0; ; i++ {
+		submitCtx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+		err := b.Submit(submitCtx, batchflow.NewRequest(schema).SetInt("id", i))
+		cancel()
+		if err != nil {
+			break
+		}
+		if i > 1000 {
+			t.Fatal("expected submit to block once the buffer is full")
+		}
+	}
+	if longest := reporter.max(); longest < 20*time.Millisecond {
+		t.Fatalf("expected abandoned wait reported as blocked time, longest observed %v", longest)
+	}
+}
@@ -0,0 +1,126 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// rejectCounter 按原因统计 IncSubmitRejected
+type rejectCounter struct {
+	batchflow.NoopMetricsReporter
+	mu       sync.Mutex
+	rejected map[string]int
+}
+
+func newRejectCounter() *rejectCounter {
+	return &rejectCounter{rejected: make(map[string]int)}
+}
+
+func (r *rejectCounter) IncSubmitRejected(reason string) {
+	r.mu.Lock()
+	r.rejected[reason]++
+	r.mu.Unlock()
+}
+
+func (*rejectCounter) ObservePipelineFlushSize(int)    {}
+func (*rejectCounter) ObserveSchemaGroupsPerFlush(int) {}
+
+func (r *rejectCounter) count(reason string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rejected[reason]
+}
+
+func newRejectCountingFlow(t *testing.T, reporter *rejectCounter, processor batchflow.BatchProcessor, config batchflow.PipelineConfig) *batchflow.BatchFlow {
+	t.Helper()
+	exec := batchflow.NewThrottledBatchExecutor(processor).WithMetricsReporter(reporter)
+	b, err := batchflow.NewBatchFlowWithConfig(context.Background(), batchflow.BatchFlowConfig{
+		Pipeline: config,
+		Executor: exec,
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	return b
+}
+
+func TestBatchFlow_SubmitRejectedReasons(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	t.Run("closed", func(t *testing.T) {
+		reporter := newRejectCounter()
+		b := newRejectCountingFlow(t, reporter, okProcessor{}, batchflow.PipelineConfig{
+			BufferSize:    8,
+			FlushSize:     4,
+			FlushInterval: time.Hour,
+		})
+		if err := b.Close(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := b.Submit(context.Background(), batchflow.NewRequest(schema).SetInt64("id", int64(i))); err == nil {
+				t.Fatal("expected submit to closed flow to fail")
+			}
+		}
+		if got := reporter.count("batchflow_closed"); got != 3 {
+			t.Fatalf("expected 3 batchflow_closed rejections, got %d (%v)", got, reporter.rejected)
+		}
+	})
+
+	t.Run("context_canceled", func(t *testing.T) {
+		reporter := newRejectCounter()
+		b := newRejectCountingFlow(t, reporter, okProcessor{}, batchflow.PipelineConfig{
+			BufferSize:    8,
+			FlushSize:     4,
+			FlushInterval: time.Hour,
+		})
+		defer b.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 1)); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if got := reporter.count("context_canceled"); got != 1 {
+			t.Fatalf("expected 1 context_canceled rejection, got %v", reporter.rejected)
+		}
+	})
+
+	t.Run("buffer_full", func(t *testing.T) {
+		reporter := newRejectCounter()
+		gate := &gateProcessor{release: make(chan struct{})}
+		b := newRejectCountingFlow(t, reporter, gate, batchflow.PipelineConfig{
+			BufferSize:           1,
+			FlushSize:            1,
+			FlushInterval:        time.Hour,
+			MaxConcurrentFlushes: 1,
+		})
+		defer b.Close()
+		defer close(gate.release)
+
+		// 执行器阻塞后缓冲区很快写满，SubmitWithTimeout 开始超时
+		var timedOut bool
+		for i := 0; i < 50 && !timedOut; i++ {
+			err := b.SubmitWithTimeout(context.Background(), batchflow.NewRequest(schema).SetInt64("id", int64(i)), 20*time.Millisecond)
+			switch {
+			case errors.Is(err, batchflow.ErrSubmitTimeout):
+				timedOut = true
+			case err != nil:
+				t.Fatalf("unexpected submit error: %v", err)
+			}
+		}
+		if !timedOut {
+			t.Fatal("expected SubmitWithTimeout to time out on a full buffer")
+		}
+		if got := reporter.count("buffer_full"); got != 1 {
+			t.Fatalf("expected 1 buffer_full rejection, got %v", reporter.rejected)
+		}
+		if got := reporter.count("context_deadline_exceeded"); got != 0 {
+			t.Fatalf("submit timeout should not be reported as context_deadline_exceeded, got %v", reporter.rejected)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_SubmitWithTimeoutFailsFastOnSaturatedBuffer(t *testing.T) {
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 1, FlushSize: 1, FlushInterval: time.Hour, MaxConcurrentFlushes: 1},
+		Executor: batchflow.NewThrottledBatchExecutor(okProcessor{}),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+	b.Pause()
+	defer b.Resume()
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	const timeout = 20 * time.Millisecond
+	for i := 0; i < 1000; i++ {
+		start := time.Now()
+		err := b.SubmitWithTimeout(ctx, batchflow.NewRequest(schema).SetInt("id", i), timeout)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, batchflow.ErrSubmitTimeout) {
+			t.Fatalf("expected ErrSubmitTimeout, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < timeout || elapsed > timeout+500*time.Millisecond {
+			t.Fatalf("expected timeout after ~%v, took %v", timeout, elapsed)
+		}
+		return
+	}
+	t.Fatal("expected buffer to saturate")
+}
+
+func TestBatchFlow_SubmitWithTimeoutKeepsParentCancellation(t *testing.T) {
+	b, _ := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{BufferSize: 10, FlushSize: 10, FlushInterval: time.Hour})
+	defer b.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	err := b.SubmitWithTimeout(ctx, batchflow.NewRequest(schema).SetInt("id", 1), time.Second)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected parent cancellation, got %v", err)
+	}
+}
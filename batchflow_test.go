@@ -64,7 +64,7 @@ func TestBatchFlow(t *testing.T) {
 	}
 
 	// 等待批量处理完成
-	time.Sleep(2 * time.Second)
+	waitUntilEmpty(t, mysqlBatch, postgreSQLBatch, sqliteBatch)
 
 	// 验证执行结果
 	snapshotMy := mysqlSchemaMockExecutor.SnapshotExecutedBatches()
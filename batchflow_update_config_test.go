@@ -0,0 +1,189 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// gateProcessor 阻塞每个批次直到 release 关闭，并记录同时在执行的批次数
+type gateProcessor struct {
+	release chan struct{}
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (p *gateProcessor) GenerateOperations(context.Context, batchflow.SchemaInterface, []map[string]any) (batchflow.Operations, error) {
+	return batchflow.Operations{"ok"}, nil
+}
+
+func (p *gateProcessor) ExecuteOperations(ctx context.Context, _ batchflow.Operations) error {
+	n := p.running.Add(1)
+	defer p.running.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func waitForRunning(t *testing.T, p *gateProcessor, want int32) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for p.running.Load() < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d concurrent executions, got %d", want, p.running.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchFlow_UpdateConfigRaisesConcurrencyLimit(t *testing.T) {
+	processor := &gateProcessor{release: make(chan struct{})}
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:           10,
+			FlushSize:            1,
+			FlushInterval:        time.Hour,
+			MaxConcurrentFlushes: 4,
+		},
+		Executor: batchflow.NewThrottledBatchExecutor(processor).WithConcurrencyLimit(1),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	var releaseOnce sync.Once
+	release := func() { releaseOnce.Do(func() { close(processor.release) }) }
+	defer func() {
+		release()
+		b.Close()
+	}()
+
+	schema := batchflow.NewSQLSchema("jobs", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 3; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	waitForRunning(t, processor, 1)
+	time.Sleep(50 * time.Millisecond)
+	if got := processor.peak.Load(); got != 1 {
+		t.Fatalf("expected at most 1 concurrent execution before update, got %d", got)
+	}
+
+	limit := 3
+	if err := b.UpdateConfig(batchflow.PipelineConfigPatch{ConcurrencyLimit: &limit}); err != nil {
+		t.Fatalf("update config failed: %v", err)
+	}
+	waitForRunning(t, processor, 3)
+	release()
+}
+
+func TestBatchFlow_UpdateConfigReenablesLimitWithInflightBatches(t *testing.T) {
+	processor := &gateProcessor{release: make(chan struct{})}
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:           10,
+			FlushSize:            1,
+			FlushInterval:        time.Hour,
+			MaxConcurrentFlushes: 4,
+		},
+		Executor: batchflow.NewThrottledBatchExecutor(processor).WithConcurrencyLimit(1),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	var releaseOnce sync.Once
+	release := func() { releaseOnce.Do(func() { close(processor.release) }) }
+	defer func() {
+		release()
+		b.Close()
+	}()
+
+	schema := batchflow.NewSQLSchema("jobs", batchflow.ConflictIgnoreOperationConfig, "id")
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 0)); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	waitForRunning(t, processor, 1)
+
+	// 关闭限流再恢复：仍在执行的批次必须继续计入上限
+	unlimited, one := 0, 1
+	if err := b.UpdateConfig(batchflow.PipelineConfigPatch{ConcurrencyLimit: &unlimited}); err != nil {
+		t.Fatalf("update config failed: %v", err)
+	}
+	if err := b.UpdateConfig(batchflow.PipelineConfigPatch{ConcurrencyLimit: &one}); err != nil {
+		t.Fatalf("update config failed: %v", err)
+	}
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 1)); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := processor.peak.Load(); got != 1 {
+		t.Fatalf("expected at most 1 concurrent execution after re-enabling the limit, got %d", got)
+	}
+	release()
+}
+
+func TestBatchFlow_UpdateConfigFlushSize(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{BufferSize: 10, FlushSize: 10, FlushInterval: time.Hour})
+	defer b.Close()
+
+	size := uint32(2)
+	if err := b.UpdateConfig(batchflow.PipelineConfigPatch{FlushSize: &size}); err != nil {
+		t.Fatalf("update config failed: %v", err)
+	}
+	schema := batchflow.NewSQLSchema("jobs", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 2; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a flush at the new FlushSize, got %d rows", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchFlow_UpdateConfigRejectsInvalidPatch(t *testing.T) {
+	b, _ := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{BufferSize: 10, FlushSize: 5, FlushInterval: time.Hour})
+	defer b.Close()
+
+	buffer := uint32(20)
+	tooLarge := uint32(11)
+	limit := 2
+	cases := []struct {
+		name  string
+		patch batchflow.PipelineConfigPatch
+		field string
+	}{
+		{"immutable buffer size", batchflow.PipelineConfigPatch{BufferSize: &buffer}, "BufferSize"},
+		{"flush size over buffer", batchflow.PipelineConfigPatch{FlushSize: &tooLarge}, "FlushSize"},
+		{"executor without limiter", batchflow.PipelineConfigPatch{ConcurrencyLimit: &limit}, "ConcurrencyLimit"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := b.UpdateConfig(tc.patch)
+			var cfgErr *batchflow.ConfigError
+			if !errors.As(err, &cfgErr) || cfgErr.Field != tc.field {
+				t.Fatalf("expected ConfigError for %s, got %v", tc.field, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// blockingExecutor 在 release 关闭前阻塞 ExecuteBatch，用于模拟执行中的批次
+type blockingExecutor struct {
+	started chan struct{}
+	release chan struct{}
+	rows    atomic.Int32
+}
+
+func (e *blockingExecutor) ExecuteBatch(ctx context.Context, _ batchflow.SchemaInterface, data []map[string]any) error {
+	select {
+	case e.started <- struct{}{}:
+	default:
+	}
+	<-e.release
+	e.rows.Add(int32(len(data)))
+	return nil
+}
+
+func TestBatchFlow_WaitUntilEmptyDrainsWithoutSleep(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    64,
+		FlushSize:     10,
+		FlushInterval: 20 * time.Millisecond,
+	})
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 25; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := b.WaitUntilEmpty(waitCtx); err != nil {
+		t.Fatalf("WaitUntilEmpty: %v", err)
+	}
+	if got := executedRows(mock); got != 25 {
+		t.Fatalf("expected 25 executed rows after WaitUntilEmpty, got %d", got)
+	}
+}
+
+func TestBatchFlow_WaitUntilEmptyReturnsImmediatelyWhenIdle(t *testing.T) {
+	ctx := context.Background()
+	b, _ := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    8,
+		FlushSize:     4,
+		FlushInterval: time.Hour,
+	})
+	defer b.Close()
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := b.WaitUntilEmpty(waitCtx); err != nil {
+		t.Fatalf("idle WaitUntilEmpty should return nil, got %v", err)
+	}
+}
+
+func TestBatchFlow_WaitUntilEmptyWaitsForInFlightBatch(t *testing.T) {
+	ctx := context.Background()
+	exec := &blockingExecutor{started: make(chan struct{}, 1), release: make(chan struct{})}
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 8, FlushSize: 2, FlushInterval: time.Hour},
+		Executor: exec,
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 2; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	select {
+	case <-exec.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch did not start executing")
+	}
+
+	// 批次已从缓冲区取出但仍在执行，不应视为空
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := b.WaitUntilEmpty(shortCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded while batch in flight, got %v", err)
+	}
+
+	close(exec.release)
+	waitCtx, cancelWait := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelWait()
+	if err := b.WaitUntilEmpty(waitCtx); err != nil {
+		t.Fatalf("WaitUntilEmpty: %v", err)
+	}
+	if got := exec.rows.Load(); got != 2 {
+		t.Fatalf("expected 2 executed rows, got %d", got)
+	}
+}
+
+// waitUntilEmpty 等待 BatchFlow 排空，替代测试中的固定 Sleep
+func waitUntilEmpty(t *testing.T, flows ...*batchflow.BatchFlow) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, b := range flows {
+		if err := b.WaitUntilEmpty(ctx); err != nil {
+			t.Fatalf("WaitUntilEmpty: %v", err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package batchflow_test
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestNewBatchFlowWithCancel_StopDrainsAndReleasesGoroutines(t *testing.T) {
+	ctx := context.Background() // 从不取消：只依赖 stop 结束生命周期
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	settle := func() int {
+		var n int
+		for i := 0; i < 50; i++ {
+			runtime.GC()
+			n = runtime.NumGoroutine()
+			time.Sleep(10 * time.Millisecond)
+			if runtime.NumGoroutine() == n {
+				break
+			}
+		}
+		return n
+	}
+	before := settle()
+
+	mock := batchflow.NewMockExecutor()
+	b, stop := batchflow.NewBatchFlowWithCancel(ctx, 16, 100, time.Hour, mock)
+	var _ io.Closer = b
+	for i := 0; i < 5; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("second stop should be a no-op, got %v", err)
+	}
+
+	select {
+	case <-b.Done():
+	default:
+		t.Fatal("expected background goroutine to have exited after stop")
+	}
+	if rows := executedRows(mock); rows != 5 {
+		t.Fatalf("expected buffered rows to be flushed on stop, got %d", rows)
+	}
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 99)); err == nil {
+		t.Fatal("expected submit after stop to fail")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		after := settle()
+		if after <= before {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines grew from %d to %d after stop", before, after)
+		}
+	}
+}
@@ -119,6 +119,12 @@ func (c *KeyCoalescer) key(schema SchemaInterface, row Record) (string, bool) {
 	if len(columns) == 0 {
 		return "", false
 	}
+	for _, col := range columns {
+		// 内联 SQL 表达式（如 SQLExpr("UUID()")）逐行求值，客户端无法判断是否重复
+		if _, ok := row[col].(inlineSQLValue); ok {
+			return "", false
+		}
+	}
 	return recordKey(row, columns), true
 }
 
@@ -0,0 +1,106 @@
+package batchflow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"sync"
+)
+
+// CompressionType 列级压缩算法（PipelineConfig.CompressColumns）
+type CompressionType string
+
+const (
+	// CompressionGzip 标准库 gzip，内置可用
+	CompressionGzip CompressionType = "gzip"
+	// CompressionZstd zstd；为避免引入额外依赖未内置，需先通过 RegisterCompressor 注册实现
+	CompressionZstd CompressionType = "zstd"
+)
+
+// CompressFunc 压缩函数：输入原始字节，返回压缩后的字节
+type CompressFunc func(data []byte) ([]byte, error)
+
+var compressors = struct {
+	sync.RWMutex
+	funcs map[CompressionType]CompressFunc
+}{
+	funcs: map[CompressionType]CompressFunc{CompressionGzip: gzipCompress},
+}
+
+// RegisterCompressor 注册或替换某种压缩算法的实现，例如基于 github.com/klauspost/compress/zstd
+// 注册 CompressionZstd。返回的函数恢复注册前的状态。
+func RegisterCompressor(t CompressionType, fn CompressFunc) func() {
+	if fn == nil {
+		return func() {}
+	}
+	compressors.Lock()
+	prev, had := compressors.funcs[t]
+	compressors.funcs[t] = fn
+	compressors.Unlock()
+	return func() {
+		compressors.Lock()
+		defer compressors.Unlock()
+		if had {
+			compressors.funcs[t] = prev
+		} else {
+			delete(compressors.funcs, t)
+		}
+	}
+}
+
+func lookupCompressor(t CompressionType) (CompressFunc, bool) {
+	compressors.RLock()
+	defer compressors.RUnlock()
+	fn, ok := compressors.funcs[t]
+	return fn, ok
+}
+
+var gzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+
+func gzipCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(zw)
+	zw.Reset(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// compressRows 对组装后的行数据按列压缩（先于 EncryptColumns 执行）；行 map 为 flush 内部副本，不影响 Request 本身
+func compressRows(rows []map[string]any, compress map[string]CompressionType) error {
+	if len(compress) == 0 {
+		return nil
+	}
+	for col, t := range compress {
+		fn, ok := lookupCompressor(t)
+		if !ok {
+			return fmt.Errorf("column %s: compression %q is not registered", col, t)
+		}
+		for _, row := range rows {
+			value, ok := row[col]
+			if !ok || value == nil {
+				continue
+			}
+			var raw []byte
+			switch v := value.(type) {
+			case []byte:
+				raw = v
+			case string:
+				raw = []byte(v)
+			default:
+				return fmt.Errorf("column %s: compression expects []byte or string, got %T", col, value)
+			}
+			compressed, err := fn(raw)
+			if err != nil {
+				return fmt.Errorf("column %s: compress: %w", col, err)
+			}
+			row[col] = compressed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package batchflow_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_CompressColumnsGzip(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:      10,
+		FlushSize:       1,
+		FlushInterval:   time.Hour,
+		CompressColumns: map[string]batchflow.CompressionType{"body": batchflow.CompressionGzip},
+	})
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("documents", batchflow.ConflictIgnoreOperationConfig, "id", "body")
+	body := strings.Repeat("batchflow compresses large text columns. ", 4096)
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 1).SetString("body", body)); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 1 row executed, got %d", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	row := mock.SnapshotExecutedBatches()[0][0]
+	compressed, ok := row["body"].([]byte)
+	if !ok {
+		t.Fatalf("expected compressed []byte, got %T", row["body"])
+	}
+	if len(compressed) >= len(body) {
+		t.Fatalf("expected compressed arg smaller than %d bytes, got %d", len(body), len(compressed))
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("gzip reader failed: %v", err)
+	}
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompress failed: %v", err)
+	}
+	if string(plain) != body {
+		t.Fatal("decompressed value does not match original")
+	}
+}
+
+func TestPipelineConfig_CompressColumnsRequiresRegisteredCompressor(t *testing.T) {
+	config := batchflow.DefaultPipelineConfig()
+	config.CompressColumns = map[string]batchflow.CompressionType{"body": batchflow.CompressionZstd}
+	var configErr *batchflow.ConfigError
+	if err := config.Validate(); !errors.As(err, &configErr) || configErr.Field != "CompressColumns" {
+		t.Fatalf("expected CompressColumns ConfigError, got %v", err)
+	}
+
+	restore := batchflow.RegisterCompressor(batchflow.CompressionZstd, func(data []byte) ([]byte, error) { return data, nil })
+	defer restore()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("expected registered zstd to validate, got %v", err)
+	}
+}
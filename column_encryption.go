@@ -0,0 +1,36 @@
+package batchflow
+
+import "fmt"
+
+// ColumnEncryptFunc 列级加密函数：输入明文字节，返回密文
+type ColumnEncryptFunc func(plaintext []byte) ([]byte, error)
+
+// encryptRows 对组装后的行数据按列加密；行 map 为 flush 内部副本，不影响 Request 本身
+func encryptRows(rows []map[string]any, encrypt map[string]ColumnEncryptFunc) error {
+	if len(encrypt) == 0 {
+		return nil
+	}
+	for _, row := range rows {
+		for col, fn := range encrypt {
+			value, ok := row[col]
+			if !ok || value == nil || fn == nil {
+				continue
+			}
+			var plaintext []byte
+			switch v := value.(type) {
+			case []byte:
+				plaintext = v
+			case string:
+				plaintext = []byte(v)
+			default:
+				return fmt.Errorf("column %s: encryption expects []byte or string, got %T", col, value)
+			}
+			ciphertext, err := fn(plaintext)
+			if err != nil {
+				return fmt.Errorf("column %s: encrypt: %w", col, err)
+			}
+			row[col] = ciphertext
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package batchflow_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func xorEncrypt(plaintext []byte) ([]byte, error) {
+	out := make([]byte, len(plaintext))
+	for i, c := range plaintext {
+		out[i] = c ^ 0x5a
+	}
+	return out, nil
+}
+
+func TestBatchFlow_EncryptColumnsTransformsConfiguredColumns(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:     16,
+		FlushSize:      2,
+		FlushInterval:  10 * time.Millisecond,
+		EncryptColumns: map[string]batchflow.ColumnEncryptFunc{"ssn": xorEncrypt, "token": xorEncrypt},
+	})
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name", "ssn", "token")
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).
+		SetInt64("id", 1).
+		SetString("name", "alice").
+		SetString("ssn", "123-45-6789").
+		SetBytes("token", []byte("secret"))); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).
+		SetInt64("id", 2).
+		SetString("name", "bob").
+		SetNull("ssn").
+		SetBytes("token", []byte("other"))); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 rows, got %d", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	rows := mock.SnapshotExecutedBatches()[0]
+	first, second := rows[0], rows[1]
+
+	ssn, ok := first["ssn"].([]byte)
+	if !ok {
+		t.Fatalf("expected encrypted ssn as []byte, got %T", first["ssn"])
+	}
+	if string(ssn) == "123-45-6789" {
+		t.Fatal("ssn must not reach the executor as plaintext")
+	}
+	want, _ := xorEncrypt([]byte("123-45-6789"))
+	if !bytes.Equal(ssn, want) {
+		t.Fatalf("unexpected ciphertext: %x", ssn)
+	}
+	if token, _ := first["token"].([]byte); bytes.Equal(token, []byte("secret")) {
+		t.Fatal("token must be encrypted")
+	}
+
+	if first["id"] != int64(1) || first["name"] != "alice" {
+		t.Fatalf("untouched columns changed: %#v", first)
+	}
+	if second["ssn"] != nil {
+		t.Fatalf("nil value must stay NULL, got %#v", second["ssn"])
+	}
+	if second["name"] != "bob" {
+		t.Fatalf("untouched columns changed: %#v", second)
+	}
+}
+
+func TestBatchFlow_EncryptColumnsRejectsUnsupportedType(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:     16,
+		FlushSize:      1,
+		FlushInterval:  10 * time.Millisecond,
+		EncryptColumns: map[string]batchflow.ColumnEncryptFunc{"ssn": xorEncrypt},
+	})
+	defer b.Close()
+	errs := b.ErrorChan(4)
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "ssn")
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 1).SetInt64("ssn", 42)); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("expected encryption error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected encryption error on ErrorChan")
+	}
+	if n := executedRows(mock); n != 0 {
+		t.Fatalf("batch must not be executed after encryption failure, got %d rows", n)
+	}
+}
@@ -0,0 +1,51 @@
+package batchflow
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// schemaColumnMaxLengths 返回 schema 声明的列最大长度（只读）；非 SQLSchema 或未声明时为 nil
+func schemaColumnMaxLengths(schema SchemaInterface) map[string]int {
+	if s, ok := schema.(*SQLSchema); ok && s != nil {
+		return s.operationConfig.ColumnMaxLengths
+	}
+	return nil
+}
+
+// checkColumnMaxLengths 检查 string/[]byte 列值是否超出声明的最大长度：
+// string 按字符（rune）计数，与 VARCHAR(n) 的语义一致；[]byte 按字节计数。其余类型不检查。
+func (r *Request) checkColumnMaxLengths() error {
+	limits := schemaColumnMaxLengths(r.schema)
+	if len(limits) == 0 {
+		return nil
+	}
+	for _, colName := range r.schema.Columns() {
+		limit := limits[colName]
+		if limit <= 0 {
+			continue
+		}
+		length := 0
+		switch v := r.columns[colName].(type) {
+		case string:
+			// 字节数不超过上限时字符数必然不超过，省去计数
+			if len(v) <= limit {
+				continue
+			}
+			length = utf8.RuneCountInString(v)
+		case []byte:
+			length = len(v)
+		default:
+			continue
+		}
+		if length > limit {
+			return &ColumnError{
+				SchemaName: r.schema.Name(),
+				Column:     colName,
+				Reason:     fmt.Sprintf("value length %d exceeds max length %d", length, limit),
+				Err:        ErrValueTooLong,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package batchflow
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ColumnType 列的逻辑值类型，用于 Request.Validate 在执行前检查 Go 值类型（SQLOperationConfig.WithColumnTypes）
+type ColumnType uint8
+
+const (
+	ColumnTypeAny     ColumnType = iota // 不检查
+	ColumnTypeInteger                   // 有/无符号整数；超出 int64 的十进制字符串（SetUint64/SetBigInt）
+	ColumnTypeFloat                     // 整数、浮点数与数值字符串（DECIMAL/NUMERIC）
+	ColumnTypeString                    // string、[]byte
+	ColumnTypeBool                      // bool
+	ColumnTypeTime                      // time.Time
+	ColumnTypeBytes                     // []byte、string
+	ColumnTypeJSON                      // SetMap/SetStruct、json.RawMessage、string、[]byte
+)
+
+func (t ColumnType) String() string {
+	switch t {
+	case ColumnTypeAny:
+		return "any"
+	case ColumnTypeInteger:
+		return "integer"
+	case ColumnTypeFloat:
+		return "float"
+	case ColumnTypeString:
+		return "string"
+	case ColumnTypeBool:
+		return "bool"
+	case ColumnTypeTime:
+		return "time"
+	case ColumnTypeBytes:
+		return "bytes"
+	case ColumnTypeJSON:
+		return "json"
+	default:
+		return fmt.Sprintf("ColumnType(%d)", uint8(t))
+	}
+}
+
+// schemaColumnTypes 返回 schema 声明的列类型（只读）；非 SQLSchema 或未声明时为 nil
+func schemaColumnTypes(schema SchemaInterface) map[string]ColumnType {
+	if s, ok := schema.(*SQLSchema); ok && s != nil {
+		return s.operationConfig.ColumnTypes
+	}
+	return nil
+}
+
+// columnTypeAccepts 判断值能否写入声明类型的列。NULL、内联 SQL 与自定义 driver.Valuer 不做检查。
+func columnTypeAccepts(t ColumnType, value any) bool {
+	switch value.(type) {
+	case nil, inlineSQLValue, driver.Valuer:
+		return true
+	}
+	switch t {
+	case ColumnTypeInteger:
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case string:
+			return isDecimalInteger(v)
+		}
+	case ColumnTypeFloat:
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return true
+		case string:
+			_, err := strconv.ParseFloat(v, 64)
+			return err == nil
+		}
+	case ColumnTypeString, ColumnTypeBytes:
+		switch value.(type) {
+		case string, []byte:
+			return true
+		}
+	case ColumnTypeBool:
+		_, ok := value.(bool)
+		return ok
+	case ColumnTypeTime:
+		_, ok := value.(time.Time)
+		return ok
+	case ColumnTypeJSON:
+		switch value.(type) {
+		case *jsonColumnValue, json.RawMessage, string, []byte:
+			return true
+		}
+	default:
+		return true
+	}
+	return false
+}
+
+// isDecimalInteger 判断字符串是否为十进制整数（允许前导负号）
+func isDecimalInteger(s string) bool {
+	if len(s) > 0 && s[0] == '-' {
+		s = s[1:]
+	}
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package batchflow
+
+import (
+	"context"
+	"sync"
+)
+
+// concurrencySemaphore 可在运行时调整容量的计数信号量；limit <= 0 表示不限流，但仍统计在途数，
+// 使之后恢复限流时已在执行的批次也计入上限。
+// 缩小容量时不打断已持有令牌的批次，新的获取会等到在途数降到新上限以下。
+type concurrencySemaphore struct {
+	mu      sync.Mutex
+	limit   int // <= 0 表示不限流
+	inUse   int
+	changed chan struct{} // 释放或调整容量时关闭并替换，唤醒等待者重新检查
+}
+
+func newConcurrencySemaphore(limit int) *concurrencySemaphore {
+	return &concurrencySemaphore{limit: limit, changed: make(chan struct{})}
+}
+
+// acquire 获取一个令牌；limited 表示获取时处于限流状态。ctx 取消时放弃等待并返回 ctx.Err()
+func (s *concurrencySemaphore) acquire(ctx context.Context) (limited bool, err error) {
+	for {
+		s.mu.Lock()
+		if s.limit <= 0 || s.inUse < s.limit {
+			s.inUse++
+			limited = s.limit > 0
+			s.mu.Unlock()
+			return limited, nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return true, ctx.Err()
+		}
+	}
+}
+
+func (s *concurrencySemaphore) release() {
+	s.mu.Lock()
+	s.inUse--
+	s.broadcastLocked()
+	s.mu.Unlock()
+}
+
+// resize 原地调整容量（<= 0 表示不限流），在途数保持不变
+func (s *concurrencySemaphore) resize(limit int) {
+	s.mu.Lock()
+	s.limit = max(limit, 0)
+	s.broadcastLocked()
+	s.mu.Unlock()
+}
+
+func (s *concurrencySemaphore) capacity() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limit
+}
+
+func (s *concurrencySemaphore) broadcastLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
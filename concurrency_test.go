@@ -47,7 +47,7 @@ func TestConcurrency_MultipleGoroutinesSubmit(t *testing.T) {
 	wg.Wait()
 
 	// 等待所有数据处理完成
-	time.Sleep(2 * time.Second)
+	waitUntilEmpty(t, batch)
 }
 
 func TestConcurrency_MultipleSchemas(t *testing.T) {
@@ -110,7 +110,7 @@ func TestConcurrency_MultipleSchemas(t *testing.T) {
 	wg.Wait()
 
 	// 等待所有数据处理完成
-	time.Sleep(2 * time.Second)
+	waitUntilEmpty(t, batch)
 }
 
 func TestConcurrency_HighFrequencySubmission(t *testing.T) {
@@ -161,7 +161,7 @@ func TestConcurrency_HighFrequencySubmission(t *testing.T) {
 	t.Logf("Error count: %d", errorCount)
 
 	// 等待所有数据处理完成
-	time.Sleep(2 * time.Second)
+	waitUntilEmpty(t, batch)
 }
 
 func TestConcurrency_ContextCancellation(t *testing.T) {
@@ -351,7 +351,7 @@ func TestConcurrency_StressTest(t *testing.T) {
 	t.Logf("  Error rate: %.2f%%", float64(totalErrors)/float64(totalRequests)*100)
 
 	// 等待所有数据处理完成
-	time.Sleep(3 * time.Second)
+	waitUntilEmpty(t, batch)
 
 	if totalErrors > int32(totalRequests/100) { // 错误率不应超过1%
 		t.Errorf("Error rate too high: %d errors out of %d requests", totalErrors, totalRequests)
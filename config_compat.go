@@ -0,0 +1,102 @@
+package batchflow
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	redisV9 "github.com/redis/go-redis/v9"
+)
+
+// validateCompatibility 在 Validate 基础上拒绝不合理的组合（供 E 后缀构造函数使用）：
+//   - BufferSize 为 0（E 构造函数要求显式设置缓冲区大小）
+//   - FlushSize 大于 BufferSize（缓冲区在达到 FlushSize 前就会阻塞 Submit）
+//   - ConcurrencyLimit 为负数
+func (c PipelineConfig) validateCompatibility() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.BufferSize == 0 {
+		return &ConfigError{Field: "BufferSize", Cause: errors.New("must be > 0")}
+	}
+	if flushSize := c.withDefaults().FlushSize; flushSize > c.BufferSize {
+		return &ConfigError{Field: "FlushSize", Cause: fmt.Errorf("%d exceeds BufferSize %d", flushSize, c.BufferSize)}
+	}
+	return nil
+}
+
+// clampIncompatible 将不合理的组合钳制到安全值并记录警告（零值仍按默认值处理，不告警）
+func (c PipelineConfig) clampIncompatible(logger Logger) PipelineConfig {
+	if c.ConcurrencyLimit < 0 {
+		logger.Warn("batchflow config clamped", "field", "ConcurrencyLimit", "value", c.ConcurrencyLimit, "clamped", 0)
+		c.ConcurrencyLimit = 0
+	}
+	resolved := c.withDefaults()
+	if resolved.FlushSize > resolved.BufferSize {
+		logger.Warn("batchflow config clamped", "field", "FlushSize", "value", resolved.FlushSize, "clamped", resolved.BufferSize, "buffer_size", resolved.BufferSize)
+		c.FlushSize = resolved.BufferSize
+	}
+	return c
+}
+
+// NewBatchFlowE 与 NewBatchFlow 相同，但对不合理的配置返回 *ConfigError 而不是钳制
+func NewBatchFlowE(ctx context.Context, buffSize uint32, flushSize uint32, flushInterval time.Duration, executor BatchExecutor) (*BatchFlow, error) {
+	if executor == nil {
+		return nil, &ConfigError{Field: "Executor", Cause: errors.New("must not be nil")}
+	}
+	config := PipelineConfig{BufferSize: buffSize, FlushSize: flushSize, FlushInterval: flushInterval}
+	if err := config.validateCompatibility(); err != nil {
+		return nil, err
+	}
+	return NewBatchFlow(ctx, buffSize, flushSize, flushInterval, executor), nil
+}
+
+// NewSQLBatchFlowWithDriverE 与 NewSQLBatchFlowWithDriver 相同，但对不合理的配置返回 *ConfigError
+func NewSQLBatchFlowWithDriverE(ctx context.Context, db *sql.DB, config PipelineConfig, driver SQLDriver) (*BatchFlow, error) {
+	if err := config.validateCompatibility(); err != nil {
+		return nil, err
+	}
+	return NewSQLBatchFlowWithDriver(ctx, db, config, driver), nil
+}
+
+// NewMySQLBatchFlowE 与 NewMySQLBatchFlow 相同，但对不合理的配置返回 *ConfigError
+func NewMySQLBatchFlowE(ctx context.Context, db *sql.DB, config PipelineConfig, opts ...MySQLOption) (*BatchFlow, error) {
+	if err := config.validateCompatibility(); err != nil {
+		return nil, err
+	}
+	return NewMySQLBatchFlow(ctx, db, config, opts...), nil
+}
+
+// NewPostgreSQLBatchFlowE 与 NewPostgreSQLBatchFlow 相同，但对不合理的配置返回 *ConfigError
+func NewPostgreSQLBatchFlowE(ctx context.Context, db *sql.DB, config PipelineConfig) (*BatchFlow, error) {
+	if err := config.validateCompatibility(); err != nil {
+		return nil, err
+	}
+	return NewPostgreSQLBatchFlow(ctx, db, config), nil
+}
+
+// NewSQLiteBatchFlowE 与 NewSQLiteBatchFlow 相同，但对不合理的配置返回 *ConfigError
+func NewSQLiteBatchFlowE(ctx context.Context, db *sql.DB, config PipelineConfig) (*BatchFlow, error) {
+	if err := config.validateCompatibility(); err != nil {
+		return nil, err
+	}
+	return NewSQLiteBatchFlow(ctx, db, config), nil
+}
+
+// NewDuckDBBatchFlowE 与 NewDuckDBBatchFlow 相同，但对不合理的配置返回 *ConfigError
+func NewDuckDBBatchFlowE(ctx context.Context, db *sql.DB, config PipelineConfig) (*BatchFlow, error) {
+	if err := config.validateCompatibility(); err != nil {
+		return nil, err
+	}
+	return NewDuckDBBatchFlow(ctx, db, config), nil
+}
+
+// NewRedisBatchFlowE 与 NewRedisBatchFlow 相同，但对不合理的配置返回 *ConfigError
+func NewRedisBatchFlowE(ctx context.Context, db *redisV9.Client, config PipelineConfig) (*BatchFlow, error) {
+	if err := config.validateCompatibility(); err != nil {
+		return nil, err
+	}
+	return NewRedisBatchFlow(ctx, db, config), nil
+}
@@ -0,0 +1,100 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestEConstructors_RejectIncompatibleConfig(t *testing.T) {
+	ctx := context.Background()
+	db := sql.OpenDB(execModeConnector{d: &execModeDriver{}})
+	t.Cleanup(func() { _ = db.Close() })
+
+	cases := []struct {
+		name   string
+		config batchflow.PipelineConfig
+		field  string
+	}{
+		{"zero buffer", batchflow.PipelineConfig{FlushSize: 10, FlushInterval: time.Second}, "BufferSize"},
+		{"flush exceeds buffer", batchflow.PipelineConfig{BufferSize: 10, FlushSize: 20, FlushInterval: time.Second}, "FlushSize"},
+		{"default flush exceeds small buffer", batchflow.PipelineConfig{BufferSize: 10, FlushInterval: time.Second}, "FlushSize"},
+		{"negative concurrency", batchflow.PipelineConfig{BufferSize: 10, FlushSize: 5, FlushInterval: time.Second, ConcurrencyLimit: -1}, "ConcurrencyLimit"},
+	}
+	constructors := map[string]func(batchflow.PipelineConfig) (*batchflow.BatchFlow, error){
+		"mysql": func(c batchflow.PipelineConfig) (*batchflow.BatchFlow, error) {
+			return batchflow.NewMySQLBatchFlowE(ctx, db, c)
+		},
+		"postgresql": func(c batchflow.PipelineConfig) (*batchflow.BatchFlow, error) {
+			return batchflow.NewPostgreSQLBatchFlowE(ctx, db, c)
+		},
+		"sqlite": func(c batchflow.PipelineConfig) (*batchflow.BatchFlow, error) {
+			return batchflow.NewSQLiteBatchFlowE(ctx, db, c)
+		},
+		"duckdb": func(c batchflow.PipelineConfig) (*batchflow.BatchFlow, error) {
+			return batchflow.NewDuckDBBatchFlowE(ctx, db, c)
+		},
+		"sql driver": func(c batchflow.PipelineConfig) (*batchflow.BatchFlow, error) {
+			return batchflow.NewSQLBatchFlowWithDriverE(ctx, db, c, batchflow.DefaultMySQLDriver)
+		},
+	}
+	for _, tc := range cases {
+		for name, construct := range constructors {
+			t.Run(tc.name+"/"+name, func(t *testing.T) {
+				b, err := construct(tc.config)
+				var cfgErr *batchflow.ConfigError
+				if !errors.As(err, &cfgErr) || cfgErr.Field != tc.field {
+					if b != nil {
+						_ = b.Close()
+					}
+					t.Fatalf("expected ConfigError on %s, got %v", tc.field, err)
+				}
+			})
+		}
+	}
+
+	if _, err := batchflow.NewBatchFlowE(ctx, 10, 20, time.Second, batchflow.NewMockExecutor()); err == nil {
+		t.Fatal("NewBatchFlowE: expected FlushSize > BufferSize to be rejected")
+	}
+	if _, err := batchflow.NewBatchFlowE(ctx, 0, 20, time.Second, batchflow.NewMockExecutor()); err == nil {
+		t.Fatal("NewBatchFlowE: expected zero BufferSize to be rejected")
+	}
+
+	b, err := batchflow.NewMySQLBatchFlowE(ctx, db, batchflow.PipelineConfig{BufferSize: 10, FlushSize: 10, FlushInterval: time.Second})
+	if err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+	_ = b.Close()
+}
+
+func TestConstructors_ClampIncompatibleConfigWithWarning(t *testing.T) {
+	db := sql.OpenDB(execModeConnector{d: &execModeDriver{}})
+	t.Cleanup(func() { _ = db.Close() })
+
+	logger := &capturingLogger{}
+	reporter := &configMetrics{}
+	b := batchflow.NewMySQLBatchFlow(context.Background(), db, batchflow.PipelineConfig{
+		BufferSize:       4,
+		FlushSize:        10,
+		FlushInterval:    time.Second,
+		ConcurrencyLimit: -2,
+		Logger:           logger,
+		MetricsReporter:  reporter,
+	})
+	defer b.Close()
+
+	if reporter.flushSize != 4 || reporter.concurrencyLimit != 0 {
+		t.Fatalf("expected FlushSize clamped to 4 and ConcurrencyLimit to 0, got %d / %d", reporter.flushSize, reporter.concurrencyLimit)
+	}
+	fields := map[any]bool{}
+	for _, entry := range logger.byLevel("warn") {
+		fields[entry.kv["field"]] = true
+	}
+	if !fields["FlushSize"] || !fields["ConcurrencyLimit"] {
+		t.Fatalf("expected clamp warnings for FlushSize and ConcurrencyLimit, got %v", logger.byLevel("warn"))
+	}
+}
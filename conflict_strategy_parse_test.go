@@ -0,0 +1,47 @@
+package batchflow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestParseConflictStrategy(t *testing.T) {
+	cases := map[string]batchflow.ConflictStrategy{
+		"ignore":   batchflow.ConflictIgnore,
+		"REPLACE":  batchflow.ConflictReplace,
+		" Update ": batchflow.ConflictUpdate,
+		"touch":    batchflow.ConflictTouch,
+	}
+	for name, want := range cases {
+		got, err := batchflow.ParseConflictStrategy(name)
+		if err != nil || got != want {
+			t.Fatalf("ParseConflictStrategy(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+}
+
+func TestParseConflictStrategy_Unknown(t *testing.T) {
+	for _, name := range []string{"", "upsert", "ignore-update"} {
+		_, err := batchflow.ParseConflictStrategy(name)
+		var cfgErr *batchflow.ConfigError
+		if !errors.As(err, &cfgErr) || cfgErr.Field != "ConflictStrategy" {
+			t.Fatalf("%q: expected ConfigError for ConflictStrategy, got %v", name, err)
+		}
+	}
+}
+
+func TestParseSQLOperationConfig(t *testing.T) {
+	cfg, err := batchflow.ParseSQLOperationConfig("update")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg = cfg.WithConflictColumns("id").WithUpdateColumns("name")
+	if cfg.ConflictStrategy != batchflow.ConflictUpdate || !cfg.DeduplicateByConflictColumns {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if _, err := batchflow.ParseSQLOperationConfig("merge"); err == nil {
+		t.Fatalf("expected error for unknown strategy")
+	}
+}
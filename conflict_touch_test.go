@@ -0,0 +1,54 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestConflictTouch_MySQLSelfAssignment(t *testing.T) {
+	cfg := batchflow.ConflictTouchOperationConfig.WithConflictColumns("user_id")
+	schema := batchflow.NewSQLSchemaWithColumns("users", cfg,
+		batchflow.Column{Logical: "user_id", DB: "uid"}, batchflow.Column{Logical: "name"})
+	rows := []map[string]any{{"user_id": 1, "name": "alice"}, {"user_id": 2, "name": "bob"}}
+
+	for name, driver := range map[string]batchflow.SQLDriver{
+		"mysql": batchflow.DefaultMySQLDriver,
+		"mock":  batchflow.NewMockDriver("mysql"),
+	} {
+		sql, args, err := driver.GenerateInsertSQL(context.Background(), schema, rows)
+		if err != nil {
+			t.Fatalf("%s: generate sql failed: %v", name, err)
+		}
+		if !strings.HasSuffix(sql, "ON DUPLICATE KEY UPDATE uid = uid") {
+			t.Fatalf("%s: expected self-assignment on conflict column, got: %s", name, sql)
+		}
+		if !strings.HasPrefix(sql, "INSERT INTO users") || len(args) != 4 {
+			t.Fatalf("%s: unexpected sql/args: %s %v", name, sql, args)
+		}
+	}
+}
+
+func TestConflictTouch_UnsupportedDrivers(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictTouchOperationConfig, "id", "name")
+	rows := []map[string]any{{"id": 1, "name": "alice"}}
+	for _, driver := range []batchflow.SQLDriver{
+		batchflow.DefaultPostgreSQLDriver,
+		batchflow.DefaultSQLiteDriver,
+		batchflow.NewMockDriver("postgresql"),
+		batchflow.NewMockDriver("sqlite"),
+	} {
+		if _, _, err := driver.GenerateInsertSQL(context.Background(), schema, rows); err == nil {
+			t.Fatalf("%T: expected error for ConflictTouch", driver)
+		}
+		if err := batchflow.ValidateSQLSchemaForDriver(driver, schema); !errors.Is(err, batchflow.ErrInvalidSchema) {
+			t.Fatalf("%T: expected ErrInvalidSchema, got %v", driver, err)
+		}
+	}
+	if err := batchflow.ValidateSQLSchemaForDriver(batchflow.DefaultMySQLDriver, schema); err != nil {
+		t.Fatalf("mysql should support ConflictTouch: %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package batchflow
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dedupLookupMaxArgs 单条查找语句的参数上限，低于 SQLite 默认的 999，按键列数换算每段行数
+const dedupLookupMaxArgs = 900
+
+// DedupExecutor 在执行前按键列查询已存在的记录，丢弃库中已有的行后再交给内部执行器，
+// 用于缺少原生 upsert 的数据库，或需要在应用侧对既有数据去重的场景。
+//
+// 仅对 ConflictIgnore 的 SQLSchema 生效（同批内重复键保留首行）；其他策略、删除段（SQLSchema.IsDelete）与非 SQL schema 直接透传。
+// 键列为 nil 或 SQLExpr 的行无法查找，原样保留。
+// 查找与写入之间没有事务保护，并发写入同一键时仍需依赖数据库约束兜底。
+type DedupExecutor struct {
+	inner       BatchExecutor
+	db          *sql.DB
+	keyColumns  []string
+	placeholder func(argIndex int) string
+}
+
+var _ BatchExecutor = (*DedupExecutor)(nil)
+
+// NewDedupExecutor 创建查重执行器；keyColumns 为逻辑列名，为空时使用 schema 的冲突键（ConflictColumns，缺省为首列）
+func NewDedupExecutor(inner BatchExecutor, db *sql.DB, keyColumns []string) *DedupExecutor {
+	return &DedupExecutor{
+		inner:       inner,
+		db:          db,
+		keyColumns:  append([]string(nil), keyColumns...),
+		placeholder: func(int) string { return "?" },
+	}
+}
+
+// WithDollarPlaceholders 查找语句使用 $1..$N 占位符（PostgreSQL）；默认使用 ?
+func (e *DedupExecutor) WithDollarPlaceholders() *DedupExecutor {
+	e.placeholder = func(argIndex int) string { return "$" + strconv.Itoa(argIndex) }
+	return e
+}
+
+// MetricsReporter 返回内部执行器的 reporter（供 BatchFlow 探测）；内部执行器未暴露时为 nil
+func (e *DedupExecutor) MetricsReporter() MetricsReporter {
+	return executorMetricsReporter(e.inner)
+}
+
+// Logger 返回内部执行器的 Logger（供 BatchFlow 探测）；内部执行器未暴露时为 nil
+func (e *DedupExecutor) Logger() Logger {
+	return executorLogger(e.inner)
+}
+
+// ExecuteBatch 过滤掉已存在的行后委托给内部执行器；全部被过滤时不调用内部执行器
+func (e *DedupExecutor) ExecuteBatch(ctx context.Context, schema SchemaInterface, data []map[string]any) error {
+	sqlSchema, ok := schema.(*SQLSchema)
+	if !ok || sqlSchema.IsDelete() || sqlSchema.operationConfig.ConflictStrategy != ConflictIgnore || len(data) == 0 {
+		return e.inner.ExecuteBatch(ctx, schema, data)
+	}
+	keyColumns := e.keyColumns
+	if len(keyColumns) == 0 {
+		keyColumns = sqlConflictColumns(sqlSchema)
+	}
+
+	// 同批去重：保留首次出现的行；无法查找的行直接保留
+	lookup := make([]map[string]any, 0, len(data))
+	seen := make(map[string]struct{}, len(data))
+	keep := make([]bool, len(data))
+	for i, row := range data {
+		key, ok := dedupKey(row, keyColumns)
+		if !ok {
+			keep[i] = true
+			continue
+		}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		keep[i] = true
+		lookup = append(lookup, row)
+	}
+
+	existing, err := e.existingKeys(ctx, sqlSchema, keyColumns, lookup)
+	if err != nil {
+		return err
+	}
+
+	filtered := make([]map[string]any, 0, len(data))
+	for i, row := range data {
+		if !keep[i] {
+			continue
+		}
+		if key, ok := dedupKey(row, keyColumns); ok {
+			if _, found := existing[key]; found {
+				continue
+			}
+		}
+		filtered = append(filtered, row)
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return e.inner.ExecuteBatch(ctx, schema, filtered)
+}
+
+// existingKeys 分段执行 SELECT ... WHERE key IN (...)，返回库中已存在的键
+func (e *DedupExecutor) existingKeys(ctx context.Context, schema *SQLSchema, keyColumns []string, rows []map[string]any) (map[string]struct{}, error) {
+	existing := make(map[string]struct{})
+	if len(rows) == 0 {
+		return existing, nil
+	}
+	dbColumns := schema.dbColumnNames(keyColumns)
+	chunk := max(1, dedupLookupMaxArgs/len(keyColumns))
+	for start := 0; start < len(rows); start += chunk {
+		end := min(start+chunk, len(rows))
+		query, args := e.lookupQuery(schema.Name(), dbColumns, keyColumns, rows[start:end])
+		if err := e.scanKeys(ctx, query, args, keyColumns, existing); err != nil {
+			return nil, &SQLError{
+				Stage:            SQLStageExecute,
+				Table:            schema.Name(),
+				BatchSize:        end - start,
+				ConflictStrategy: schema.operationConfig.ConflictStrategy,
+				ConflictColumns:  keyColumns,
+				SQLFingerprint:   FingerprintSQL(query),
+				ArgsCount:        len(args),
+				Cause:            fmt.Errorf("dedup lookup: %w", err),
+			}
+		}
+	}
+	return existing, nil
+}
+
+// lookupQuery 单键列生成 col IN (?, ?)；复合键使用行值构造 (a, b) IN ((?, ?), (?, ?))
+func (e *DedupExecutor) lookupQuery(table string, dbColumns, keyColumns []string, rows []map[string]any) (string, []any) {
+	var sb strings.Builder
+	args := make([]any, 0, len(rows)*len(keyColumns))
+	composite := len(keyColumns) > 1
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		if composite {
+			sb.WriteByte('(')
+		}
+		for j, col := range keyColumns {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			args = append(args, row[col])
+			sb.WriteString(e.placeholder(len(args)))
+		}
+		if composite {
+			sb.WriteByte(')')
+		}
+	}
+	target := dbColumns[0]
+	if composite {
+		target = "(" + strings.Join(dbColumns, ", ") + ")"
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (%s)", strings.Join(dbColumns, ", "), table, target, sb.String())
+	return query, args
+}
+
+func (e *DedupExecutor) scanKeys(ctx context.Context, query string, args []any, keyColumns []string, existing map[string]struct{}) error {
+	rows, err := e.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	values := make([]any, len(keyColumns))
+	dest := make([]any, len(keyColumns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		row := make(map[string]any, len(keyColumns))
+		for i, col := range keyColumns {
+			row[col] = values[i]
+		}
+		if key, ok := dedupKey(row, keyColumns); ok {
+			existing[key] = struct{}{}
+		}
+	}
+	return rows.Err()
+}
+
+// dedupKey 生成与类型无关的键（驱动扫描出的类型与提交时的类型往往不同，如 int 与 int64、string 与 []byte）；
+// 任一键列为 nil 或 SQL 内联表达式时返回 ok=false
+func dedupKey(row map[string]any, keyColumns []string) (string, bool) {
+	var sb strings.Builder
+	for _, col := range keyColumns {
+		value := row[col]
+		switch v := value.(type) {
+		case nil, inlineSQLValue:
+			return "", false
+		case []byte:
+			sb.Write(v)
+		case time.Time:
+			sb.WriteString(v.UTC().Format(time.RFC3339Nano))
+		default:
+			fmt.Fprint(&sb, v)
+		}
+		sb.WriteByte(0)
+	}
+	return sb.String(), true
+}
@@ -0,0 +1,171 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestDedupExecutor_FiltersExistingKeys(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:batchflow_dedup_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER, name TEXT)"); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, name) VALUES (2, 'old'), (4, 'old')"); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	mock := batchflow.NewMockExecutor()
+	executor := batchflow.NewDedupExecutor(mock, db, []string{"id"})
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	data := []map[string]any{
+		{"id": 1, "name": "a"},
+		{"id": 2, "name": "b"},
+		{"id": 3, "name": "c"},
+		{"id": 4, "name": "d"},
+		{"id": 3, "name": "dup"},
+	}
+	if err := executor.ExecuteBatch(context.Background(), schema, data); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	batches := mock.SnapshotExecutedBatches()
+	if len(batches) != 1 {
+		t.Fatalf("expected 1 execution, got %d", len(batches))
+	}
+	var ids []any
+	for _, row := range batches[0] {
+		ids = append(ids, row["id"])
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Fatalf("expected ids [1 3] after filtering existing keys, got %v", ids)
+	}
+	if batches[0][1]["name"] != "c" {
+		t.Fatalf("expected first occurrence of duplicate key kept, got %v", batches[0][1]["name"])
+	}
+}
+
+func TestDedupExecutor_CompositeKeysAndAllExisting(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:batchflow_dedup_composite_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE memberships (org TEXT, user_id INTEGER)"); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO memberships VALUES ('acme', 1), ('acme', 2)"); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	mock := batchflow.NewMockExecutor()
+	executor := batchflow.NewDedupExecutor(mock, db, []string{"org", "user_id"})
+	schema := batchflow.NewSQLSchema("memberships", batchflow.ConflictIgnoreOperationConfig, "org", "user_id")
+	data := []map[string]any{
+		{"org": "acme", "user_id": int64(1)},
+		{"org": "acme", "user_id": int64(2)},
+	}
+	if err := executor.ExecuteBatch(context.Background(), schema, data); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if n := len(mock.SnapshotExecutedBatches()); n != 0 {
+		t.Fatalf("expected inner executor skipped when every row exists, got %d executions", n)
+	}
+}
+
+// segmentRecorder 记录每次执行是否为删除段及其 id
+type segmentRecorder struct {
+	mu       sync.Mutex
+	segments []string
+}
+
+func (r *segmentRecorder) ExecuteBatch(_ context.Context, schema batchflow.SchemaInterface, data []map[string]any) error {
+	kind := "insert"
+	if s, ok := schema.(*batchflow.SQLSchema); ok && s.IsDelete() {
+		kind = "delete"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.segments = append(r.segments, fmt.Sprintf("%s%v", kind, flattenIDs(data)))
+	return nil
+}
+
+func flattenIDs(data []map[string]any) []any {
+	ids := make([]any, 0, len(data))
+	for _, row := range data {
+		ids = append(ids, row["id"])
+	}
+	return ids
+}
+
+func TestDedupExecutor_PassesDeleteSegmentsThrough(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:batchflow_dedup_delete_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER, name TEXT)"); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, name) VALUES (2, 'old')"); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	ctx := context.Background()
+	recorder := &segmentRecorder{}
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 10, FlushInterval: time.Hour},
+		Executor: batchflow.NewDedupExecutor(recorder, db, nil),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	requests := []*batchflow.Request{
+		batchflow.NewRequest(schema).SetInt("id", 1).SetString("name", "a"),
+		batchflow.NewRequest(schema).SetInt("id", 2).SetString("name", "b"),
+		batchflow.NewRequest(schema).SetInt("id", 2).MarkDelete(),
+		batchflow.NewRequest(schema).SetInt("id", 2).MarkDelete(),
+	}
+	for _, r := range requests {
+		if err := b.Submit(ctx, r); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	// 插入段过滤掉库中已有的 2；删除段即使键已存在也必须原样交给内部执行器
+	want := []string{"insert[1]", "delete[2 2]"}
+	if !reflect.DeepEqual(recorder.segments, want) {
+		t.Fatalf("segments = %v, want %v", recorder.segments, want)
+	}
+}
+
+func TestDedupExecutor_ForwardsMetricsReporterAndLogger(t *testing.T) {
+	reporter := &concurrencyWaitMetrics{}
+	logger := &capturingLogger{}
+	exec := batchflow.NewDedupExecutor(
+		batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter).WithLogger(logger), nil, nil)
+	if exec.MetricsReporter() != batchflow.MetricsReporter(reporter) {
+		t.Fatalf("expected the inner reporter to be forwarded")
+	}
+	if exec.Logger() != batchflow.Logger(logger) {
+		t.Fatalf("expected the inner logger to be forwarded")
+	}
+}
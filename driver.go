@@ -2,10 +2,15 @@ package batchflow
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/lib/pq"
 )
 
 // SQLDriver 数据库特定的SQL生成器接口
@@ -13,22 +18,65 @@ type SQLDriver interface {
 	GenerateInsertSQL(ctx context.Context, schema *SQLSchema, data []map[string]any) (sql string, args []any, err error)
 }
 
-func prepareSQLRowsAndArgs(ctx context.Context, schema *SQLSchema, data []map[string]any) ([]map[string]any, []any, error) {
-	rows, _, err := deduplicateSQLRowsWithStatsCtx(ctx, schema, data)
+// sqlArgBinder 将行内的值转换为驱动可绑定的参数（如 PostgreSQL 数组包装）
+type sqlArgBinder func(column string, value any) (any, error)
+
+// prepareSQLRowsAndArgs 去重并按列顺序展开参数；inline 表示存在内联 SQL 值（如 DefaultExpr），
+// 此时调用方需使用 sqlValuesClause 生成逐格占位符，内联值不进入 args。
+func prepareSQLRowsAndArgs(ctx context.Context, schema *SQLSchema, data []map[string]any, bind sqlArgBinder) (rows []map[string]any, args []any, inline bool, err error) {
+	rows, _, err = deduplicateSQLRowsWithStatsCtx(ctx, schema, data)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, false, err
 	}
 	columns := schema.Columns()
-	args := make([]any, 0, len(rows)*len(columns))
+	args = make([]any, 0, len(rows)*len(columns))
 	for _, row := range rows {
 		if ctx.Err() != nil {
-			return nil, nil, ctx.Err()
+			return nil, nil, false, ctx.Err()
 		}
 		for _, col := range columns {
-			args = append(args, row[col])
+			value := row[col]
+			if _, ok := value.(inlineSQLValue); ok {
+				inline = true
+				continue
+			}
+			if bind != nil {
+				if value, err = bind(col, value); err != nil {
+					return nil, nil, false, err
+				}
+			}
+			args = append(args, value)
 		}
 	}
-	return rows, args, nil
+	return rows, args, inline, nil
+}
+
+// isSQLArrayValue 判断是否为 SetIntArray/SetStringArray 写入的数组值
+func isSQLArrayValue(value any) bool {
+	switch value.(type) {
+	case []int64, []string:
+		return true
+	default:
+		return false
+	}
+}
+
+// bindPostgreSQLArrayArg 使用 pq.Array 包装数组值，使其按 PostgreSQL 数组格式编码
+func bindPostgreSQLArrayArg(_ string, value any) (any, error) {
+	if isSQLArrayValue(value) {
+		return pq.Array(value), nil
+	}
+	return value, nil
+}
+
+// rejectSQLArrayArg 为不支持原生数组的数据库生成明确错误
+func rejectSQLArrayArg(database string) sqlArgBinder {
+	return func(column string, value any) (any, error) {
+		if isSQLArrayValue(value) {
+			return nil, fmt.Errorf("column %s: array values are not supported by %s driver", column, database)
+		}
+		return value, nil
+	}
 }
 
 func newSQLCoalescer(schema *SQLSchema) Coalescer {
@@ -51,6 +99,8 @@ func newSQLCoalescer(schema *SQLSchema) Coalescer {
 		strategy = CoalesceKeepLast
 	case ConflictUpdate:
 		strategy = CoalesceMergePresentFields
+	case ConflictTouch:
+		strategy = CoalesceKeepFirst
 	default:
 		return CoalescerFunc(func(_ context.Context, _ SchemaInterface, batch Batch) (CoalesceResult, error) {
 			return NewCoalesceResult(batch), nil
@@ -131,6 +181,16 @@ func mysqlUpdatePairs(columns []string) []string {
 	return updatePairs
 }
 
+// mysqlTouchPair 生成 ConflictTouch 的自赋值更新（首个冲突列 = 自身）
+func mysqlTouchPair(schema *SQLSchema) string {
+	col := schema.DBColumn(sqlConflictColumns(schema)[0])
+	return fmt.Sprintf("%s = %s", col, col)
+}
+
+func errConflictTouchUnsupported(database string) error {
+	return fmt.Errorf("conflict touch is not supported by %s driver", database)
+}
+
 func postgresUpdatePairs(columns []string) []string {
 	updatePairs := make([]string, len(columns))
 	for i, col := range columns {
@@ -143,12 +203,60 @@ var DefaultMySQLDriver = NewMySQLDriver()
 
 type MySQLDriver struct {
 	placeholders sync.Map // key: (colCount<<32)|batchSize  value: string
+
+	rowAlias         string // 非空时 ConflictUpdate 使用行别名形式（WithMySQLVersion/WithMySQLRowAlias）
+	rowAliasSet      bool   // 行别名由 WithMySQLRowAlias 显式指定，不受版本推断覆盖
+	quoteIdentifiers bool   // 反引号引用表名与列名（WithMySQLQuotedIdentifiers）
 }
 
 var _ SQLDriver = (*MySQLDriver)(nil)
 
-func NewMySQLDriver() *MySQLDriver {
-	return &MySQLDriver{}
+// NewMySQLDriver 创建 MySQL 驱动；不传选项时与 DefaultMySQLDriver 行为一致
+func NewMySQLDriver(opts ...MySQLOption) *MySQLDriver {
+	d := &MySQLDriver{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(d)
+		}
+	}
+	return d
+}
+
+// table 返回语句中使用的表名
+func (d *MySQLDriver) table(schema *SQLSchema) string {
+	if d.quoteIdentifiers {
+		return quoteMySQLTable(schema.Name())
+	}
+	return schema.Name()
+}
+
+// identifiers 返回语句中使用的列名（数据库列名，按需引用）
+func (d *MySQLDriver) identifiers(schema *SQLSchema, columns []string) []string {
+	names := schema.dbColumnNames(columns)
+	if !d.quoteIdentifiers {
+		return names
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteMySQLIdentifier(name)
+	}
+	return quoted
+}
+
+// updatePairs 生成 ON DUPLICATE KEY UPDATE 赋值：配置行别名时引用 alias.col，否则 VALUES(col)
+func (d *MySQLDriver) updatePairs(columns []string) []string {
+	if d.rowAlias == "" {
+		return mysqlUpdatePairs(columns)
+	}
+	alias := d.rowAlias
+	if d.quoteIdentifiers {
+		alias = quoteMySQLIdentifier(alias)
+	}
+	pairs := make([]string, len(columns))
+	for i, col := range columns {
+		pairs[i] = fmt.Sprintf("%s = %s.%s", col, alias, col)
+	}
+	return pairs
 }
 
 // GenerateInsertSQL 生成MySQL批量插入SQL
@@ -161,30 +269,46 @@ func (d *MySQLDriver) GenerateInsertSQL(ctx context.Context, schema *SQLSchema,
 	if len(columns) == 0 {
 		return "", nil, errors.New("no columns defined in schema")
 	}
-	rows, args, err := prepareSQLRowsAndArgs(ctx, schema, data)
+	rows, args, inline, err := prepareSQLRowsAndArgs(ctx, schema, data, rejectSQLArrayArg("mysql"))
 	if err != nil {
 		return "", nil, err
 	}
 
-	columnsStr := strings.Join(columns, ", ")
-	placeholders := d.generatePlaceholders(len(columns), len(rows))
+	table := d.table(schema)
+	columnsStr := strings.Join(d.identifiers(schema, columns), ", ")
+	var placeholders string
+	if inline {
+		placeholders = sqlValuesClause(columns, rows, questionPlaceholder)
+	} else {
+		placeholders = d.generatePlaceholders(len(columns), len(rows))
+	}
 
-	baseSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", schema.Name(), columnsStr, placeholders)
+	baseSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, columnsStr, placeholders)
 
 	switch schema.operationConfig.ConflictStrategy {
 	case ConflictIgnore:
-		sql := fmt.Sprintf("INSERT IGNORE INTO %s (%s) VALUES %s", schema.Name(), columnsStr, placeholders)
+		sql := fmt.Sprintf("INSERT IGNORE INTO %s (%s) VALUES %s", table, columnsStr, placeholders)
 		return sql, args, nil
 	case ConflictReplace:
-		sql := fmt.Sprintf("REPLACE INTO %s (%s) VALUES %s", schema.Name(), columnsStr, placeholders)
+		sql := fmt.Sprintf("REPLACE INTO %s (%s) VALUES %s", table, columnsStr, placeholders)
 		return sql, args, nil
 	case ConflictUpdate:
 		updateColumns := sqlUpdateColumns(schema, false)
 		if len(updateColumns) == 0 {
 			return "", nil, errors.New("no update columns defined for conflict update")
 		}
-		sql := fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", baseSQL, strings.Join(mysqlUpdatePairs(updateColumns), ", "))
+		if d.rowAlias != "" {
+			alias := d.rowAlias
+			if d.quoteIdentifiers {
+				alias = quoteMySQLIdentifier(alias)
+			}
+			baseSQL += " AS " + alias
+		}
+		sql := fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", baseSQL, strings.Join(d.updatePairs(d.identifiers(schema, updateColumns)), ", "))
 		return sql, args, nil
+	case ConflictTouch:
+		col := d.identifiers(schema, sqlConflictColumns(schema)[:1])[0]
+		return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s = %s", baseSQL, col, col), args, nil
 	default:
 		return baseSQL, args, nil
 	}
@@ -230,34 +354,49 @@ func (d *PostgreSQLDriver) GenerateInsertSQL(ctx context.Context, schema *SQLSch
 	if len(columns) == 0 {
 		return "", nil, errors.New("no columns defined in schema")
 	}
-	rows, args, err := prepareSQLRowsAndArgs(ctx, schema, data)
+	rows, args, inline, err := prepareSQLRowsAndArgs(ctx, schema, data, bindPostgreSQLArrayArg)
 	if err != nil {
 		return "", nil, err
 	}
 
-	columnsStr := strings.Join(columns, ", ")
-	placeholders := d.generatePlaceholders(len(columns), len(rows))
+	columnsStr := strings.Join(schema.dbColumnNames(columns), ", ")
+	var placeholders string
+	if inline {
+		hints := schema.operationConfig.ColumnTypeHints
+		placeholders = sqlValuesClause(columns, rows, func(argIndex int, column string) string {
+			if typ := hints[column]; typ != "" {
+				return fmt.Sprintf("$%d::%s", argIndex, typ)
+			}
+			return fmt.Sprintf("$%d", argIndex)
+		})
+	} else if len(schema.operationConfig.ColumnTypeHints) > 0 {
+		placeholders = d.generateTypedPlaceholders(columns, len(rows), schema.operationConfig.ColumnTypeHints)
+	} else {
+		placeholders = d.generatePlaceholders(len(columns), len(rows))
+	}
 
 	baseSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", schema.Name(), columnsStr, placeholders)
 
 	switch schema.operationConfig.ConflictStrategy {
 	case ConflictIgnore:
-		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", baseSQL, strings.Join(sqlConflictColumns(schema), ", "))
+		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", baseSQL, strings.Join(schema.dbColumnNames(sqlConflictColumns(schema)), ", "))
 		return sql, args, nil
 	case ConflictReplace:
 		updateColumns := sqlUpdateColumns(schema, true)
 		if len(updateColumns) == 0 {
 			return "", nil, errors.New("no update columns defined for conflict replace")
 		}
-		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, strings.Join(sqlConflictColumns(schema), ", "), strings.Join(postgresUpdatePairs(updateColumns), ", "))
+		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, strings.Join(schema.dbColumnNames(sqlConflictColumns(schema)), ", "), strings.Join(postgresUpdatePairs(schema.dbColumnNames(updateColumns)), ", "))
 		return sql, args, nil
 	case ConflictUpdate:
 		updateColumns := sqlUpdateColumns(schema, false)
 		if len(updateColumns) == 0 {
 			return "", nil, errors.New("no update columns defined for conflict update")
 		}
-		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, strings.Join(sqlConflictColumns(schema), ", "), strings.Join(postgresUpdatePairs(updateColumns), ", "))
+		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, strings.Join(schema.dbColumnNames(sqlConflictColumns(schema)), ", "), strings.Join(postgresUpdatePairs(schema.dbColumnNames(updateColumns)), ", "))
 		return sql, args, nil
+	case ConflictTouch:
+		return "", nil, errConflictTouchUnsupported("postgresql")
 	default:
 		return baseSQL, args, nil
 	}
@@ -284,6 +423,27 @@ func (d *PostgreSQLDriver) generatePlaceholders(columnCount, batchSize int) stri
 	return out
 }
 
+// generateTypedPlaceholders 生成带类型转换的占位符（如 $1::jsonb）；未提示的列保持原样。
+// 类型提示因 schema 而异，因此不进入占位符缓存。
+func (d *PostgreSQLDriver) generateTypedPlaceholders(columns []string, batchSize int, hints map[string]string) string {
+	if len(columns) == 0 || batchSize <= 0 {
+		return ""
+	}
+	columnCount := len(columns)
+	rows := make([]string, batchSize)
+	for i := 0; i < batchSize; i++ {
+		ph := make([]string, columnCount)
+		for j, col := range columns {
+			ph[j] = fmt.Sprintf("$%d", i*columnCount+j+1)
+			if typ := hints[col]; typ != "" {
+				ph[j] += "::" + typ
+			}
+		}
+		rows[i] = "(" + strings.Join(ph, ", ") + ")"
+	}
+	return strings.Join(rows, ", ")
+}
+
 var DefaultSQLiteDriver = NewSQLiteDriver()
 
 type SQLiteDriver struct {
@@ -306,13 +466,18 @@ func (d *SQLiteDriver) GenerateInsertSQL(ctx context.Context, schema *SQLSchema,
 	if len(columns) == 0 {
 		return "", nil, errors.New("no columns defined in schema")
 	}
-	rows, args, err := prepareSQLRowsAndArgs(ctx, schema, data)
+	rows, args, inline, err := prepareSQLRowsAndArgs(ctx, schema, data, rejectSQLArrayArg("sqlite"))
 	if err != nil {
 		return "", nil, err
 	}
 
-	columnsStr := strings.Join(columns, ", ")
-	placeholders := d.generatePlaceholders(len(columns), len(rows))
+	columnsStr := strings.Join(schema.dbColumnNames(columns), ", ")
+	var placeholders string
+	if inline {
+		placeholders = sqlValuesClause(columns, rows, questionPlaceholder)
+	} else {
+		placeholders = d.generatePlaceholders(len(columns), len(rows))
+	}
 
 	baseSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", schema.Name(), columnsStr, placeholders)
 
@@ -329,11 +494,13 @@ func (d *SQLiteDriver) GenerateInsertSQL(ctx context.Context, schema *SQLSchema,
 			return "", nil, errors.New("no update columns defined for conflict update")
 		}
 		updatePairs := make([]string, len(updateColumns))
-		for i, col := range updateColumns {
+		for i, col := range schema.dbColumnNames(updateColumns) {
 			updatePairs[i] = fmt.Sprintf("%s = excluded.%s", col, col)
 		}
 		sql := fmt.Sprintf("%s ON CONFLICT DO UPDATE SET %s", baseSQL, strings.Join(updatePairs, ", "))
 		return sql, args, nil
+	case ConflictTouch:
+		return "", nil, errConflictTouchUnsupported("sqlite")
 	default:
 		return baseSQL, args, nil
 	}
@@ -359,6 +526,9 @@ func (d *SQLiteDriver) generatePlaceholders(columnCount, batchSize int) string {
 
 type MockDriver struct {
 	databaseType string
+
+	mu      sync.RWMutex
+	failErr error // FailGeneration 注入的生成错误
 }
 
 var _ SQLDriver = (*MockDriver)(nil)
@@ -367,23 +537,43 @@ func NewMockDriver(databaseType string) *MockDriver {
 	return &MockDriver{databaseType: databaseType}
 }
 
+// FailGeneration 注入生成错误：之后的 GenerateInsertSQL 均返回 err，用于测试生成失败的传播路径。
+// 传入 nil 恢复正常生成。
+func (d *MockDriver) FailGeneration(err error) *MockDriver {
+	d.mu.Lock()
+	d.failErr = err
+	d.mu.Unlock()
+	return d
+}
+
 // GenerateInsertSQL 生成模拟SQL（默认MySQL语法）
 func (d *MockDriver) GenerateInsertSQL(ctx context.Context, schema *SQLSchema, data []map[string]any) (string, []any, error) {
 	if len(data) == 0 {
 		return "", nil, nil
 	}
+	d.mu.RLock()
+	failErr := d.failErr
+	d.mu.RUnlock()
+	if failErr != nil {
+		return "", nil, failErr
+	}
 
 	columns := schema.Columns()
 	if len(columns) == 0 {
 		return "", nil, errors.New("no columns defined in schema")
 	}
-	rows, args, err := prepareSQLRowsAndArgs(ctx, schema, data)
+	rows, args, inline, err := prepareSQLRowsAndArgs(ctx, schema, data, d.bindArg)
 	if err != nil {
 		return "", nil, err
 	}
 
-	columnsStr := strings.Join(columns, ", ")
-	placeholders := d.generatePlaceholders(len(columns), len(rows))
+	columnsStr := strings.Join(schema.dbColumnNames(columns), ", ")
+	var placeholders string
+	if inline {
+		placeholders = sqlValuesClause(columns, rows, questionPlaceholder)
+	} else {
+		placeholders = d.generatePlaceholders(len(columns), len(rows))
+	}
 
 	baseSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", schema.Name(), columnsStr, placeholders)
 
@@ -400,6 +590,14 @@ func (d *MockDriver) GenerateInsertSQL(ctx context.Context, schema *SQLSchema, d
 	}
 }
 
+// bindArg 按模拟的数据库类型处理数组值：postgresql 使用 pq.Array，其余类型报错
+func (d *MockDriver) bindArg(column string, value any) (any, error) {
+	if d.databaseType == "postgresql" {
+		return bindPostgreSQLArrayArg(column, value)
+	}
+	return rejectSQLArrayArg(d.databaseType)(column, value)
+}
+
 func (d *MockDriver) generateMySQLSQL(schema *SQLSchema, baseSQL, columnsStr, placeholders string, args []any) (string, []any, error) {
 	switch schema.operationConfig.ConflictStrategy {
 	case ConflictIgnore:
@@ -413,8 +611,10 @@ func (d *MockDriver) generateMySQLSQL(schema *SQLSchema, baseSQL, columnsStr, pl
 		if len(updateColumns) == 0 {
 			return "", nil, errors.New("no update columns defined for conflict update")
 		}
-		sql := fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", baseSQL, strings.Join(mysqlUpdatePairs(updateColumns), ", "))
+		sql := fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", baseSQL, strings.Join(mysqlUpdatePairs(schema.dbColumnNames(updateColumns)), ", "))
 		return sql, args, nil
+	case ConflictTouch:
+		return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", baseSQL, mysqlTouchPair(schema)), args, nil
 	default:
 		return baseSQL, args, nil
 	}
@@ -423,22 +623,24 @@ func (d *MockDriver) generateMySQLSQL(schema *SQLSchema, baseSQL, columnsStr, pl
 func (d *MockDriver) generatePostgreSQLSQL(schema *SQLSchema, baseSQL, _, _ string, args []any) (string, []any, error) {
 	switch schema.operationConfig.ConflictStrategy {
 	case ConflictIgnore:
-		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", baseSQL, strings.Join(sqlConflictColumns(schema), ", "))
+		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", baseSQL, strings.Join(schema.dbColumnNames(sqlConflictColumns(schema)), ", "))
 		return sql, args, nil
 	case ConflictReplace:
 		updateColumns := sqlUpdateColumns(schema, true)
 		if len(updateColumns) == 0 {
 			return "", nil, errors.New("no update columns defined for conflict replace")
 		}
-		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, strings.Join(sqlConflictColumns(schema), ", "), strings.Join(postgresUpdatePairs(updateColumns), ", "))
+		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, strings.Join(schema.dbColumnNames(sqlConflictColumns(schema)), ", "), strings.Join(postgresUpdatePairs(schema.dbColumnNames(updateColumns)), ", "))
 		return sql, args, nil
 	case ConflictUpdate:
 		updateColumns := sqlUpdateColumns(schema, false)
 		if len(updateColumns) == 0 {
 			return "", nil, errors.New("no update columns defined for conflict update")
 		}
-		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, strings.Join(sqlConflictColumns(schema), ", "), strings.Join(postgresUpdatePairs(updateColumns), ", "))
+		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, strings.Join(schema.dbColumnNames(sqlConflictColumns(schema)), ", "), strings.Join(postgresUpdatePairs(schema.dbColumnNames(updateColumns)), ", "))
 		return sql, args, nil
+	case ConflictTouch:
+		return "", nil, errConflictTouchUnsupported("postgresql")
 	default:
 		return baseSQL, args, nil
 	}
@@ -458,11 +660,13 @@ func (d *MockDriver) generateSQLiteSQL(schema *SQLSchema, baseSQL, columnsStr, p
 			return "", nil, errors.New("no update columns defined for conflict update")
 		}
 		updatePairs := make([]string, len(updateColumns))
-		for i, col := range updateColumns {
+		for i, col := range schema.dbColumnNames(updateColumns) {
 			updatePairs[i] = fmt.Sprintf("%s = excluded.%s", col, col)
 		}
 		sql := fmt.Sprintf("%s ON CONFLICT DO UPDATE SET %s", baseSQL, strings.Join(updatePairs, ", "))
 		return sql, args, nil
+	case ConflictTouch:
+		return "", nil, errConflictTouchUnsupported("sqlite")
 	default:
 		return baseSQL, args, nil
 	}
@@ -514,3 +718,237 @@ func (d *RedisPipelineDriver) GenerateCmds(ctx context.Context, schema SchemaInt
 	}
 	return batchCmd, nil
 }
+
+// RedisJSONDriver 使用 RedisJSON 模块将每行写为 JSON 文档：JSON.SET <prefix>:<key> $ <json>
+// 键列用于拼接 Redis key，不写入文档；其余 schema 列（含 nil -> null、嵌套 map/slice）序列化为 JSON。
+type RedisJSONDriver struct {
+	keyColumn string
+	keyPrefix string
+}
+
+var _ RedisDriver = (*RedisJSONDriver)(nil)
+
+// NewRedisJSONDriver 创建 RedisJSON 驱动；keyPrefix 为空时直接使用键列值作为 key
+func NewRedisJSONDriver(keyColumn, keyPrefix string) *RedisJSONDriver {
+	return &RedisJSONDriver{keyColumn: keyColumn, keyPrefix: keyPrefix}
+}
+
+func (d *RedisJSONDriver) GenerateCmds(ctx context.Context, schema SchemaInterface, data []map[string]any) ([]RedisCmd, error) {
+	columns := schema.Columns()
+	if !containsColumn(columns, d.keyColumn) {
+		return nil, fmt.Errorf("redis json schema must contain key column %q", d.keyColumn)
+	}
+
+	batchCmd := make([]RedisCmd, len(data))
+	for i, row := range data {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		keyValue, ok := row[d.keyColumn]
+		if !ok || keyValue == nil {
+			return nil, fmt.Errorf("row %d: missing key column %q", i, d.keyColumn)
+		}
+		doc := make(map[string]any, len(columns)-1)
+		for _, col := range columns {
+			if col == d.keyColumn {
+				continue
+			}
+			doc[col] = row[col]
+		}
+		payload, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: marshal json document: %w", i, err)
+		}
+		batchCmd[i] = RedisCmd{"JSON.SET", d.redisKey(keyValue), "$", string(payload)}
+	}
+	return batchCmd, nil
+}
+
+func (d *RedisJSONDriver) redisKey(keyValue any) string {
+	if d.keyPrefix == "" {
+		return fmt.Sprint(keyValue)
+	}
+	return d.keyPrefix + ":" + fmt.Sprint(keyValue)
+}
+
+// RedisStreamDriver 将每行写为 Redis Streams 条目：XADD <stream> [NOMKSTREAM] [MAXLEN ~ n] * field value ...
+// streamColumn 列的值作为 stream 名称，其余 schema 列按声明顺序作为条目字段；nil 值字段被跳过。
+type RedisStreamDriver struct {
+	streamColumn string
+	maxLen       int64
+	approximate  bool
+	noMkStream   bool
+}
+
+var _ RedisDriver = (*RedisStreamDriver)(nil)
+
+// NewRedisStreamDriver 创建 Redis Streams 驱动；maxLen <= 0 表示不裁剪，默认使用近似裁剪（MAXLEN ~）
+func NewRedisStreamDriver(streamColumn string, maxLen int64) *RedisStreamDriver {
+	return &RedisStreamDriver{streamColumn: streamColumn, maxLen: maxLen, approximate: true}
+}
+
+// WithApproximateTrim 设置是否使用近似裁剪（MAXLEN ~ n）；false 时使用精确裁剪（MAXLEN n），开销更高
+func (d *RedisStreamDriver) WithApproximateTrim(approximate bool) *RedisStreamDriver {
+	d.approximate = approximate
+	return d
+}
+
+// WithNoMkStream 设置 NOMKSTREAM：stream 不存在时不自动创建（XADD 返回 nil，不视为错误）
+func (d *RedisStreamDriver) WithNoMkStream(noMkStream bool) *RedisStreamDriver {
+	d.noMkStream = noMkStream
+	return d
+}
+
+func (d *RedisStreamDriver) GenerateCmds(ctx context.Context, schema SchemaInterface, data []map[string]any) ([]RedisCmd, error) {
+	columns := schema.Columns()
+	if !containsColumn(columns, d.streamColumn) {
+		return nil, fmt.Errorf("redis stream schema must contain stream column %q", d.streamColumn)
+	}
+
+	batchCmd := make([]RedisCmd, len(data))
+	for i, row := range data {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		stream, ok := row[d.streamColumn]
+		if !ok || stream == nil {
+			return nil, fmt.Errorf("row %d: missing stream column %q", i, d.streamColumn)
+		}
+		cmd := make(RedisCmd, 0, 6+2*len(columns))
+		cmd = append(cmd, "XADD", fmt.Sprint(stream))
+		if d.noMkStream {
+			cmd = append(cmd, "NOMKSTREAM")
+		}
+		if d.maxLen > 0 {
+			if d.approximate {
+				cmd = append(cmd, "MAXLEN", "~", d.maxLen)
+			} else {
+				cmd = append(cmd, "MAXLEN", d.maxLen)
+			}
+		}
+		cmd = append(cmd, "*")
+		fields := 0
+		for _, col := range columns {
+			if col == d.streamColumn || row[col] == nil {
+				continue
+			}
+			cmd = append(cmd, col, row[col])
+			fields++
+		}
+		if fields == 0 {
+			return nil, fmt.Errorf("row %d: stream entry has no fields", i)
+		}
+		batchCmd[i] = cmd
+	}
+	return batchCmd, nil
+}
+
+// Redis GEOADD 接受的坐标范围（EPSG:3857 限制纬度）
+const (
+	redisGeoMaxLon = 180.0
+	redisGeoMaxLat = 85.05112878
+)
+
+// RedisGeoDriver 将每行写入 Redis 地理集合：GEOADD <key> <lon> <lat> <member>
+// 经纬度列须为数值（整数、浮点或数值字符串）且在 Redis 接受的范围内，否则整批生成失败。
+type RedisGeoDriver struct {
+	keyColumn    string
+	lonColumn    string
+	latColumn    string
+	memberColumn string
+}
+
+var _ RedisDriver = (*RedisGeoDriver)(nil)
+
+// NewRedisGeoDriver 创建地理集合驱动，参数依次为 key、经度、纬度、成员所在的列名
+func NewRedisGeoDriver(keyColumn, lonColumn, latColumn, memberColumn string) *RedisGeoDriver {
+	return &RedisGeoDriver{keyColumn: keyColumn, lonColumn: lonColumn, latColumn: latColumn, memberColumn: memberColumn}
+}
+
+func (d *RedisGeoDriver) GenerateCmds(ctx context.Context, schema SchemaInterface, data []map[string]any) ([]RedisCmd, error) {
+	columns := schema.Columns()
+	for _, col := range []string{d.keyColumn, d.lonColumn, d.latColumn, d.memberColumn} {
+		if !containsColumn(columns, col) {
+			return nil, fmt.Errorf("redis geo schema must contain column %q", col)
+		}
+	}
+
+	batchCmd := make([]RedisCmd, len(data))
+	for i, row := range data {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		key, member := row[d.keyColumn], row[d.memberColumn]
+		if key == nil {
+			return nil, fmt.Errorf("row %d: missing key column %q", i, d.keyColumn)
+		}
+		if member == nil {
+			return nil, fmt.Errorf("row %d: missing member column %q", i, d.memberColumn)
+		}
+		lon, err := geoCoordinate(row[d.lonColumn], redisGeoMaxLon)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: longitude column %q: %w", i, d.lonColumn, err)
+		}
+		lat, err := geoCoordinate(row[d.latColumn], redisGeoMaxLat)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: latitude column %q: %w", i, d.latColumn, err)
+		}
+		batchCmd[i] = RedisCmd{"GEOADD", fmt.Sprint(key), lon, lat, member}
+	}
+	return batchCmd, nil
+}
+
+// geoCoordinate 将数值或数值字符串转换为 float64，并校验 |v| <= limit
+func geoCoordinate(value any, limit float64) (float64, error) {
+	var v float64
+	switch n := value.(type) {
+	case float64:
+		v = n
+	case float32:
+		v = float64(n)
+	case int:
+		v = float64(n)
+	case int8:
+		v = float64(n)
+	case int16:
+		v = float64(n)
+	case int32:
+		v = float64(n)
+	case int64:
+		v = float64(n)
+	case uint:
+		v = float64(n)
+	case uint8:
+		v = float64(n)
+	case uint16:
+		v = float64(n)
+	case uint32:
+		v = float64(n)
+	case uint64:
+		v = float64(n)
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		if err != nil {
+			return 0, fmt.Errorf("value %q is not numeric", n)
+		}
+		v = parsed
+	default:
+		return 0, fmt.Errorf("value of type %T is not numeric", value)
+	}
+	if math.IsNaN(v) || v < -limit || v > limit {
+		return 0, fmt.Errorf("value %v out of range [-%v, %v]", v, limit, limit)
+	}
+	return v, nil
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, col := range columns {
+		if col == column {
+			return true
+		}
+	}
+	return false
+}
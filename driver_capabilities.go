@@ -0,0 +1,121 @@
+package batchflow
+
+// PlaceholderStyle SQL 参数占位符风格
+type PlaceholderStyle string
+
+const (
+	PlaceholderQuestion PlaceholderStyle = "?"  // MySQL / SQLite / DuckDB
+	PlaceholderDollar   PlaceholderStyle = "$n" // PostgreSQL
+)
+
+// DriverCapabilities 描述 SQL 驱动（及其目标数据库）支持的能力，便于通用代码按能力分支。
+type DriverCapabilities struct {
+	// SupportsUpsert 支持 ConflictUpdate/ConflictReplace 语义
+	SupportsUpsert bool
+	// SupportsTouch 支持 ConflictTouch（自赋值空更新）
+	SupportsTouch bool
+	// SupportsReturning 目标数据库支持 INSERT ... RETURNING
+	SupportsReturning bool
+	// SupportsArrays 支持 SetIntArray/SetStringArray 写入的原生数组
+	SupportsArrays bool
+	// MaxParameters 单条语句允许的最大绑定参数数（0 表示未知/不限）
+	MaxParameters int
+	// PlaceholderStyle 生成 SQL 使用的占位符风格
+	PlaceholderStyle PlaceholderStyle
+}
+
+// SQLDriverCapabilities 可选扩展接口：SQL 驱动声明自身能力。
+// 以扩展接口形式提供，避免破坏已有的自定义 SQLDriver 实现。
+type SQLDriverCapabilities interface {
+	Capabilities() DriverCapabilities
+}
+
+var (
+	mysqlCapabilities = DriverCapabilities{
+		SupportsUpsert:   true,
+		SupportsTouch:    true,
+		MaxParameters:    65535,
+		PlaceholderStyle: PlaceholderQuestion,
+	}
+	postgreSQLCapabilities = DriverCapabilities{
+		SupportsUpsert:    true,
+		SupportsReturning: true,
+		SupportsArrays:    true,
+		MaxParameters:     65535,
+		PlaceholderStyle:  PlaceholderDollar,
+	}
+	sqliteCapabilities = DriverCapabilities{
+		SupportsUpsert:    true,
+		SupportsReturning: true, // SQLite >= 3.35
+		MaxParameters:     32766,
+		PlaceholderStyle:  PlaceholderQuestion,
+	}
+	duckDBCapabilities = DriverCapabilities{
+		SupportsUpsert:    true,
+		SupportsReturning: true,
+		PlaceholderStyle:  PlaceholderQuestion,
+	}
+)
+
+func (d *MySQLDriver) Capabilities() DriverCapabilities      { return mysqlCapabilities }
+func (d *PostgreSQLDriver) Capabilities() DriverCapabilities { return postgreSQLCapabilities }
+func (d *SQLiteDriver) Capabilities() DriverCapabilities     { return sqliteCapabilities }
+func (d *DuckDBDriver) Capabilities() DriverCapabilities     { return duckDBCapabilities }
+
+// Capabilities 按模拟的数据库类型返回对应能力；未知类型按 MySQL 语法处理
+func (d *MockDriver) Capabilities() DriverCapabilities {
+	switch d.databaseType {
+	case "postgresql":
+		return postgreSQLCapabilities
+	case "sqlite":
+		return sqliteCapabilities
+	default:
+		return mysqlCapabilities
+	}
+}
+
+// CapabilitiesOf 返回驱动声明的能力；未实现 SQLDriverCapabilities 时 ok=false
+func CapabilitiesOf(driver SQLDriver) (caps DriverCapabilities, ok bool) {
+	if cp, ok := driver.(SQLDriverCapabilities); ok && cp != nil {
+		return cp.Capabilities(), true
+	}
+	return DriverCapabilities{}, false
+}
+
+// ValidateSQLSchemaForDriver 在启动阶段校验 schema 配置与驱动能力是否匹配，尽早暴露问题：
+// - ConflictUpdate/ConflictReplace 需要 SupportsUpsert，ConflictTouch 需要 SupportsTouch
+// - 单行参数数（列数）不得超过 MaxParameters（返回 *BatchTooLargeError）
+// 驱动未声明能力时不做校验。其余错误为 *SchemaError，errors.Is(err, ErrInvalidSchema) 成立。
+func ValidateSQLSchemaForDriver(driver SQLDriver, schema *SQLSchema) error {
+	if schema == nil {
+		return &SchemaError{Reason: "schema is nil", Err: ErrInvalidSchema}
+	}
+	caps, ok := CapabilitiesOf(driver)
+	if !ok {
+		return nil
+	}
+	switch schema.operationConfig.ConflictStrategy {
+	case ConflictUpdate, ConflictReplace:
+		if !caps.SupportsUpsert {
+			return &SchemaError{SchemaName: schema.Name(), Reason: "driver does not support upsert", Err: ErrInvalidSchema}
+		}
+	case ConflictTouch:
+		if !caps.SupportsTouch {
+			return &SchemaError{SchemaName: schema.Name(), Reason: "driver does not support conflict touch", Err: ErrInvalidSchema}
+		}
+	}
+	if caps.MaxParameters > 0 && len(schema.Columns()) > caps.MaxParameters {
+		// 单行即超限，任何批次都无法执行
+		return &BatchTooLargeError{Schema: schema.Name(), Params: len(schema.Columns()), Limit: caps.MaxParameters}
+	}
+	return nil
+}
+
+// checkSQLParamLimit 在执行前校验生成的参数数是否超过驱动上限；驱动未声明能力时跳过
+func checkSQLParamLimit(driver SQLDriver, schema *SQLSchema, params int) error {
+	caps, ok := CapabilitiesOf(driver)
+	if !ok || caps.MaxParameters <= 0 || params <= caps.MaxParameters {
+		return nil
+	}
+	return &BatchTooLargeError{Schema: schema.Name(), Params: params, Limit: caps.MaxParameters}
+}
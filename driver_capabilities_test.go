@@ -0,0 +1,73 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestDefaultDrivers_Capabilities(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver batchflow.SQLDriver
+		want   batchflow.DriverCapabilities
+	}{
+		{"mysql", batchflow.DefaultMySQLDriver, batchflow.DriverCapabilities{
+			SupportsUpsert: true, SupportsTouch: true, MaxParameters: 65535, PlaceholderStyle: batchflow.PlaceholderQuestion,
+		}},
+		{"postgresql", batchflow.DefaultPostgreSQLDriver, batchflow.DriverCapabilities{
+			SupportsUpsert: true, SupportsReturning: true, SupportsArrays: true, MaxParameters: 65535, PlaceholderStyle: batchflow.PlaceholderDollar,
+		}},
+		{"sqlite", batchflow.DefaultSQLiteDriver, batchflow.DriverCapabilities{
+			SupportsUpsert: true, SupportsReturning: true, MaxParameters: 32766, PlaceholderStyle: batchflow.PlaceholderQuestion,
+		}},
+		{"mock postgresql", batchflow.NewMockDriver("postgresql"), batchflow.DriverCapabilities{
+			SupportsUpsert: true, SupportsReturning: true, SupportsArrays: true, MaxParameters: 65535, PlaceholderStyle: batchflow.PlaceholderDollar,
+		}},
+	}
+	for _, tt := range tests {
+		got, ok := batchflow.CapabilitiesOf(tt.driver)
+		if !ok {
+			t.Fatalf("%s: expected driver to declare capabilities", tt.name)
+		}
+		if got != tt.want {
+			t.Fatalf("%s: capabilities=%+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+type plainSQLDriver struct{}
+
+func (plainSQLDriver) GenerateInsertSQL(context.Context, *batchflow.SQLSchema, []map[string]any) (string, []any, error) {
+	return "", nil, nil
+}
+
+type noUpsertDriver struct{ plainSQLDriver }
+
+func (noUpsertDriver) Capabilities() batchflow.DriverCapabilities {
+	return batchflow.DriverCapabilities{MaxParameters: 2}
+}
+
+func TestValidateSQLSchemaForDriver(t *testing.T) {
+	upsert := batchflow.NewSQLSchema("users", batchflow.ConflictUpdateOperationConfig, "id", "name")
+	if err := batchflow.ValidateSQLSchemaForDriver(batchflow.DefaultMySQLDriver, upsert); err != nil {
+		t.Fatalf("mysql should accept ConflictUpdate: %v", err)
+	}
+	if err := batchflow.ValidateSQLSchemaForDriver(plainSQLDriver{}, upsert); err != nil {
+		t.Fatalf("drivers without capabilities should be skipped: %v", err)
+	}
+
+	err := batchflow.ValidateSQLSchemaForDriver(noUpsertDriver{}, upsert)
+	var schemaErr *batchflow.SchemaError
+	if !errors.Is(err, batchflow.ErrInvalidSchema) || !errors.As(err, &schemaErr) || schemaErr.SchemaName != "users" {
+		t.Fatalf("expected upsert rejection as SchemaError, got %v", err)
+	}
+
+	wide := batchflow.NewSQLSchema("wide", batchflow.ConflictIgnoreOperationConfig, "a", "b", "c")
+	var tooLarge *batchflow.BatchTooLargeError
+	if err := batchflow.ValidateSQLSchemaForDriver(noUpsertDriver{}, wide); !errors.As(err, &tooLarge) || tooLarge.Params != 3 || tooLarge.Limit != 2 {
+		t.Fatalf("expected max parameters rejection, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package batchflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var DefaultDuckDBDriver = NewDuckDBDriver()
+
+// DuckDBDriver 生成 DuckDB 多行 INSERT（? 占位符），冲突语义使用与 PostgreSQL 相同的 ON CONFLICT 子句。
+// 限制：
+// - ConflictUpdate/ConflictReplace 要求冲突列上有 PRIMARY KEY 或 UNIQUE 约束；
+// - 同一条语句中同一冲突键出现两次时 DuckDB 拒绝执行（不能在同一命令中更新同一行两次），
+// 需启用 WithDeduplicateByConflictColumns 在批内合并；
+// - 不支持 ConflictTouch 与 SetIntArray/SetStringArray 写入的数组值。
+type DuckDBDriver struct {
+	placeholders sync.Map // key: (colCount<<32)|batchSize  value: string
+}
+
+var _ SQLDriver = (*DuckDBDriver)(nil)
+
+func NewDuckDBDriver() *DuckDBDriver {
+	return &DuckDBDriver{}
+}
+
+// GenerateInsertSQL 生成DuckDB批量插入SQL
+func (d *DuckDBDriver) GenerateInsertSQL(ctx context.Context, schema *SQLSchema, data []map[string]any) (string, []any, error) {
+	if len(data) == 0 {
+		return "", nil, nil
+	}
+
+	columns := schema.Columns()
+	if len(columns) == 0 {
+		return "", nil, errors.New("no columns defined in schema")
+	}
+	rows, args, inline, err := prepareSQLRowsAndArgs(ctx, schema, data, rejectSQLArrayArg("duckdb"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	columnsStr := strings.Join(schema.dbColumnNames(columns), ", ")
+	var placeholders string
+	if inline {
+		placeholders = sqlValuesClause(columns, rows, questionPlaceholder)
+	} else {
+		placeholders = d.generatePlaceholders(len(columns), len(rows))
+	}
+
+	baseSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", schema.Name(), columnsStr, placeholders)
+	conflictColumns := strings.Join(schema.dbColumnNames(sqlConflictColumns(schema)), ", ")
+
+	switch schema.operationConfig.ConflictStrategy {
+	case ConflictIgnore:
+		return fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", baseSQL, conflictColumns), args, nil
+	case ConflictReplace:
+		updateColumns := sqlUpdateColumns(schema, true)
+		if len(updateColumns) == 0 {
+			return "", nil, errors.New("no update columns defined for conflict replace")
+		}
+		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, conflictColumns, strings.Join(postgresUpdatePairs(schema.dbColumnNames(updateColumns)), ", "))
+		return sql, args, nil
+	case ConflictUpdate:
+		updateColumns := sqlUpdateColumns(schema, false)
+		if len(updateColumns) == 0 {
+			return "", nil, errors.New("no update columns defined for conflict update")
+		}
+		sql := fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", baseSQL, conflictColumns, strings.Join(postgresUpdatePairs(schema.dbColumnNames(updateColumns)), ", "))
+		return sql, args, nil
+	case ConflictTouch:
+		return "", nil, errConflictTouchUnsupported("duckdb")
+	default:
+		return baseSQL, args, nil
+	}
+}
+
+func (d *DuckDBDriver) generatePlaceholders(columnCount, batchSize int) string {
+	if columnCount <= 0 || batchSize <= 0 {
+		return ""
+	}
+	key := (uint64(columnCount) << 32) | uint64(batchSize)
+	if v, ok := d.placeholders.Load(key); ok {
+		return v.(string)
+	}
+	singleRow := "(" + strings.Repeat("?, ", columnCount-1) + "?)"
+	rows := make([]string, batchSize)
+	for i := range rows {
+		rows[i] = singleRow
+	}
+	out := strings.Join(rows, ", ")
+	d.placeholders.Store(key, out)
+	return out
+}
@@ -0,0 +1,96 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestDuckDBDriver_GenerateInsertSQL(t *testing.T) {
+	data := []map[string]any{
+		{"id": 1, "name": "a", "score": 10},
+		{"id": 2, "name": "b", "score": 20},
+	}
+	tests := []struct {
+		name   string
+		config batchflow.SQLOperationConfig
+		want   string
+	}{
+		{
+			name:   "ignore",
+			config: batchflow.ConflictIgnoreOperationConfig,
+			want:   "INSERT INTO events (id, name, score) VALUES (?, ?, ?), (?, ?, ?) ON CONFLICT (id) DO NOTHING",
+		},
+		{
+			name:   "update",
+			config: batchflow.ConflictUpdateOperationConfig,
+			want:   "INSERT INTO events (id, name, score) VALUES (?, ?, ?), (?, ?, ?) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, score = EXCLUDED.score",
+		},
+		{
+			name:   "replace",
+			config: batchflow.ConflictReplaceOperationConfig,
+			want:   "INSERT INTO events (id, name, score) VALUES (?, ?, ?), (?, ?, ?) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, score = EXCLUDED.score",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := batchflow.NewSQLSchema("events", tt.config, "id", "name", "score")
+			sql, args, err := batchflow.DefaultDuckDBDriver.GenerateInsertSQL(context.Background(), schema, data)
+			if err != nil {
+				t.Fatalf("GenerateInsertSQL: %v", err)
+			}
+			if sql != tt.want {
+				t.Fatalf("got  %s\nwant %s", sql, tt.want)
+			}
+			if len(args) != 6 || args[0] != 1 || args[1] != "a" || args[5] != 20 {
+				t.Fatalf("unexpected args: %#v", args)
+			}
+		})
+	}
+}
+
+func TestDuckDBDriver_Unsupported(t *testing.T) {
+	touch := batchflow.NewSQLSchema("events", batchflow.ConflictTouchOperationConfig, "id")
+	if _, _, err := batchflow.DefaultDuckDBDriver.GenerateInsertSQL(context.Background(), touch, []map[string]any{{"id": 1}}); err == nil || !strings.Contains(err.Error(), "duckdb") {
+		t.Fatalf("expected conflict touch to be rejected, got %v", err)
+	}
+	if err := batchflow.ValidateSQLSchemaForDriver(batchflow.DefaultDuckDBDriver, touch); err == nil {
+		t.Fatal("expected ValidateSQLSchemaForDriver to reject conflict touch")
+	}
+
+	arrays := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id", "tags")
+	req := batchflow.NewRequest(arrays).SetInt64("id", 1).SetStringArray("tags", []string{"a"})
+	if _, _, err := batchflow.DefaultDuckDBDriver.GenerateInsertSQL(context.Background(), arrays, []map[string]any{req.Columns()}); err == nil {
+		t.Fatal("expected array values to be rejected")
+	}
+}
+
+func TestNewDuckDBBatchFlow(t *testing.T) {
+	d := &execModeDriver{}
+	db := sql.OpenDB(execModeConnector{d: d})
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	b := batchflow.NewDuckDBBatchFlow(ctx, db, batchflow.PipelineConfig{BufferSize: 10, FlushSize: 2, FlushInterval: time.Hour})
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	for i := 1; i <= 2; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", int64(i)).SetString("name", "n")); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	waitUntilEmpty(t, b)
+	if err := b.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	want := "INSERT INTO events (id, name) VALUES (?, ?), (?, ?) ON CONFLICT (id) DO NOTHING"
+	if len(d.queries) != 1 || d.queries[0] != want {
+		t.Fatalf("expected %q, got %v", want, d.queries)
+	}
+}
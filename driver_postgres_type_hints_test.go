@@ -0,0 +1,48 @@
+package batchflow_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestPostgreSQLDriver_ColumnTypeHints(t *testing.T) {
+	cfg := batchflow.ConflictIgnoreOperationConfig.
+		WithConflictColumns("id").
+		WithColumnTypeHints(map[string]string{
+			"payload": "jsonb",
+			"tags":    "int[]",
+		})
+	schema := batchflow.NewSQLSchema("events", cfg, "id", "payload", "tags")
+
+	sql, args, err := batchflow.DefaultPostgreSQLDriver.GenerateInsertSQL(context.Background(), schema, []map[string]any{
+		{"id": 1, "payload": `{"a":1}`, "tags": "{1,2}"},
+		{"id": 2, "payload": `{"b":2}`, "tags": "{3}"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateInsertSQL failed: %v", err)
+	}
+	want := "VALUES ($1, $2::jsonb, $3::int[]), ($4, $5::jsonb, $6::int[]) ON CONFLICT (id) DO NOTHING"
+	if !strings.Contains(sql, want) {
+		t.Fatalf("unexpected typed placeholders:\n got: %s\nwant: %s", sql, want)
+	}
+	if len(args) != 6 {
+		t.Fatalf("expected 6 args, got %d", len(args))
+	}
+}
+
+func TestPostgreSQLDriver_NoTypeHintsKeepsPlainPlaceholders(t *testing.T) {
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id", "payload")
+
+	sql, _, err := batchflow.DefaultPostgreSQLDriver.GenerateInsertSQL(context.Background(), schema, []map[string]any{
+		{"id": 1, "payload": "x"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateInsertSQL failed: %v", err)
+	}
+	if !strings.Contains(sql, "VALUES ($1, $2)") || strings.Contains(sql, "::") {
+		t.Fatalf("unexpected placeholders without hints: %s", sql)
+	}
+}
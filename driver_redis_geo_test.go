@@ -0,0 +1,77 @@
+package batchflow_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRedisGeoDriver_GenerateCmds(t *testing.T) {
+	driver := batchflow.NewRedisGeoDriver("key", "lon", "lat", "member")
+	schema := batchflow.NewSchema("stores", "key", "lon", "lat", "member")
+	rows := []map[string]any{
+		{"key": "stores:sh", "lon": 121.4737, "lat": 31.2304, "member": "store-1"},
+		{"key": "stores:sh", "lon": "121.5", "lat": int64(31), "member": "store-2"},
+	}
+
+	cmds, err := driver.GenerateCmds(context.Background(), schema, rows)
+	if err != nil {
+		t.Fatalf("GenerateCmds failed: %v", err)
+	}
+	want := []batchflow.RedisCmd{
+		{"GEOADD", "stores:sh", 121.4737, 31.2304, "store-1"},
+		{"GEOADD", "stores:sh", 121.5, float64(31), "store-2"},
+	}
+	if !reflect.DeepEqual(cmds, want) {
+		t.Fatalf("unexpected commands:\n got: %#v\nwant: %#v", cmds, want)
+	}
+}
+
+func TestRedisGeoDriver_RejectsInvalidCoordinates(t *testing.T) {
+	driver := batchflow.NewRedisGeoDriver("key", "lon", "lat", "member")
+	schema := batchflow.NewSchema("stores", "key", "lon", "lat", "member")
+
+	cases := []struct {
+		name string
+		row  map[string]any
+		want string
+	}{
+		{"non-numeric longitude", map[string]any{"key": "k", "lon": "east", "lat": 1.0, "member": "m"}, "longitude"},
+		{"non-numeric latitude", map[string]any{"key": "k", "lon": 1.0, "lat": true, "member": "m"}, "latitude"},
+		{"latitude out of range", map[string]any{"key": "k", "lon": 1.0, "lat": 89.0, "member": "m"}, "out of range"},
+		{"missing member", map[string]any{"key": "k", "lon": 1.0, "lat": 1.0}, "member"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := driver.GenerateCmds(context.Background(), schema, []map[string]any{tc.row})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+	if _, err := driver.GenerateCmds(context.Background(), batchflow.NewSchema("stores", "key", "lon"), nil); err == nil {
+		t.Fatal("expected error when schema lacks geo columns")
+	}
+}
+
+func TestRedisGeoDriver_ExecutesThroughPipeline(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+	rec := &pipelineRecorder{}
+	client.AddHook(rec)
+
+	executor := batchflow.NewRedisThrottledBatchExecutorWithDriver(client, batchflow.NewRedisGeoDriver("key", "lon", "lat", "member"))
+	schema := batchflow.NewSchema("stores", "key", "lon", "lat", "member")
+	rows := []map[string]any{{"key": "stores:sh", "lon": 121.4737, "lat": 31.2304, "member": "store-1"}}
+	if err := executor.ExecuteBatch(context.Background(), schema, rows); err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	want := [][]any{{"GEOADD", "stores:sh", 121.4737, 31.2304, "store-1"}}
+	if !reflect.DeepEqual(rec.args, want) {
+		t.Fatalf("unexpected pipeline commands: %#v", rec.args)
+	}
+}
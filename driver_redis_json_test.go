@@ -0,0 +1,65 @@
+package batchflow_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRedisJSONDriver_GenerateCmds(t *testing.T) {
+	schema := batchflow.NewSchema("profiles", "id", "name", "age", "tags", "address", "nickname")
+	driver := batchflow.NewRedisJSONDriver("id", "user")
+
+	cmds, err := driver.GenerateCmds(context.Background(), schema, []map[string]any{
+		{
+			"id":       42,
+			"name":     "alice",
+			"age":      30,
+			"tags":     []string{"a", "b"},
+			"address":  map[string]any{"city": "Paris"},
+			"nickname": nil,
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateCmds failed: %v", err)
+	}
+	want := batchflow.RedisCmd{
+		"JSON.SET",
+		"user:42",
+		"$",
+		`{"address":{"city":"Paris"},"age":30,"name":"alice","nickname":null,"tags":["a","b"]}`,
+	}
+	if len(cmds) != 1 || !reflect.DeepEqual(cmds[0], want) {
+		t.Fatalf("unexpected command:\n got: %#v\nwant: %#v", cmds, want)
+	}
+
+	// 与 RedisBatchProcessor 相同的变参方式构建命令，确认参数逐个展开
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+	cmd := client.Pipeline().Do(context.Background(), cmds[0]...)
+	if !reflect.DeepEqual(cmd.Args(), []any(want)) {
+		t.Fatalf("unexpected pipeline args: %#v", cmd.Args())
+	}
+}
+
+func TestRedisJSONDriver_Errors(t *testing.T) {
+	driver := batchflow.NewRedisJSONDriver("id", "")
+
+	if _, err := driver.GenerateCmds(context.Background(), batchflow.NewSchema("profiles", "name"), nil); err == nil {
+		t.Fatalf("expected error when schema lacks key column")
+	}
+	schema := batchflow.NewSchema("profiles", "id", "name")
+	if _, err := driver.GenerateCmds(context.Background(), schema, []map[string]any{{"name": "x"}}); err == nil {
+		t.Fatalf("expected error when row lacks key value")
+	}
+	cmds, err := driver.GenerateCmds(context.Background(), schema, []map[string]any{{"id": "7", "name": "x"}})
+	if err != nil {
+		t.Fatalf("GenerateCmds failed: %v", err)
+	}
+	if cmds[0][1] != "7" {
+		t.Fatalf("expected unprefixed key, got %v", cmds[0][1])
+	}
+}
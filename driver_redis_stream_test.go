@@ -0,0 +1,62 @@
+package batchflow_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRedisStreamDriver_GenerateCmds(t *testing.T) {
+	schema := batchflow.NewSchema("events", "stream", "type", "payload")
+	rows := []map[string]any{{"stream": "orders", "type": "created", "payload": `{"id":1}`}}
+
+	cases := []struct {
+		name   string
+		driver *batchflow.RedisStreamDriver
+		want   batchflow.RedisCmd
+	}{
+		{
+			name:   "approximate trim",
+			driver: batchflow.NewRedisStreamDriver("stream", 1000),
+			want:   batchflow.RedisCmd{"XADD", "orders", "MAXLEN", "~", int64(1000), "*", "type", "created", "payload", `{"id":1}`},
+		},
+		{
+			name:   "exact trim with nomkstream",
+			driver: batchflow.NewRedisStreamDriver("stream", 50).WithApproximateTrim(false).WithNoMkStream(true),
+			want:   batchflow.RedisCmd{"XADD", "orders", "NOMKSTREAM", "MAXLEN", int64(50), "*", "type", "created", "payload", `{"id":1}`},
+		},
+		{
+			name:   "no trim",
+			driver: batchflow.NewRedisStreamDriver("stream", 0),
+			want:   batchflow.RedisCmd{"XADD", "orders", "*", "type", "created", "payload", `{"id":1}`},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmds, err := tc.driver.GenerateCmds(context.Background(), schema, rows)
+			if err != nil {
+				t.Fatalf("GenerateCmds failed: %v", err)
+			}
+			if len(cmds) != 1 || !reflect.DeepEqual(cmds[0], tc.want) {
+				t.Fatalf("unexpected command:\n got: %#v\nwant: %#v", cmds, tc.want)
+			}
+		})
+	}
+}
+
+func TestRedisStreamDriver_Errors(t *testing.T) {
+	driver := batchflow.NewRedisStreamDriver("stream", 100)
+
+	if _, err := driver.GenerateCmds(context.Background(), batchflow.NewSchema("events", "type"), nil); err == nil {
+		t.Fatal("expected error when schema lacks stream column")
+	}
+	schema := batchflow.NewSchema("events", "stream", "type")
+	if _, err := driver.GenerateCmds(context.Background(), schema, []map[string]any{{"type": "x"}}); err == nil {
+		t.Fatal("expected error when row lacks stream name")
+	}
+	if _, err := driver.GenerateCmds(context.Background(), schema, []map[string]any{{"stream": "s", "type": nil}}); err == nil {
+		t.Fatal("expected error when entry has no fields")
+	}
+}
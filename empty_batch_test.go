@@ -0,0 +1,93 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+// emptySQLDriver 模拟驱动在过滤后没有可写入的行，生成空语句
+type emptySQLDriver struct{}
+
+func (emptySQLDriver) GenerateInsertSQL(context.Context, *batchflow.SQLSchema, []map[string]any) (string, []any, error) {
+	return "", nil, nil
+}
+
+func TestSQLBatchProcessor_EmptyOperationsAreNoop(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver)
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	if err := processor.ExecuteOperations(ctx, nil); err != nil {
+		t.Fatalf("expected nil operations to be a no-op, got %v", err)
+	}
+	ops, err := processor.GenerateOperations(ctx, schema, nil)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if err := processor.ExecuteOperations(ctx, ops); err != nil {
+		t.Fatalf("expected operations for empty data to be a no-op, got %v", err)
+	}
+	if err := processor.ExecuteOperations(ctx, batchflow.Operations{42}); err == nil {
+		t.Fatal("expected invalid operation type to still fail")
+	}
+}
+
+func TestBatchFlow_EmptyGeneratedOperationsDoNotError(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	var executed atomic.Int32
+	processor := batchflow.NewSQLBatchProcessor(db, emptySQLDriver{}).
+		WithStatementSampler(1, func(string, int, time.Duration) { executed.Add(1) })
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 2, FlushInterval: time.Hour},
+		Executor: batchflow.NewThrottledBatchExecutor(processor),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+	errs := b.ErrorChan(10)
+	flushed := make(chan error, 1)
+	b.WithAfterFlush(func(_ context.Context, _ batchflow.SchemaInterface, _ int, err error) {
+		flushed <- err
+	})
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 2; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	select {
+	case err := <-flushed:
+		if err != nil {
+			t.Fatalf("expected empty operations to succeed, got %v", err)
+		}
+		if n := executed.Load(); n != 0 {
+			t.Fatalf("expected no statement sent to the database, got %d", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected flush to complete")
+	}
+	select {
+	case err := <-errs:
+		t.Fatalf("expected no flush error, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -1,6 +1,9 @@
 package batchflow
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrEmptyRequest 空请求错误
@@ -15,6 +18,15 @@ var (
 	// ErrMissingColumn 缺少列错误
 	ErrMissingColumn = errors.New("missing required column")
 
+	// ErrUnknownColumn 请求设置了 schema 未声明的列（SQLOperationConfig.StrictColumns）
+	ErrUnknownColumn = errors.New("unknown column")
+
+	// ErrDeleteUnsupported 删除请求（Request.MarkDelete）用于非 SQLSchema，或 SQL 驱动未实现 SQLDeleteGenerator
+	ErrDeleteUnsupported = errors.New("delete not supported")
+
+	// ErrValueCountMismatch NewRequestFromValues 的值数量与 schema 列数不一致
+	ErrValueCountMismatch = errors.New("value count does not match schema columns")
+
 	// ErrInvalidColumnType 无效的列类型错误
 	ErrInvalidColumnType = errors.New("invalid column type")
 
@@ -23,4 +35,87 @@ var (
 
 	// ErrEmptySchemaName 空表名错误
 	ErrEmptySchemaName = errors.New("empty schema name")
+
+	// ErrValueTooLong 列值长度超过 schema 声明的最大长度
+	ErrValueTooLong = errors.New("value too long")
+
+	// ErrBatchTooLarge 批次绑定参数数超过驱动上限
+	ErrBatchTooLarge = errors.New("batch too large")
+
+	// ErrSubmitTimeout SubmitWithTimeout 在超时前未能将请求写入缓冲区
+	ErrSubmitTimeout = errors.New("submit timeout: buffer did not accept request in time")
+
+	// ErrPanic flush 过程中（执行器/驱动）发生 panic
+	ErrPanic = errors.New("panic during flush")
+
+	// ErrNonFiniteFloat 浮点列值为 NaN 或 ±Inf（PipelineConfig.NonFiniteFloats 为 NonFiniteReject 时）
+	ErrNonFiniteFloat = errors.New("non-finite float")
+
+	// ErrPoolExhausted SQLBatchProcessor.WithMaxConnWait 设置的时限内未能从连接池取得连接
+	ErrPoolExhausted = errors.New("connection pool exhausted")
+
+	// ErrBatchDeadline 单次 ExecuteBatch（含全部语句与重试）超过 WithBatchDeadline 设置的总时限
+	ErrBatchDeadline = errors.New("batch deadline exceeded")
 )
+
+// SchemaError 描述 schema 层面的校验失败，Err 为对应的哨兵错误（如 ErrInvalidSchema）。
+// 可用 errors.Is 判断类别，用 errors.As 取得 SchemaName/Reason。
+type SchemaError struct {
+	SchemaName string
+	Reason     string
+	Err        error
+}
+
+func (e *SchemaError) Error() string {
+	if e.SchemaName == "" {
+		return fmt.Sprintf("%v: %s", e.Err, e.Reason)
+	}
+	return fmt.Sprintf("%v: schema %q: %s", e.Err, e.SchemaName, e.Reason)
+}
+
+func (e *SchemaError) Unwrap() error { return e.Err }
+
+// ColumnError 描述列层面的校验失败，Err 为对应的哨兵错误（如 ErrMissingColumn）。
+// Column 为空表示无法定位到具体列（例如 schema 未声明任何列）。
+type ColumnError struct {
+	SchemaName string
+	Column     string
+	Reason     string
+	Err        error
+}
+
+func (e *ColumnError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("%v: schema %q: %s", e.Err, e.SchemaName, e.Reason)
+	}
+	return fmt.Sprintf("%v: schema %q column %q: %s", e.Err, e.SchemaName, e.Column, e.Reason)
+}
+
+func (e *ColumnError) Unwrap() error { return e.Err }
+
+// BatchTooLargeError 描述绑定参数数超过驱动 MaxParameters 的批次，errors.Is(err, ErrBatchTooLarge) 成立。
+// 该错误不可重试：应调小 FlushSize 或精简 schema 列数。
+type BatchTooLargeError struct {
+	Schema string
+	Params int
+	Limit  int
+}
+
+func (e *BatchTooLargeError) Error() string {
+	return fmt.Sprintf("%v: schema %q needs %d parameters, driver limit is %d", ErrBatchTooLarge, e.Schema, e.Params, e.Limit)
+}
+
+func (e *BatchTooLargeError) Unwrap() error { return ErrBatchTooLarge }
+
+// PanicError 由 flush 中恢复的 panic 转换而来，经 ErrorChan/OnError 投递，errors.Is(err, ErrPanic) 成立。
+// Stack 为 panic 发生时的 goroutine 栈，便于定位出错的驱动或处理器。
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrPanic, e.Value)
+}
+
+func (e *PanicError) Unwrap() error { return ErrPanic }
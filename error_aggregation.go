@@ -0,0 +1,116 @@
+package batchflow
+
+import (
+	"fmt"
+	"time"
+)
+
+// AggregatedError 聚合窗口内 Error() 文本相同的一组错误（PipelineConfig.ErrorAggregationWindow）。
+// Unwrap 返回最后一次出现的错误，errors.Is/As 可继续识别底层原因。
+type AggregatedError struct {
+	Count   int
+	First   error
+	Last    error
+	FirstAt time.Time
+	LastAt  time.Time
+}
+
+func (e *AggregatedError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%d identical errors in %s: %v", e.Count, e.LastAt.Sub(e.FirstAt), e.Last)
+}
+
+func (e *AggregatedError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Last
+}
+
+// aggregateErrors 按固定窗口聚合错误：窗口从首个错误开始计时，到期后按首次出现顺序统一投递。
+// src 不再有新错误（finished 关闭，含 BatchFlow 退出后仍在执行的异步批次）时先消费已缓冲的错误，再投递未满窗口的聚合结果。
+func (b *BatchFlow) aggregateErrors(src <-chan error, finished <-chan struct{}, out chan<- error, window time.Duration) {
+	defer close(b.aggDone)
+	pending := make(map[string]*AggregatedError)
+	var order []string
+	var timer *time.Timer
+	var timerC <-chan time.Time
+
+	add := func(err error) {
+		if err == nil {
+			return
+		}
+		now := time.Now()
+		key := err.Error()
+		if agg, ok := pending[key]; ok {
+			agg.Count++
+			agg.Last = err
+			agg.LastAt = now
+			return
+		}
+		pending[key] = &AggregatedError{Count: 1, First: err, Last: err, FirstAt: now, LastAt: now}
+		order = append(order, key)
+		if timer == nil {
+			timer = time.NewTimer(window)
+			timerC = timer.C
+		}
+	}
+	emit := func(blocking bool) {
+		for _, key := range order {
+			agg := pending[key]
+			var err error = agg
+			if agg.Count == 1 {
+				err = agg.First
+			}
+			if !blocking {
+				b.sendOrDrop(out, err)
+				continue
+			}
+			select {
+			case out <- err:
+			case <-finished:
+				blocking = false
+				b.sendOrDrop(out, err)
+			}
+		}
+		clear(pending)
+		order = order[:0]
+		timer, timerC = nil, nil
+	}
+
+	for {
+		select {
+		case err := <-src:
+			add(err)
+		case <-timerC:
+			emit(true)
+		case <-finished:
+			for {
+				select {
+				case err := <-src:
+					add(err)
+				default:
+					if timer != nil {
+						timer.Stop()
+					}
+					emit(false)
+					return
+				}
+			}
+		}
+	}
+}
+
+// sendOrDrop 非阻塞投递；通道已满时记录丢弃，与 go-pipeline 错误通道写满时的行为一致
+func (b *BatchFlow) sendOrDrop(out chan<- error, err error) {
+	select {
+	case out <- err:
+	default:
+		b.logger.Warn("batchflow error dropped", "reason", "error_chan_full", "error", err)
+		if pmr, ok := b.metricsReporter.(PipelineMetricsReporter); ok && pmr != nil {
+			pmr.IncDropped("error_chan_full")
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_ErrorAggregationWindowCoalescesIdenticalErrors(t *testing.T) {
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:             256,
+			FlushSize:              1,
+			FlushInterval:          time.Hour,
+			ErrorAggregationWindow: time.Second,
+		},
+		Executor: batchflow.NewThrottledBatchExecutor(nonRetryProcessor{}),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+	errs := b.ErrorChan(256)
+
+	schema := batchflow.NewSchema("events", "id")
+	for i := 0; i < 100; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	var got error
+	select {
+	case got = <-errs:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected an aggregated error")
+	}
+	var agg *batchflow.AggregatedError
+	if !errors.As(got, &agg) {
+		t.Fatalf("expected *AggregatedError, got %T: %v", got, got)
+	}
+	if agg.Count != 100 {
+		t.Fatalf("expected 100 aggregated errors, got %d", agg.Count)
+	}
+	if agg.First == nil || agg.Last == nil || agg.First.Error() != agg.Last.Error() {
+		t.Fatalf("expected identical first/last errors, got %v / %v", agg.First, agg.Last)
+	}
+	var batchErr *batchflow.BatchError
+	if !errors.As(got, &batchErr) {
+		t.Fatalf("expected aggregated error to unwrap to *BatchError, got %v", got)
+	}
+
+	select {
+	case extra := <-errs:
+		t.Fatalf("expected a single delivery, got extra %v", extra)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBatchFlow_ErrorAggregationSingleErrorDeliveredAsIs(t *testing.T) {
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:             16,
+			FlushSize:              1,
+			FlushInterval:          time.Hour,
+			ErrorAggregationWindow: 20 * time.Millisecond,
+		},
+		Executor: batchflow.NewThrottledBatchExecutor(nonRetryProcessor{}),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+	errs := b.ErrorChan(16)
+
+	_ = b.Submit(ctx, batchflow.NewRequest(batchflow.NewSchema("events", "id")).SetInt("id", 1))
+	select {
+	case got := <-errs:
+		var agg *batchflow.AggregatedError
+		if errors.As(got, &agg) {
+			t.Fatalf("single error should not be wrapped, got %v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected error delivery")
+	}
+}
+
+func TestPipelineConfig_ValidateErrorAggregationWindow(t *testing.T) {
+	err := batchflow.PipelineConfig{ErrorAggregationWindow: -time.Second}.Validate()
+	var cfgErr *batchflow.ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "ErrorAggregationWindow" {
+		t.Fatalf("expected ConfigError for ErrorAggregationWindow, got %v", err)
+	}
+}
+
+func TestBatchFlow_ErrorAggregationIncludesFlushesFinishingAfterClose(t *testing.T) {
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:             16,
+			FlushSize:              2,
+			FlushInterval:          time.Hour,
+			ErrorAggregationWindow: time.Hour,
+		},
+		Executor: slowFailExecutor{delay: 200 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	errs := b.ErrorChan(8)
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 4; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	// 未满的窗口在两个异步批次都失败后才投递
+	select {
+	case err := <-errs:
+		var agg *batchflow.AggregatedError
+		if !errors.As(err, &agg) || agg.Count != 2 {
+			t.Fatalf("expected both async failures aggregated, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected the aggregated error after Close")
+	}
+}
@@ -2,9 +2,11 @@ package batchflow
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
 	"strings"
 	"sync"
+	"syscall"
 
 	mysqlDriver "github.com/go-sql-driver/mysql"
 	"github.com/lib/pq"
@@ -23,6 +25,12 @@ const (
 	ErrorReasonIO              = "io"
 	ErrorReasonSyntax          = "syntax"
 	ErrorReasonNonRetryable    = "non_retryable"
+	// ErrorReasonNonIdempotent 错误本可重试，但 RetryConfig.IdempotentOnly 开启且 schema 非幂等，不再重试
+	ErrorReasonNonIdempotent = "non_idempotent"
+	// ErrorReasonPoolExhausted WithMaxConnWait 时限内未取得连接（ErrPoolExhausted）
+	ErrorReasonPoolExhausted = "pool_exhausted"
+	// ErrorReasonRetryableSQLState 仅由 ThrottledBatchExecutor.WithRetryableSQLStates 命中时产生
+	ErrorReasonRetryableSQLState = "retryable_sqlstate"
 )
 
 // ErrorClassifier recognizes backend-specific errors and returns a low-cardinality reason.
@@ -89,6 +97,12 @@ func ClassifyError(err error) (retryable bool, reason string) {
 	if errors.Is(err, context.DeadlineExceeded) {
 		return false, ErrorReasonContextDeadline
 	}
+	if isBadConnError(err) {
+		return true, ErrorReasonConnection
+	}
+	if errors.Is(err, ErrPoolExhausted) {
+		return true, ErrorReasonPoolExhausted
+	}
 	if retryable, reason, ok := classifyMySQLError(err); ok {
 		return retryable, reason
 	}
@@ -115,6 +129,9 @@ func ClassifyError(err error) (retryable bool, reason string) {
 		return true, ErrorReasonDeadlock
 	case containsAny(s, "lock wait timeout", "lock timeout", "could not obtain lock"):
 		return true, ErrorReasonLockTimeout
+	case containsAny(s, "database is locked", "database table is locked", "sqlite_busy", "sqlite_locked"):
+		// SQLite 写锁竞争（SQLITE_BUSY/SQLITE_LOCKED）；按字符串识别，避免核心包依赖 cgo 驱动
+		return true, ErrorReasonLockTimeout
 	case strings.Contains(s, "timeout"):
 		return true, ErrorReasonTimeout
 	case strings.Contains(s, "connection") && containsAny(s, "refused", "reset", "closed", "failure", "unavailable"):
@@ -143,6 +160,14 @@ func snapshotCustomErrorClassifiers() []ErrorClassifier {
 	return out
 }
 
+// isBadConnError 识别连接已失效类错误（空闲连接在数据库重启后首次使用常见），重连后可重试
+func isBadConnError(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysqlDriver.ErrInvalidConn) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
 func classifyMySQLError(err error) (retryable bool, reason string, ok bool) {
 	var mysqlErr *mysqlDriver.MySQLError
 	if !errors.As(err, &mysqlErr) {
@@ -201,6 +226,32 @@ func classifyRedisError(err error) (retryable bool, reason string, ok bool) {
 	}
 }
 
+// sqlStateOf 从驱动错误中提取 SQLSTATE：*pq.Error 取 Code，*mysql.MySQLError 取 SQLState 字段（全零视为缺失），
+// 其他实现 SQLState() string 的错误（如 pgx 的 *pgconn.PgError）按接口读取。
+func sqlStateOf(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.SQLState(), pqErr.Code != ""
+	}
+	var mysqlErr *mysqlDriver.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if mysqlErr.SQLState == [5]byte{} {
+			return "", false
+		}
+		return string(mysqlErr.SQLState[:]), true
+	}
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		if code := stateErr.SQLState(); code != "" {
+			return code, true
+		}
+	}
+	return "", false
+}
+
 func unwrapBatchCause(err error) error {
 	var batchErr *BatchError
 	if errors.As(err, &batchErr) && batchErr.Cause != nil {
@@ -49,6 +49,12 @@ func TestClassifyErrorReasons(t *testing.T) {
 			retryable: true,
 			reason:    batchflow.ErrorReasonLockTimeout,
 		},
+		{
+			name:      "sqlite busy",
+			err:       errors.New("database is locked (5) (SQLITE_BUSY)"),
+			retryable: true,
+			reason:    batchflow.ErrorReasonLockTimeout,
+		},
 		{
 			name:      "timeout",
 			err:       errors.New("i/o timeout"),
@@ -30,6 +30,13 @@ type Options struct {
 	ExecuteBuckets   []float64
 	BatchSizeBuckets []float64
 
+	// 原生直方图（Native Histogram）。NativeHistogramBucketFactor > 1 时，
+	// enqueue/assemble/execute 三个耗时直方图额外输出原生直方图，便于在服务端计算任意分位数；
+	// 经典桶仍保留，未开启 native histogram 的抓取端不受影响。为 0 时仅输出经典桶（默认）。
+	NativeHistogramBucketFactor     float64
+	NativeHistogramMaxBucketNumber  uint32        // 原生桶数量上限，0 表示不限制
+	NativeHistogramMinResetDuration time.Duration // 超过桶上限时的最短重置间隔
+
 	// 是否启用管道级指标（PipelineMetricsReporter）
 	EnablePipelineMetrics bool
 }
@@ -51,16 +58,21 @@ type Metrics struct {
 	assembleDuration     *prometheus.HistogramVec
 	executeDuration      *prometheus.HistogramVec
 	batchSize            *prometheus.HistogramVec
+	batchBytes           *prometheus.HistogramVec
 	sqlGeneratedRows     *prometheus.HistogramVec
 	sqlGeneratedArgs     *prometheus.HistogramVec
 	operationItems       *prometheus.HistogramVec
 	operationArgs        *prometheus.HistogramVec
 	pipelineFlushSize    *prometheus.HistogramVec
 	schemaGroupsPerFlush *prometheus.HistogramVec
+	concurrencyWait      *prometheus.HistogramVec
+	submitBlocked        *prometheus.HistogramVec
 
 	// Gauge
 	executorConcurrency *prometheus.GaugeVec
 	queueLength         *prometheus.GaugeVec
+	queueHighWater      *prometheus.GaugeVec
+	pipelineConfig      *prometheus.GaugeVec
 	inflightBatches     *prometheus.GaugeVec
 
 	// SQL 指标
@@ -104,6 +116,7 @@ func NewMetrics(opts Options) *Metrics {
 	labelsAssemble := []string{"database"}
 	labelsExecute := []string{"database"}
 	labelsBatchSize := []string{"database"}
+	labelsBatchBytes := []string{"database"}
 	labelsSQLRows := []string{"database", "kind"}
 	labelsSQLArgs := []string{"database"}
 	labelsOperationItems := []string{"database", "backend", "operation", "kind"}
@@ -111,8 +124,11 @@ func NewMetrics(opts Options) *Metrics {
 	labelsSQLDedup := []string{"database", "strategy", "kind"}
 	labelsFlushSize := []string{"database"}
 	labelsSchemaGroups := []string{"database"}
+	labelsConcurrencyWait := []string{"database"}
+	labelsSubmitBlocked := []string{"database"}
 	labelsConcurrency := []string{"database"}
 	labelsQueue := []string{"database"}
+	labelsConfig := []string{"database", "setting"}
 	labelsInflight := []string{"database"}
 	labelsPipelineDequeue := []string{"database"}
 	labelsPipelineProcess := []string{"database", "status"}
@@ -127,15 +143,19 @@ func NewMetrics(opts Options) *Metrics {
 		labelsAssemble = append(labelsAssemble, "instance_id")
 		labelsExecute = append(labelsExecute, "instance_id")
 		labelsBatchSize = append(labelsBatchSize, "instance_id")
+		labelsBatchBytes = append(labelsBatchBytes, "instance_id")
 		labelsSQLRows = []string{"database", "instance_id", "kind"}
 		labelsSQLArgs = []string{"database", "instance_id"}
 		labelsOperationItems = []string{"database", "instance_id", "backend", "operation", "kind"}
 		labelsOperationArgs = []string{"database", "instance_id", "backend", "operation"}
 		labelsSQLDedup = []string{"database", "instance_id", "strategy", "kind"}
 		labelsFlushSize = append(labelsFlushSize, "instance_id")
+		labelsConcurrencyWait = append(labelsConcurrencyWait, "instance_id")
+		labelsSubmitBlocked = append(labelsSubmitBlocked, "instance_id")
 		labelsSchemaGroups = append(labelsSchemaGroups, "instance_id")
 		labelsConcurrency = append(labelsConcurrency, "instance_id")
 		labelsQueue = append(labelsQueue, "instance_id")
+		labelsConfig = []string{"database", "instance_id", "setting"}
 		labelsInflight = append(labelsInflight, "instance_id")
 		labelsPipelineDequeue = append(labelsPipelineDequeue, "instance_id")
 		labelsPipelineProcess = []string{"database", "instance_id", "status"}
@@ -204,6 +224,10 @@ func NewMetrics(opts Options) *Metrics {
 				Help:        "Latency from submit to enqueue",
 				Buckets:     opts.EnqueueBuckets,
 				ConstLabels: cl,
+
+				NativeHistogramBucketFactor:     opts.NativeHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  opts.NativeHistogramMaxBucketNumber,
+				NativeHistogramMinResetDuration: opts.NativeHistogramMinResetDuration,
 			},
 			labelsEnqueue,
 		),
@@ -215,6 +239,10 @@ func NewMetrics(opts Options) *Metrics {
 				Help:        "Time to assemble a batch",
 				Buckets:     opts.AssembleBuckets,
 				ConstLabels: cl,
+
+				NativeHistogramBucketFactor:     opts.NativeHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  opts.NativeHistogramMaxBucketNumber,
+				NativeHistogramMinResetDuration: opts.NativeHistogramMinResetDuration,
 			},
 			labelsAssemble,
 		),
@@ -226,6 +254,10 @@ func NewMetrics(opts Options) *Metrics {
 				Help:        "Execute duration per batch (includes retry/backoff)",
 				Buckets:     opts.ExecuteBuckets,
 				ConstLabels: cl,
+
+				NativeHistogramBucketFactor:     opts.NativeHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  opts.NativeHistogramMaxBucketNumber,
+				NativeHistogramMinResetDuration: opts.NativeHistogramMinResetDuration,
 			},
 			labelsExecute,
 		),
@@ -240,6 +272,17 @@ func NewMetrics(opts Options) *Metrics {
 			},
 			labelsBatchSize,
 		),
+		batchBytes: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace:   ns,
+				Subsystem:   ss,
+				Name:        "batch_bytes",
+				Help:        "Approximate in-memory size of assembled batch rows in bytes",
+				Buckets:     prometheus.ExponentialBuckets(256, 4, 12), // 256B ~ 1GiB
+				ConstLabels: cl,
+			},
+			labelsBatchBytes,
+		),
 		sqlGeneratedRows: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace:   ns,
@@ -316,6 +359,28 @@ func NewMetrics(opts Options) *Metrics {
 			},
 			labelsSchemaGroups,
 		),
+		concurrencyWait: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace:   ns,
+				Subsystem:   ss,
+				Name:        "concurrency_wait_seconds",
+				Help:        "Time a batch waited for a ConcurrencyLimit permit before executing",
+				Buckets:     opts.ExecuteBuckets,
+				ConstLabels: cl,
+			},
+			labelsConcurrencyWait,
+		),
+		submitBlocked: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace:   ns,
+				Subsystem:   ss,
+				Name:        "submit_blocked_seconds",
+				Help:        "Time Submit blocked on a full data channel (backpressure), including abandoned waits",
+				Buckets:     opts.EnqueueBuckets,
+				ConstLabels: cl,
+			},
+			labelsSubmitBlocked,
+		),
 		executorConcurrency: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace:   ns,
@@ -336,6 +401,26 @@ func NewMetrics(opts Options) *Metrics {
 			},
 			labelsQueue,
 		),
+		queueHighWater: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   ns,
+				Subsystem:   ss,
+				Name:        "pipeline_queue_high_water",
+				Help:        "Peak pipeline queue length observed since the previous flush",
+				ConstLabels: cl,
+			},
+			labelsQueue,
+		),
+		pipelineConfig: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   ns,
+				Subsystem:   ss,
+				Name:        "pipeline_config",
+				Help:        "Configured pipeline settings (buffer_size, flush_size, flush_interval_seconds, concurrency_limit)",
+				ConstLabels: cl,
+			},
+			labelsConfig,
+		),
 		inflightBatches: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace:   ns,
@@ -394,6 +479,7 @@ func NewMetrics(opts Options) *Metrics {
 		m.assembleDuration,
 		m.executeDuration,
 		m.batchSize,
+		m.batchBytes,
 		m.sqlGeneratedRows,
 		m.sqlGeneratedArgs,
 		m.operationItems,
@@ -401,8 +487,12 @@ func NewMetrics(opts Options) *Metrics {
 		m.sqlDeduplicatedRows,
 		m.pipelineFlushSize,
 		m.schemaGroupsPerFlush,
+		m.concurrencyWait,
+		m.submitBlocked,
 		m.executorConcurrency,
 		m.queueLength,
+		m.queueHighWater,
+		m.pipelineConfig,
 		m.inflightBatches,
 	)
 
@@ -687,6 +777,36 @@ func (m *Metrics) observeSchemaGroups(database, instanceID string, n int) {
 	m.schemaGroupsPerFlush.WithLabelValues(labels...).Observe(float64(n))
 }
 
+func (m *Metrics) observeConcurrencyWait(database, instanceID string, d time.Duration) {
+	var labels []string
+	if hasLabel(m.concurrencyWait, "instance_id") {
+		labels = []string{database, instanceID}
+	} else {
+		labels = []string{database}
+	}
+	m.concurrencyWait.WithLabelValues(labels...).Observe(d.Seconds())
+}
+
+func (m *Metrics) observeBatchBytes(database, instanceID string, n int) {
+	var labels []string
+	if hasLabel(m.batchBytes, "instance_id") {
+		labels = []string{database, instanceID}
+	} else {
+		labels = []string{database}
+	}
+	m.batchBytes.WithLabelValues(labels...).Observe(float64(n))
+}
+
+func (m *Metrics) observeSubmitBlocked(database, instanceID string, d time.Duration) {
+	var labels []string
+	if hasLabel(m.submitBlocked, "instance_id") {
+		labels = []string{database, instanceID}
+	} else {
+		labels = []string{database}
+	}
+	m.submitBlocked.WithLabelValues(labels...).Observe(d.Seconds())
+}
+
 func (m *Metrics) setConcurrency(database, instanceID string, n int) {
 	var labels []string
 	if hasLabel(m.executorConcurrency, "instance_id") {
@@ -707,6 +827,26 @@ func (m *Metrics) setQueueLen(database, instanceID string, n int) {
 	m.queueLength.WithLabelValues(labels...).Set(float64(n))
 }
 
+func (m *Metrics) setQueueHighWater(database, instanceID string, n int) {
+	var labels []string
+	if hasLabel(m.queueHighWater, "instance_id") {
+		labels = []string{database, instanceID}
+	} else {
+		labels = []string{database}
+	}
+	m.queueHighWater.WithLabelValues(labels...).Set(float64(n))
+}
+
+func (m *Metrics) setPipelineConfig(database, instanceID, setting string, v float64) {
+	var labels []string
+	if hasLabel(m.pipelineConfig, "instance_id") {
+		labels = []string{database, instanceID, setting}
+	} else {
+		labels = []string{database, setting}
+	}
+	m.pipelineConfig.WithLabelValues(labels...).Set(v)
+}
+
 func (m *Metrics) incInflight(database, instanceID string) {
 	var labels []string
 	if hasLabel(m.inflightBatches, "instance_id") {
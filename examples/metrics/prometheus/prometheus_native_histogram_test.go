@@ -0,0 +1,87 @@
+package prometheusmetrics
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestNewMetrics_NativeHistograms(t *testing.T) {
+	durationFamilies := []string{
+		"batchflow_native_enqueue_latency_seconds",
+		"batchflow_native_batch_assemble_duration_seconds",
+		"batchflow_native_execute_duration_seconds",
+	}
+
+	observe := func(metrics *Metrics) {
+		reporter := NewReporter(metrics, "mysql", "worker_a")
+		reporter.ObserveEnqueueLatency(3 * time.Millisecond)
+		reporter.ObserveBatchAssemble(5 * time.Millisecond)
+		reporter.ObserveExecuteDuration("events", 10, 20*time.Millisecond, "success")
+		reporter.ObserveBatchSize(10)
+	}
+
+	t.Run("classic buckets by default", func(t *testing.T) {
+		metrics := NewMetrics(Options{Namespace: "batchflow_native"})
+		observe(metrics)
+
+		histograms := gatherHistograms(t, metrics)
+		for _, name := range durationFamilies {
+			h, ok := histograms[name]
+			if !ok {
+				t.Fatalf("histogram %s not found", name)
+			}
+			if h.Schema != nil {
+				t.Fatalf("%s: expected no native histogram schema, got %d", name, h.GetSchema())
+			}
+			if len(h.GetBucket()) == 0 {
+				t.Fatalf("%s: expected classic buckets", name)
+			}
+		}
+	})
+
+	t.Run("native histograms when bucket factor set", func(t *testing.T) {
+		metrics := NewMetrics(Options{
+			Namespace:                       "batchflow_native",
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  100,
+			NativeHistogramMinResetDuration: time.Hour,
+		})
+		observe(metrics)
+
+		histograms := gatherHistograms(t, metrics)
+		for _, name := range durationFamilies {
+			h, ok := histograms[name]
+			if !ok {
+				t.Fatalf("histogram %s not found", name)
+			}
+			if h.Schema == nil {
+				t.Fatalf("%s: expected native histogram schema", name)
+			}
+			if len(h.GetPositiveSpan()) == 0 {
+				t.Fatalf("%s: expected native histogram spans", name)
+			}
+			if len(h.GetBucket()) == 0 {
+				t.Fatalf("%s: expected classic buckets to be kept alongside native buckets", name)
+			}
+		}
+
+		// 批大小不是耗时指标，保持经典桶
+		if h := histograms["batchflow_native_batch_size"]; h != nil && h.Schema != nil {
+			t.Fatal("batch_size: expected classic histogram only")
+		}
+	})
+}
+
+func gatherHistograms(t *testing.T, metrics *Metrics) map[string]*dto.Histogram {
+	t.Helper()
+	histograms := make(map[string]*dto.Histogram)
+	for _, family := range gather(t, metrics.registry) {
+		if family.GetType() != dto.MetricType_HISTOGRAM || len(family.GetMetric()) == 0 {
+			continue
+		}
+		histograms[family.GetName()] = family.GetMetric()[0].GetHistogram()
+	}
+	return histograms
+}
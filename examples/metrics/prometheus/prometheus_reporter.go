@@ -212,13 +212,61 @@ func (r *Reporter) ObserveSchemaGroupsPerFlush(n int) {
 	r.m.observeSchemaGroups(r.Database, r.InstanceID, n)
 }
 
+// ObserveConcurrencyWait 记录批次等待并发令牌的时长（batchflow.ConcurrencyMetricsReporter）。
+func (r *Reporter) ObserveConcurrencyWait(d time.Duration) {
+	if r.m == nil {
+		return
+	}
+	r.m.observeConcurrencyWait(r.Database, r.InstanceID, d)
+}
+
+// ObserveBatchBytes 记录组装后批次行数据的近似字节数（batchflow.BatchBytesMetricsReporter）。
+func (r *Reporter) ObserveBatchBytes(n int) {
+	if r.m == nil {
+		return
+	}
+	r.m.observeBatchBytes(r.Database, r.InstanceID, n)
+}
+
+// ObserveSubmitBlocked 记录 Submit 因数据通道已满而阻塞的时长（batchflow.SubmitBlockedMetricsReporter）。
+func (r *Reporter) ObserveSubmitBlocked(d time.Duration) {
+	if r.m == nil {
+		return
+	}
+	r.m.observeSubmitBlocked(r.Database, r.InstanceID, d)
+}
+
+// ObserveQueueHighWater 记录两次 flush 之间的队列高水位（batchflow.QueueMetricsReporter）。
+func (r *Reporter) ObserveQueueHighWater(n int) {
+	if r.m == nil {
+		return
+	}
+	r.m.setQueueHighWater(r.Database, r.InstanceID, n)
+}
+
+// SetConfig 导出管道配置（batchflow.ConfigMetricsReporter），每项一个 setting 标签。
+func (r *Reporter) SetConfig(bufferSize, flushSize uint32, flushInterval time.Duration, concurrencyLimit int) {
+	if r.m == nil {
+		return
+	}
+	r.m.setPipelineConfig(r.Database, r.InstanceID, "buffer_size", float64(bufferSize))
+	r.m.setPipelineConfig(r.Database, r.InstanceID, "flush_size", float64(flushSize))
+	r.m.setPipelineConfig(r.Database, r.InstanceID, "flush_interval_seconds", flushInterval.Seconds())
+	r.m.setPipelineConfig(r.Database, r.InstanceID, "concurrency_limit", float64(concurrencyLimit))
+}
+
 // 确保实现接口
 var (
-	_ batchflow.MetricsReporter          = (*Reporter)(nil)
-	_ batchflow.SQLMetricsReporter       = (*Reporter)(nil)
-	_ batchflow.OperationMetricsReporter = (*Reporter)(nil)
-	_ batchflow.PipelineMetricsReporter  = (*Reporter)(nil)
-	_ batchflow.BatchFlowMetricsReporter = (*Reporter)(nil)
+	_ batchflow.MetricsReporter              = (*Reporter)(nil)
+	_ batchflow.SQLMetricsReporter           = (*Reporter)(nil)
+	_ batchflow.OperationMetricsReporter     = (*Reporter)(nil)
+	_ batchflow.PipelineMetricsReporter      = (*Reporter)(nil)
+	_ batchflow.BatchFlowMetricsReporter     = (*Reporter)(nil)
+	_ batchflow.ConcurrencyMetricsReporter   = (*Reporter)(nil)
+	_ batchflow.QueueMetricsReporter         = (*Reporter)(nil)
+	_ batchflow.SubmitBlockedMetricsReporter = (*Reporter)(nil)
+	_ batchflow.BatchBytesMetricsReporter    = (*Reporter)(nil)
+	_ batchflow.ConfigMetricsReporter        = (*Reporter)(nil)
 )
 
 func conflictStrategyLabel(strategy batchflow.ConflictStrategy) string {
@@ -229,6 +277,8 @@ func conflictStrategyLabel(strategy batchflow.ConflictStrategy) string {
 		return "replace"
 	case batchflow.ConflictUpdate:
 		return "update"
+	case batchflow.ConflictTouch:
+		return "touch"
 	default:
 		return "unknown"
 	}
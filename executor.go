@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -15,6 +18,7 @@ import (
 // BatchExecutor 批量执行器接口 - 所有数据库驱动的统一入口
 type BatchExecutor interface {
 	// ExecuteBatch 执行批量操作
+	// data 及其中的行 map 由 BatchFlow 池化复用，仅在本次调用期间有效；需要保留请自行拷贝。
 	ExecuteBatch(ctx context.Context, schema SchemaInterface, data []map[string]any) error
 }
 
@@ -45,6 +49,43 @@ type ConcurrencyCapable[T any] interface {
 	WithConcurrencyLimit(int) T
 }
 
+// ConcurrencyLimitUpdater 可选扩展接口：运行时调整执行并发上限（BatchFlow.UpdateConfig 通过类型断言使用）。
+// 实现需保证与进行中的 ExecuteBatch 并发安全。
+type ConcurrencyLimitUpdater interface {
+	UpdateConcurrencyLimit(limit int)
+}
+
+// concurrencyLimitForwarder 由包装型执行器实现：报告内部执行器是否真正支持并发上限的查询与调整，
+// 供 BatchFlow 区分"包装器转发"与"内部执行器不支持"。
+type concurrencyLimitForwarder interface {
+	forwardsConcurrencyLimit() bool
+}
+
+// forwardsConcurrencyLimit 报告执行器的并发上限方法是否生效：非包装型执行器恒为 true，
+// 包装型执行器取决于其内部执行器
+func forwardsConcurrencyLimit(executor BatchExecutor) bool {
+	if f, ok := executor.(concurrencyLimitForwarder); ok {
+		return f.forwardsConcurrencyLimit()
+	}
+	return true
+}
+
+// executorMetricsReporter 探测执行器暴露的 reporter；未暴露时为 nil（包装型执行器据此转发内部执行器的配置）
+func executorMetricsReporter(executor BatchExecutor) MetricsReporter {
+	if mp, ok := executor.(interface{ MetricsReporter() MetricsReporter }); ok {
+		return mp.MetricsReporter()
+	}
+	return nil
+}
+
+// executorLogger 探测执行器暴露的 Logger；未暴露时为 nil
+func executorLogger(executor BatchExecutor) Logger {
+	if lp, ok := executor.(interface{ Logger() Logger }); ok {
+		return lp.Logger()
+	}
+	return nil
+}
+
 // ThrottledBatchExecutor 通用批量执行器
 // 架构：ThrottledBatchExecutor -> BatchProcessor -> backend driver/client
 //
@@ -57,7 +98,8 @@ type ThrottledBatchExecutor struct {
 	metricsReporter MetricsReporter // 性能指标报告器
 	observer        Observer
 	coalescer       Coalescer
-	semaphore       chan struct{} // 可选信号量，用于限制 ExecuteBatch 并发
+	logger          Logger                // 诊断日志（默认 Noop）
+	semaphore       *concurrencySemaphore // 限制 ExecuteBatch 并发；容量 0 表示不限流，调整上限时原地修改而不替换
 
 	// 重试配置（默认关闭）
 	retryEnabled     bool
@@ -65,18 +107,34 @@ type ThrottledBatchExecutor struct {
 	retryBackoffBase time.Duration
 	retryMaxBackoff  time.Duration
 	retryClassifier  func(error) (retryable bool, reason string)
+	retryJoinErrors  bool
+	retryMaxElapsed  time.Duration
+	retryIdempotent  bool                // 仅对幂等 schema 重试（RetryConfig.IdempotentOnly）
+	retrySQLStates   map[string]struct{} // WithRetryableSQLStates 配置的可重试 SQLSTATE
+
+	batchDeadline time.Duration // 单次 ExecuteBatch 的总时限（WithBatchDeadline）；0 表示不限制
+
+	onBatchSuccess BatchSuccessFunc // 批次最终成功后的回调（审计/CDC）
 }
 
+// BatchSuccessFunc 批次成功写入后的回调。rows 为实际写入的行（已经过 Coalescer 合并），
+// 仅在回调期间有效；如需异步处理请自行拷贝。
+type BatchSuccessFunc func(schema SchemaInterface, rows []map[string]any)
+
 var _ MetricsCapable[*ThrottledBatchExecutor] = (*ThrottledBatchExecutor)(nil)
 
 var _ ConcurrencyCapable[*ThrottledBatchExecutor] = (*ThrottledBatchExecutor)(nil)
 
+var _ ConcurrencyLimitUpdater = (*ThrottledBatchExecutor)(nil)
+
 var _ BatchExecutor = (*ThrottledBatchExecutor)(nil)
 
 // NewThrottledBatchExecutor 创建通用执行器（使用自定义BatchProcessor）
 func NewThrottledBatchExecutor(processor BatchProcessor) *ThrottledBatchExecutor {
 	return &ThrottledBatchExecutor{
 		processor: processor,
+		logger:    NewNoopLogger(),
+		semaphore: newConcurrencySemaphore(0),
 	}
 }
 
@@ -102,6 +160,16 @@ type RetryConfig struct {
 	MaxBackoff  time.Duration // 最大退避时长（上限）
 	// 自定义错误分类（可选）；返回是否可重试与原因标签
 	Classifier func(error) (retryable bool, reason string)
+	// PreserveAttemptErrors 为 true 时，最终失败返回 errors.Join 聚合的每轮尝试错误；
+	// 默认仅返回最后一轮错误。重试分类始终基于最近一次错误。
+	PreserveAttemptErrors bool
+	// IdempotentOnly 为 true 时仅对幂等的 schema（实现 IdempotentSchema 且返回 true）重试：
+	// 超时后实际已提交的普通 INSERT 再次执行会写入重复行。SQLSchema 除 AppendOnly 外均视为幂等，
+	// 未实现该接口的 schema（如 Redis Schema、自定义 schema）不重试。
+	IdempotentOnly bool
+	// MaxElapsedTime 限制单个批次从首轮开始的总耗时（含退避等待）；下一轮退避结束时会超出该值则不再重试，
+	// 以最近一次错误作为最终结果。<= 0 表示不限制，仅由 MaxAttempts 约束。
+	MaxElapsedTime time.Duration
 }
 
 // WithRetryConfig 启用/配置重试（仅对 ThrottledBatchExecutor 可用）
@@ -119,6 +187,9 @@ func (e *ThrottledBatchExecutor) WithRetryConfig(cfg RetryConfig) *ThrottledBatc
 	e.retryMaxAttempts = cfg.MaxAttempts
 	e.retryBackoffBase = cfg.BackoffBase
 	e.retryMaxBackoff = cfg.MaxBackoff
+	e.retryJoinErrors = cfg.PreserveAttemptErrors
+	e.retryMaxElapsed = cfg.MaxElapsedTime
+	e.retryIdempotent = cfg.IdempotentOnly
 	if cfg.Classifier != nil {
 		e.retryClassifier = cfg.Classifier
 	} else {
@@ -127,6 +198,50 @@ func (e *ThrottledBatchExecutor) WithRetryConfig(cfg RetryConfig) *ThrottledBatc
 	return e
 }
 
+// WithRetryableSQLStates 指定可重试的 SQLSTATE（如 CockroachDB 的 "40001"）。
+// 配置后，对暴露 SQLSTATE 的错误（*pq.Error、*mysql.MySQLError 或实现 SQLState() string）以列表为准：
+// 命中即可重试，未命中即不可重试，优先于分类器的消息子串匹配；无 SQLSTATE 的错误沿用原分类。
+// 重复调用会替换原列表，无参调用清空。
+func (e *ThrottledBatchExecutor) WithRetryableSQLStates(codes ...string) *ThrottledBatchExecutor {
+	if len(codes) == 0 {
+		e.retrySQLStates = nil
+		return e
+	}
+	e.retrySQLStates = make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			e.retrySQLStates[code] = struct{}{}
+		}
+	}
+	return e
+}
+
+// classifyRetry 综合分类器与 SQLSTATE 白名单给出重试决策；错误带 SQLSTATE 时白名单具有决定权
+func (e *ThrottledBatchExecutor) classifyRetry(err error) (bool, string) {
+	retryable, reason := false, "unknown"
+	if e.retryClassifier != nil {
+		retryable, reason = e.retryClassifier(err)
+	}
+	if len(e.retrySQLStates) == 0 {
+		return retryable, reason
+	}
+	code, ok := sqlStateOf(err)
+	if !ok {
+		return retryable, reason
+	}
+	if _, hit := e.retrySQLStates[strings.ToUpper(code)]; hit {
+		if !retryable {
+			reason = ErrorReasonRetryableSQLState
+		}
+		return true, reason
+	}
+	if retryable {
+		// 分类器按消息判为可重试，但 SQLSTATE 不在列表中
+		reason = ErrorReasonNonRetryable
+	}
+	return false, reason
+}
+
 /*
 默认重试分类器策略说明：
 - 对调用方外层 ctx 的取消/超时（context.Canceled/context.DeadlineExceeded）判为不可重试（final:context）。
@@ -137,11 +252,39 @@ func defaultRetryClassifier(err error) (bool, string) {
 	return ClassifyError(err)
 }
 
+// WithBatchDeadline 为每次 ExecuteBatch 设置总时限，覆盖并发令牌等待、全部语句（如逐行保存点模式的多条语句）
+// 与所有重试轮次及退避等待；SQLBatchProcessor.WithTimeout 仅约束单条语句。超时后取消剩余工作，
+// 返回的错误满足 errors.Is(err, ErrBatchDeadline)。d <= 0 表示关闭。
+func (e *ThrottledBatchExecutor) WithBatchDeadline(d time.Duration) *ThrottledBatchExecutor {
+	e.batchDeadline = max(d, 0)
+	return e
+}
+
 // ExecuteBatch 执行批量操作
 func (e *ThrottledBatchExecutor) ExecuteBatch(ctx context.Context, schema SchemaInterface, data []map[string]any) error {
 	if len(data) == 0 {
 		return nil
 	}
+	if e.batchDeadline > 0 {
+		parent := ctx
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(parent, e.batchDeadline, ErrBatchDeadline)
+		defer cancel()
+		err := e.executeBatch(ctx, schema, data)
+		return batchDeadlineError(ctx, parent, e.batchDeadline, err)
+	}
+	return e.executeBatch(ctx, schema, data)
+}
+
+// batchDeadlineError 批次总时限触发（而非调用方 ctx 结束）时，确保返回的错误可被识别为 ErrBatchDeadline
+func batchDeadlineError(ctx, parent context.Context, deadline time.Duration, err error) error {
+	if err == nil || parent.Err() != nil || !errors.Is(context.Cause(ctx), ErrBatchDeadline) || errors.Is(err, ErrBatchDeadline) {
+		return err
+	}
+	return fmt.Errorf("%w (%s): %w", ErrBatchDeadline, deadline, err)
+}
+
+func (e *ThrottledBatchExecutor) executeBatch(ctx context.Context, schema SchemaInterface, data []map[string]any) error {
 	if e.coalescer != nil {
 		result, err := e.coalescer.Coalesce(ctx, schema, data)
 		if err != nil {
@@ -158,13 +301,16 @@ func (e *ThrottledBatchExecutor) ExecuteBatch(ctx context.Context, schema Schema
 		}
 	}
 
-	// 可选并发限流：当设置了信号量时，进入前需占用一个令牌
-	if e.semaphore != nil {
-		select {
-		case e.semaphore <- struct{}{}:
-			defer func() { <-e.semaphore }()
-		case <-ctx.Done():
-			return ctx.Err()
+	// 并发限流：进入前占用一个令牌（不限流时立即获得，仅计入在途数）；仅限流时上报等待时长
+	waitStart := time.Now()
+	limited, acquireErr := e.semaphore.acquire(ctx)
+	if acquireErr != nil {
+		return acquireErr
+	}
+	defer e.semaphore.release()
+	if limited {
+		if cmr, ok := e.metricsReporter.(ConcurrencyMetricsReporter); ok && cmr != nil {
+			cmr.ObserveConcurrencyWait(time.Since(waitStart))
 		}
 	}
 
@@ -177,6 +323,7 @@ func (e *ThrottledBatchExecutor) ExecuteBatch(ctx context.Context, schema Schema
 	}
 
 	var err error
+	var attemptErrs []error
 	attempts := 1
 	if e.retryEnabled && e.retryMaxAttempts > 1 {
 		attempts = e.retryMaxAttempts
@@ -190,15 +337,25 @@ RETRY:
 			status = "success"
 			break
 		}
+		if e.retryJoinErrors {
+			attemptErrs = append(attemptErrs, err)
+		}
 
 		shouldRetry, retryErr := e.handleRetry(ctx, schema, data, result, attempt, attempts, startTime)
 		if retryErr != nil {
 			status = "fail"
 			err = retryErr
+			// 退避期间 ctx 结束：同样保留此前各轮的尝试错误
+			if len(attemptErrs) > 0 {
+				err = errors.Join(append(attemptErrs, retryErr)...)
+			}
 			break RETRY
 		}
 		if !shouldRetry {
 			status = "fail"
+			if len(attemptErrs) > 1 {
+				err = errors.Join(attemptErrs...)
+			}
 			break
 		}
 	}
@@ -206,19 +363,25 @@ RETRY:
 	if e.metricsReporter != nil {
 		e.metricsReporter.ObserveExecuteDuration(schema.Name(), len(data), time.Since(startTime), status)
 	}
+	if err == nil && e.onBatchSuccess != nil {
+		e.onBatchSuccess(schema, data)
+	}
 	return err
 }
 
+// WithOnBatchSuccess 设置批次成功回调，在 ExecuteBatch 最终成功（含重试后成功）时同步调用一次；
+// 中间失败的重试轮次不会触发。回调在执行路径内运行，应避免阻塞。
+func (e *ThrottledBatchExecutor) WithOnBatchSuccess(fn BatchSuccessFunc) *ThrottledBatchExecutor {
+	e.onBatchSuccess = fn
+	return e
+}
+
 // WithMetricsReporter 设置指标报告器
 func (e *ThrottledBatchExecutor) WithMetricsReporter(metricsReporter MetricsReporter) *ThrottledBatchExecutor {
 	e.metricsReporter = metricsReporter
 	// 注入 reporter 后，立即上报一次当前并发度（如已配置）
 	if e.metricsReporter != nil {
-		if e.semaphore != nil {
-			e.metricsReporter.SetConcurrency(cap(e.semaphore))
-		} else {
-			e.metricsReporter.SetConcurrency(0)
-		}
+		e.metricsReporter.SetConcurrency(e.ConcurrencyLimit())
 	}
 	return e
 }
@@ -238,6 +401,15 @@ func (e *ThrottledBatchExecutor) WithObservability(config ObservabilityConfig) *
 	return e
 }
 
+// WithLogger 设置诊断日志（重试、最终失败等）；传入 nil 恢复为 Noop
+func (e *ThrottledBatchExecutor) WithLogger(logger Logger) *ThrottledBatchExecutor {
+	e.logger = loggerOrNoop(logger)
+	return e
+}
+
+// Logger 获取诊断日志
+func (e *ThrottledBatchExecutor) Logger() Logger { return loggerOrNoop(e.logger) }
+
 func (e *ThrottledBatchExecutor) WithCoalescer(coalescer Coalescer) *ThrottledBatchExecutor {
 	e.coalescer = coalescer
 	return e
@@ -246,6 +418,7 @@ func (e *ThrottledBatchExecutor) WithCoalescer(coalescer Coalescer) *ThrottledBa
 type attemptResult struct {
 	preview  OperationPreview
 	err      error
+	stage    string // 失败所处阶段：BatchStageGenerate / BatchStageExecute
 	duration time.Duration
 }
 
@@ -257,7 +430,7 @@ func (e *ThrottledBatchExecutor) executeAttempt(ctx context.Context, schema Sche
 		e.reportOperationError(schema.Name(), BatchStageGenerate, err)
 		duration := time.Since(attemptStart)
 		e.observeBatchEvent(ctx, newBatchEvent(BatchStageGenerate, "fail", attempt, len(data), duration, schema.Name(), preview, err, defaultOperationErrorReason(err)))
-		return attemptResult{preview: preview, err: err, duration: duration}
+		return attemptResult{preview: preview, err: err, stage: BatchStageGenerate, duration: duration}
 	}
 
 	e.reportOperationGenerated(schema.Name(), operations, data, preview, hasPreview)
@@ -267,7 +440,7 @@ func (e *ThrottledBatchExecutor) executeAttempt(ctx context.Context, schema Sche
 		e.reportOperationError(schema.Name(), BatchStageExecute, err)
 		duration := time.Since(attemptStart)
 		e.observeBatchEvent(ctx, newBatchEvent(BatchStageExecute, "fail", attempt, len(data), duration, schema.Name(), preview, err, defaultOperationErrorReason(err)))
-		return attemptResult{preview: preview, err: err, duration: duration}
+		return attemptResult{preview: preview, err: err, stage: BatchStageExecute, duration: duration}
 	}
 
 	duration := time.Since(attemptStart)
@@ -276,14 +449,23 @@ func (e *ThrottledBatchExecutor) executeAttempt(ctx context.Context, schema Sche
 }
 
 func (e *ThrottledBatchExecutor) handleRetry(ctx context.Context, schema SchemaInterface, data []map[string]any, result attemptResult, attempt, attempts int, startTime time.Time) (bool, error) {
-	retryable, reason := false, "unknown"
-	if e.retryClassifier != nil {
-		retryable, reason = e.retryClassifier(result.err)
-	}
-	if !e.retryEnabled || attempt == attempts || !retryable {
+	retryable, reason := e.classifyRetry(result.err)
+	// 生成阶段失败（非法 SQL、不支持的值类型等）是确定性的，重试只会浪费尝试次数；
+	// 仅 ExecuteOperations 阶段的错误参与重试分类。
+	if result.stage == BatchStageGenerate {
+		retryable = false
+	}
+	if retryable && e.retryIdempotent && !schemaIdempotent(schema) {
+		retryable, reason = false, ErrorReasonNonIdempotent
+	}
+	backoff := e.retryBackoff(attempt)
+	// 总耗时上限：等待下一轮后将超出 MaxElapsedTime 时直接判为最终失败
+	elapsedExceeded := e.retryMaxElapsed > 0 && time.Since(startTime)+backoff > e.retryMaxElapsed
+	if !e.retryEnabled || attempt == attempts || !retryable || elapsedExceeded {
 		if e.metricsReporter != nil {
 			e.metricsReporter.IncError(schema.Name(), "final:"+reason)
 		}
+		e.Logger().Error("batchflow batch failed", "schema", schema.Name(), "attempt", attempt, "batch_size", len(data), "reason", reason, "error", result.err)
 		e.observeBatchEvent(ctx, newBatchEvent(BatchStageFinal, "fail", attempt, len(data), time.Since(startTime), schema.Name(), result.preview, result.err, reason))
 		return false, nil
 	}
@@ -291,9 +473,10 @@ func (e *ThrottledBatchExecutor) handleRetry(ctx context.Context, schema SchemaI
 	if e.metricsReporter != nil {
 		e.metricsReporter.IncError(schema.Name(), "retry:"+reason)
 	}
+	e.Logger().Warn("batchflow batch retry", "schema", schema.Name(), "attempt", attempt, "batch_size", len(data), "reason", reason, "error", result.err)
 	e.observeBatchEvent(ctx, newBatchEvent(BatchStageRetry, "retry", attempt, len(data), result.duration, schema.Name(), result.preview, result.err, reason))
 
-	timer := time.NewTimer(e.retryBackoff(attempt))
+	timer := time.NewTimer(backoff)
 	select {
 	case <-ctx.Done():
 		if !timer.Stop() {
@@ -463,6 +646,8 @@ func conflictStrategyFromName(name string) ConflictStrategy {
 		return ConflictReplace
 	case "update":
 		return ConflictUpdate
+	case "touch":
+		return ConflictTouch
 	default:
 		return ConflictIgnore
 	}
@@ -483,20 +668,24 @@ func intAttribute(attrs map[string]any, key string) int {
 
 // WithConcurrencyLimit 设置并发上限（limit <= 0 表示不启用限流）
 func (e *ThrottledBatchExecutor) WithConcurrencyLimit(limit int) *ThrottledBatchExecutor {
-	if limit > 0 {
-		e.semaphore = make(chan struct{}, limit)
-	} else {
-		e.semaphore = nil
-	}
+	e.UpdateConcurrencyLimit(limit)
+	return e
+}
+
+// UpdateConcurrencyLimit 运行时调整并发上限（limit <= 0 表示不启用限流）。
+// 已在执行的批次不受影响：扩容立即放行等待者，缩容时新批次等到在途数降到新上限以下；
+// 不限流期间启动的批次同样计入在途数，之后恢复限流不会超出上限。
+func (e *ThrottledBatchExecutor) UpdateConcurrencyLimit(limit int) {
+	e.semaphore.resize(limit)
 	// 配置并发上限时，上报 Gauge（0 表示不限流）
 	if e.metricsReporter != nil {
-		if e.semaphore != nil {
-			e.metricsReporter.SetConcurrency(cap(e.semaphore))
-		} else {
-			e.metricsReporter.SetConcurrency(0)
-		}
+		e.metricsReporter.SetConcurrency(e.ConcurrencyLimit())
 	}
-	return e
+}
+
+// ConcurrencyLimit 返回当前并发上限（0 表示不限流）
+func (e *ThrottledBatchExecutor) ConcurrencyLimit() int {
+	return e.semaphore.capacity()
 }
 
 // Executor 模拟批量执行器（用于测试）
@@ -508,6 +697,13 @@ type MockExecutor struct {
 	// 并发安全的统计聚合：按表名累计批次数、行数、参数数
 	statsMu sync.Mutex
 	stats   map[string]*mockStats
+
+	onBatchSuccess BatchSuccessFunc
+
+	// 摘要模式：不保留行数据，仅累计统计与可选校验和（WithSummaryOnly）
+	summaryOnly bool
+	checksum    bool
+	rowsSum     atomic.Uint64
 }
 
 var _ BatchExecutor = (*MockExecutor)(nil)
@@ -577,23 +773,57 @@ func (e *MockExecutor) ExecuteBatch(ctx context.Context, schema SchemaInterface,
 	if !ok {
 		return errors.New("schema is not a SQLSchema")
 	}
+	if len(data) == 0 {
+		return nil
+	}
 
+	e.mu.RLock()
+	summaryOnly, checksum := e.summaryOnly, e.checksum
+	e.mu.RUnlock()
+	var recorded []map[string]any
+	if !summaryOnly {
+		// data 为池化缓冲，返回后会被复用，这里记录深拷贝
+		recorded = make([]map[string]any, len(data))
+		for i, row := range data {
+			recorded[i] = maps.Clone(row)
+		}
+	}
 	e.mu.Lock()
-	e.ExecutedBatches = append(e.ExecutedBatches, data)
+	if !summaryOnly {
+		e.ExecutedBatches = append(e.ExecutedBatches, recorded)
+	}
+	onSuccess := e.onBatchSuccess
 	e.mu.Unlock()
 
 	// 生成SQL信息（不输出大参数）
-	_, args, err := e.driver.GenerateInsertSQL(ctx, s, data)
+	_, args, err := generateSQLStatement(ctx, e.driver, s, data)
 	if err != nil {
 		return err
 	}
+	if err := checkSQLParamLimit(e.driver, s, len(args)); err != nil {
+		return err
+	}
 
 	// 统计聚合（避免每批次打印噪音日志）
 	e.addStats(schema.Name(), len(data), len(args))
+	if checksum {
+		e.rowsSum.Add(ChecksumRows(data...))
+	}
 
+	if onSuccess != nil {
+		onSuccess(schema, data)
+	}
 	return nil
 }
 
+// WithOnBatchSuccess 设置批次成功回调，语义同 ThrottledBatchExecutor.WithOnBatchSuccess
+func (e *MockExecutor) WithOnBatchSuccess(fn BatchSuccessFunc) *MockExecutor {
+	e.mu.Lock()
+	e.onBatchSuccess = fn
+	e.mu.Unlock()
+	return e
+}
+
 // SnapshotExecutedBatches 返回一次性快照，避免并发读写竞态
 func (e *MockExecutor) SnapshotExecutedBatches() [][]map[string]any {
 	e.mu.RLock()
@@ -0,0 +1,100 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// multiStatementProcessor 每批执行 statements 条语句，每条耗时 delay 并响应 ctx 取消
+type multiStatementProcessor struct {
+	statements int
+	delay      time.Duration
+	executed   atomic.Int32
+}
+
+func (p *multiStatementProcessor) GenerateOperations(context.Context, batchflow.SchemaInterface, []map[string]any) (batchflow.Operations, error) {
+	return batchflow.Operations{"multi"}, nil
+}
+
+func (p *multiStatementProcessor) ExecuteOperations(ctx context.Context, _ batchflow.Operations) error {
+	for i := 0; i < p.statements; i++ {
+		select {
+		case <-time.After(p.delay):
+			p.executed.Add(1)
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+	return nil
+}
+
+// badConnProcessor 总是返回可重试的连接错误
+type badConnProcessor struct{ attempts atomic.Int32 }
+
+func (p *badConnProcessor) GenerateOperations(context.Context, batchflow.SchemaInterface, []map[string]any) (batchflow.Operations, error) {
+	return batchflow.Operations{"op"}, nil
+}
+
+func (p *badConnProcessor) ExecuteOperations(context.Context, batchflow.Operations) error {
+	p.attempts.Add(1)
+	return driver.ErrBadConn
+}
+
+func TestThrottledBatchExecutor_BatchDeadlineBoundsAllStatements(t *testing.T) {
+	processor := &multiStatementProcessor{statements: 10, delay: 20 * time.Millisecond}
+	executor := batchflow.NewThrottledBatchExecutor(processor).WithBatchDeadline(70 * time.Millisecond)
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	start := time.Now()
+	err := executor.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, batchflow.ErrBatchDeadline) {
+		t.Fatalf("expected ErrBatchDeadline, got %v", err)
+	}
+	if n := processor.executed.Load(); n >= 10 {
+		t.Fatalf("expected remaining statements to be cancelled, executed %d", n)
+	}
+	if elapsed > 150*time.Millisecond {
+		t.Fatalf("deadline should stop the batch early, took %v", elapsed)
+	}
+}
+
+func TestThrottledBatchExecutor_BatchDeadlineBoundsRetries(t *testing.T) {
+	processor := &badConnProcessor{}
+	executor := batchflow.NewThrottledBatchExecutor(processor).
+		WithRetryConfig(batchflow.RetryConfig{Enabled: true, MaxAttempts: 1000, BackoffBase: 20 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}).
+		WithBatchDeadline(100 * time.Millisecond)
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	start := time.Now()
+	err := executor.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}})
+	if !errors.Is(err, batchflow.ErrBatchDeadline) {
+		t.Fatalf("expected ErrBatchDeadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Fatalf("deadline should stop retries, took %v", elapsed)
+	}
+	if n := processor.attempts.Load(); n < 2 || n > 20 {
+		t.Fatalf("expected a handful of attempts within the deadline, got %d", n)
+	}
+}
+
+func TestThrottledBatchExecutor_BatchDeadlineNotReportedForCallerCancel(t *testing.T) {
+	processor := &multiStatementProcessor{statements: 10, delay: 20 * time.Millisecond}
+	executor := batchflow.NewThrottledBatchExecutor(processor).WithBatchDeadline(time.Second)
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	err := executor.ExecuteBatch(ctx, schema, []map[string]any{{"id": 1}})
+	if err == nil || errors.Is(err, batchflow.ErrBatchDeadline) {
+		t.Fatalf("caller cancellation must not be reported as batch deadline, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package batchflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type slowProcessor struct{ delay time.Duration }
+
+func (p slowProcessor) GenerateOperations(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) (batchflow.Operations, error) {
+	return batchflow.Operations{"ok"}, nil
+}
+
+func (p slowProcessor) ExecuteOperations(ctx context.Context, ops batchflow.Operations) error {
+	time.Sleep(p.delay)
+	return nil
+}
+
+type concurrencyWaitMetrics struct {
+	batchflow.NoopMetricsReporter
+
+	mu    sync.Mutex
+	waits []time.Duration
+}
+
+func (m *concurrencyWaitMetrics) ObserveConcurrencyWait(d time.Duration) {
+	m.mu.Lock()
+	m.waits = append(m.waits, d)
+	m.mu.Unlock()
+}
+
+func TestThrottledExecutor_ObserveConcurrencyWait(t *testing.T) {
+	reporter := &concurrencyWaitMetrics{}
+	exec := batchflow.NewThrottledBatchExecutor(slowProcessor{delay: 50 * time.Millisecond}).
+		WithConcurrencyLimit(1).
+		WithMetricsReporter(reporter)
+
+	schema := batchflow.NewSchema("events", "id")
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": i}}); err != nil {
+				t.Errorf("ExecuteBatch failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if len(reporter.waits) != 2 {
+		t.Fatalf("expected 2 wait observations, got %d", len(reporter.waits))
+	}
+	longest := max(reporter.waits[0], reporter.waits[1])
+	if longest < 20*time.Millisecond {
+		t.Fatalf("expected the second batch to wait for the permit, waits=%v", reporter.waits)
+	}
+}
+
+func TestThrottledExecutor_NoConcurrencyWaitWithoutLimit(t *testing.T) {
+	reporter := &concurrencyWaitMetrics{}
+	exec := batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter)
+	if err := exec.ExecuteBatch(context.Background(), batchflow.NewSchema("events", "id"), []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if len(reporter.waits) != 0 {
+		t.Fatalf("expected no wait observations without a limit, got %v", reporter.waits)
+	}
+}
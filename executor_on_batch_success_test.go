@@ -0,0 +1,106 @@
+package batchflow_test
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rushairer/batchflow/v2"
+)
+
+type successSink struct {
+	mu      sync.Mutex
+	schemas []string
+	batches [][]map[string]any
+}
+
+func (s *successSink) record(schema batchflow.SchemaInterface, rows []map[string]any) {
+	copied := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		copied[i] = maps.Clone(row)
+	}
+	s.mu.Lock()
+	s.schemas = append(s.schemas, schema.Name())
+	s.batches = append(s.batches, copied)
+	s.mu.Unlock()
+}
+
+func (s *successSink) snapshot() ([]string, [][]map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.schemas...), append([][]map[string]any(nil), s.batches...)
+}
+
+func TestBatchFlow_OnBatchSuccessReceivesFlushedRows(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    16,
+		FlushSize:     3,
+		FlushInterval: time.Hour,
+	})
+	defer b.Close()
+	sink := &successSink{}
+	mock.WithOnBatchSuccess(sink.record)
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	for i, name := range []string{"a", "b", "c"} {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", int64(i)).SetString("name", name)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, batches := sink.snapshot(); len(batches) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected one success callback")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	schemas, batches := sink.snapshot()
+	if schemas[0] != "users" {
+		t.Fatalf("unexpected schema %q", schemas[0])
+	}
+	executed := mock.SnapshotExecutedBatches()
+	if !reflect.DeepEqual(batches[0], executed[0]) {
+		t.Fatalf("callback rows differ from executed batch:\ngot  %v\nwant %v", batches[0], executed[0])
+	}
+}
+
+func TestThrottledExecutor_OnBatchSuccessOnlyAfterFinalSuccess(t *testing.T) {
+	p := &sqlStateProcessor{err: &pq.Error{Code: "40P01", Message: "deadlock detected"}, failures: 2}
+	sink := &successSink{}
+	exec := retryingExecutor(p).WithOnBatchSuccess(sink.record)
+
+	schema := batchflow.NewSchema("events", "id")
+	rows := []map[string]any{{"id": 1}, {"id": 2}}
+	if err := exec.ExecuteBatch(context.Background(), schema, rows); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if n := p.calls.Load(); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+	_, batches := sink.snapshot()
+	if len(batches) != 1 || !reflect.DeepEqual(batches[0], rows) {
+		t.Fatalf("expected a single callback with the written rows, got %v", batches)
+	}
+}
+
+func TestThrottledExecutor_OnBatchSuccessNotCalledOnFailure(t *testing.T) {
+	sink := &successSink{}
+	exec := batchflow.NewThrottledBatchExecutor(nonRetryProcessor{}).WithOnBatchSuccess(sink.record)
+
+	if err := exec.ExecuteBatch(context.Background(), batchflow.NewSchema("events", "id"), []map[string]any{{"id": 1}}); err == nil {
+		t.Fatal("expected failure")
+	}
+	if _, batches := sink.snapshot(); len(batches) != 0 {
+		t.Fatalf("callback must not fire on failure, got %v", batches)
+	}
+}
@@ -0,0 +1,133 @@
+package batchflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// FileExecutor 将每批行数据以 JSON Lines（每行一个 JSON 对象）写入 io.Writer，用于调试与离线导出。
+// 对象的键按 schema 列顺序输出（行中不属于 schema 的键按字母序追加在后），[]byte 按 encoding/json 规则编码为 base64。
+// 并发的 ExecuteBatch 串行写入，同一批次的行保持连续；写入器由调用方负责 Flush/Close。
+// 删除段（MarkDelete）需通过 WithOperationField 写出操作类型，否则返回包装 ErrDeleteUnsupported 的错误。
+type FileExecutor struct {
+	mu             sync.Mutex
+	w              io.Writer
+	schemaField    string // 非空时在每行首个字段写入 schema 名称
+	operationField string // 非空时在每行写入操作类型（insert/delete）
+}
+
+var _ BatchExecutor = (*FileExecutor)(nil)
+
+// NewFileExecutor 创建写入 w 的 JSON Lines 执行器
+func NewFileExecutor(w io.Writer) *FileExecutor {
+	return &FileExecutor{w: w}
+}
+
+// WithSchemaField 在每行首个字段写入 schema 名称（如 "_table"），便于区分同一文件中多个 schema 的行；
+// 空字符串关闭（默认）。
+func (e *FileExecutor) WithSchemaField(field string) *FileExecutor {
+	e.schemaField = field
+	return e
+}
+
+// WithOperationField 在每行写入操作类型字段（如 "_op"），取值为 OperationInsert 或 OperationDelete（删除行只含冲突键列）；
+// 位于 schema 字段之后、数据列之前。空字符串关闭（默认），此时遇到删除段返回错误而不是把删除写成普通行。
+func (e *FileExecutor) WithOperationField(field string) *FileExecutor {
+	e.operationField = field
+	return e
+}
+
+// ExecuteBatch 逐行编码并写入；每行写入前检查 ctx，取消时停止并返回 ctx 错误（已写入的行保留）
+func (e *FileExecutor) ExecuteBatch(ctx context.Context, schema SchemaInterface, data []map[string]any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	operation := OperationInsert
+	if isDeleteSchema(schema) {
+		if e.operationField == "" {
+			return &BatchError{Stage: BatchStageGenerate, Backend: BackendCustom, Schema: schema.Name(), BatchSize: len(data), Cause: fmt.Errorf("%w: FileExecutor requires WithOperationField to encode deletes", ErrDeleteUnsupported)}
+		}
+		operation = OperationDelete
+	}
+	columns := schema.Columns()
+	var line bytes.Buffer
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, row := range data {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line.Reset()
+		if err := e.encodeRow(&line, schema.Name(), operation, columns, row); err != nil {
+			return &BatchError{Stage: BatchStageGenerate, Backend: BackendCustom, Schema: schema.Name(), BatchSize: len(data), Cause: err}
+		}
+		if _, err := e.w.Write(line.Bytes()); err != nil {
+			return &BatchError{Stage: BatchStageExecute, Backend: BackendCustom, Schema: schema.Name(), BatchSize: len(data), Cause: fmt.Errorf("write jsonl: %w", err)}
+		}
+	}
+	return nil
+}
+
+// encodeRow 按列顺序编码单行并追加换行符
+func (e *FileExecutor) encodeRow(buf *bytes.Buffer, schemaName, operation string, columns []string, row map[string]any) error {
+	buf.WriteByte('{')
+	first := true
+	field := func(key string, value any) error {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", key, err)
+		}
+		name, _ := json.Marshal(key)
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(encoded)
+		return nil
+	}
+	if e.schemaField != "" {
+		if err := field(e.schemaField, schemaName); err != nil {
+			return err
+		}
+	}
+	if e.operationField != "" {
+		if err := field(e.operationField, operation); err != nil {
+			return err
+		}
+	}
+	seen := 0
+	for _, col := range columns {
+		value, ok := row[col]
+		if !ok {
+			continue
+		}
+		seen++
+		if err := field(col, value); err != nil {
+			return err
+		}
+	}
+	if seen < len(row) {
+		var extra []string
+		for key := range row {
+			if !slices.Contains(columns, key) {
+				extra = append(extra, key)
+			}
+		}
+		slices.Sort(extra)
+		for _, key := range extra {
+			if err := field(key, row[key]); err != nil {
+				return err
+			}
+		}
+	}
+	buf.WriteString("}\n")
+	return nil
+}
@@ -0,0 +1,129 @@
+package batchflow_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestFileExecutor_WritesJSONLinesInOrder(t *testing.T) {
+	var out bytes.Buffer
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 10, FlushInterval: time.Hour},
+		Executor: batchflow.NewFileExecutor(&out).WithSchemaField("_table"),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "name", "id", "active")
+	requests := []*batchflow.Request{
+		batchflow.NewRequest(users).SetInt("id", 1).SetString("name", "alice").SetBool("active", true),
+		batchflow.NewRequest(users).SetInt("id", 2).SetString("name", "bob \"b\"").SetNull("active"),
+		batchflow.NewRequest(users).SetInt("id", 3).SetString("name", "carol").SetBool("active", false),
+	}
+	for _, r := range requests {
+		if err := b.Submit(ctx, r); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	want := strings.Join([]string{
+		`{"_table":"users","name":"alice","id":1,"active":true}`,
+		`{"_table":"users","name":"bob \"b\"","id":2,"active":null}`,
+		`{"_table":"users","name":"carol","id":3,"active":false}`,
+	}, "\n") + "\n"
+	if out.String() != want {
+		t.Fatalf("unexpected jsonl output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestFileExecutor_StopsOnCanceledContext(t *testing.T) {
+	var out bytes.Buffer
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := batchflow.NewFileExecutor(&out).ExecuteBatch(ctx, schema, []map[string]any{{"id": 1}, {"id": 2}})
+	if !errors.Is(err, context.Canceled) || out.Len() != 0 {
+		t.Fatalf("expected cancellation before writing, got err=%v output=%q", err, out.String())
+	}
+}
+
+func TestFileExecutor_MarshalErrorIsGenerateStage(t *testing.T) {
+	var out bytes.Buffer
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id", "payload")
+	err := batchflow.NewFileExecutor(&out).ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1, "payload": make(chan int)}})
+	var batchErr *batchflow.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Stage != batchflow.BatchStageGenerate {
+		t.Fatalf("expected generate-stage BatchError, got %v", err)
+	}
+}
+
+func TestFileExecutor_OperationFieldEncodesDeletes(t *testing.T) {
+	var out bytes.Buffer
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 10, FlushInterval: time.Hour},
+		Executor: batchflow.NewFileExecutor(&out).WithSchemaField("_table").WithOperationField("_op"),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	requests := []*batchflow.Request{
+		batchflow.NewRequest(users).SetInt("id", 1).SetString("name", "alice"),
+		batchflow.NewRequest(users).SetInt("id", 2).MarkDelete(),
+	}
+	for _, r := range requests {
+		if err := b.Submit(ctx, r); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	want := `{"_table":"users","_op":"insert","id":1,"name":"alice"}` + "\n" +
+		`{"_table":"users","_op":"delete","id":2}` + "\n"
+	if out.String() != want {
+		t.Fatalf("unexpected jsonl output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestFileExecutor_RejectsDeletesWithoutOperationField(t *testing.T) {
+	var out bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 4, FlushSize: 1, FlushInterval: time.Hour},
+		Executor: batchflow.NewFileExecutor(&out),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	errs := b.ErrorChan(1)
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	if err := b.Submit(ctx, batchflow.NewRequest(users).SetInt("id", 2).MarkDelete()); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, batchflow.ErrDeleteUnsupported) {
+			t.Fatalf("expected ErrDeleteUnsupported, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a flush error for an unencodable delete")
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected nothing written, got %q", out.String())
+	}
+}
@@ -0,0 +1,83 @@
+package batchflow
+
+import (
+	"fmt"
+	"math"
+)
+
+// NonFiniteFloatPolicy 决定 NaN、+Inf、-Inf 等非有限浮点值（float32/float64 列值）的处理方式。
+// 多数数据库的数值列拒绝这些值，默认透传时错误要到执行阶段才出现。
+type NonFiniteFloatPolicy uint8
+
+const (
+	// NonFinitePassThrough 原样交给执行器（默认，兼容旧行为）
+	NonFinitePassThrough NonFiniteFloatPolicy = iota
+	// NonFiniteReject Submit 时拒绝，返回 *ColumnError（ErrNonFiniteFloat）；flush 组装时再次检查
+	NonFiniteReject
+	// NonFiniteCoerceToNull flush 组装时将非有限值替换为 NULL
+	NonFiniteCoerceToNull
+)
+
+// String 返回策略名称
+func (p NonFiniteFloatPolicy) String() string {
+	switch p {
+	case NonFinitePassThrough:
+		return "pass_through"
+	case NonFiniteReject:
+		return "reject"
+	case NonFiniteCoerceToNull:
+		return "coerce_to_null"
+	default:
+		return fmt.Sprintf("NonFiniteFloatPolicy(%d)", uint8(p))
+	}
+}
+
+// nonFiniteFloat 判断值是否为非有限浮点数
+func nonFiniteFloat(value any) (float64, bool) {
+	var f float64
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	default:
+		return 0, false
+	}
+	return f, math.IsNaN(f) || math.IsInf(f, 0)
+}
+
+func nonFiniteColumnError(schemaName, col string, f float64) error {
+	return &ColumnError{SchemaName: schemaName, Column: col, Reason: fmt.Sprintf("non-finite float %v", f), Err: ErrNonFiniteFloat}
+}
+
+// checkNonFiniteFloats NonFiniteReject 策略下 Submit 前的检查，按 schema 列顺序报告首个非有限值
+func (r *Request) checkNonFiniteFloats() error {
+	for _, col := range r.schema.Columns() {
+		if f, bad := nonFiniteFloat(r.columns[col]); bad {
+			return nonFiniteColumnError(r.schema.Name(), col, f)
+		}
+	}
+	return nil
+}
+
+// applyNonFinitePolicy 在 flush 组装后按策略处理行中的非有限浮点值
+func applyNonFinitePolicy(schema SchemaInterface, rows []map[string]any, policy NonFiniteFloatPolicy) error {
+	if policy == NonFinitePassThrough {
+		return nil
+	}
+	columns := schema.Columns()
+	for _, row := range rows {
+		for _, col := range columns {
+			f, bad := nonFiniteFloat(row[col])
+			if !bad {
+				continue
+			}
+			if policy == NonFiniteCoerceToNull {
+				row[col] = nil
+				continue
+			}
+			return nonFiniteColumnError(schema.Name(), col, f)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package batchflow
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// jitterFlushInterval 在 [interval*(1-jitter), interval*(1+jitter)] 内均匀取值，
+// 让共享同一配置的多个副本错开 flush 周期；结果至少为 1ms。
+func jitterFlushInterval(interval time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 || interval <= 0 {
+		return interval
+	}
+	factor := 1 + jitter*(2*rand.Float64()-1)
+	return max(time.Duration(float64(interval)*factor), time.Millisecond)
+}
+
+// EffectiveFlushInterval 返回主通道实际使用的 FlushInterval（已应用 FlushIntervalJitter）
+func (b *BatchFlow) EffectiveFlushInterval() time.Duration {
+	return time.Duration(b.effectiveFlushInterval.Load())
+}
@@ -0,0 +1,68 @@
+package batchflow
+
+import (
+	"errors"
+	"sync"
+)
+
+// GroupResult 一次分组执行（一次 ExecuteBatch 或组装校验失败）的结果
+type GroupResult struct {
+	SchemaName string
+	RowCount   int   // 该组提交的请求数
+	Err        error // 成功为 nil
+}
+
+// FlushResult Close 触发的最终排空中各分组的执行结果，按完成顺序排列
+type FlushResult struct {
+	Groups []GroupResult
+}
+
+// Err 合并所有失败分组的错误（errors.Join），全部成功时为 nil
+func (r FlushResult) Err() error {
+	var errs []error
+	for _, g := range r.Groups {
+		if g.Err != nil {
+			errs = append(errs, g.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// drainRecorder 记录 Close 开始后完成的分组结果
+type drainRecorder struct {
+	mu     sync.Mutex
+	active bool
+	groups []GroupResult
+}
+
+func (d *drainRecorder) start() {
+	d.mu.Lock()
+	d.active = true
+	d.mu.Unlock()
+}
+
+func (d *drainRecorder) record(schema SchemaInterface, rowCount int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.active {
+		return
+	}
+	d.groups = append(d.groups, GroupResult{SchemaName: schema.Name(), RowCount: rowCount, Err: err})
+}
+
+func (d *drainRecorder) result() FlushResult {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return FlushResult{Groups: append([]GroupResult(nil), d.groups...)}
+}
+
+// CloseWithResult 与 Close 相同，额外返回 Close 开始后完成的每个分组的结果（schema、行数、错误），
+// 便于在排空涉及多个 schema 时定位失败的分组。某个分组失败会结束其所在的那次 flush，
+// 同一 flush 中尚未执行的分组不会出现在结果中。
+// 分组错误同时照常投递到 ErrorChan/OnError；返回的 error 与 Close 一致（后台运行错误）。
+// 与 Close 不同，它还会等待关闭前已满批触发、仍在执行的异步 flush，结果因此覆盖全部缓冲数据。
+func (b *BatchFlow) CloseWithResult() (FlushResult, error) {
+	err := b.Close()
+	b.awaitFlushes()
+	return b.drain.result(), err
+}
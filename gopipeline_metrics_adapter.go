@@ -11,7 +11,9 @@ import (
 // 注意：为避免与批处理层的精确 success/fail 耗时重复统计，
 // 这里对 Flush、Error 选择不进行耗时上报，仅处理 ErrorDropped。
 type pipelineMetricsAdapter struct {
-	reporter MetricsReporter
+	reporter  MetricsReporter
+	logger    Logger
+	delivered func() // 每个 flush 错误写入错误通道（或丢弃）后调用
 }
 
 func (a pipelineMetricsAdapter) pmr() (PipelineMetricsReporter, bool) {
@@ -27,25 +29,30 @@ func (a pipelineMetricsAdapter) pmr() (PipelineMetricsReporter, bool) {
 // 混入同一个 ObserveBatchSize 指标中；详细 flush 维度由 BatchFlow 自身扩展指标负责。
 func (a pipelineMetricsAdapter) Flush(_ int, _ time.Duration) {}
 
-// Error 在错误成功写入错误通道时调用。
-// 说明：无持续时间，且我们已有执行器/管道级失败耗时上报，因此这里不额外上报。
+// Error 在 flush 错误尝试写入错误通道之后调用（通道已满被丢弃时也会调用）。
+// 说明：无持续时间，且我们已有执行器/管道级失败耗时上报，因此这里不额外上报，仅通知错误已投递。
 func (a pipelineMetricsAdapter) Error(_ error) {
-	// no-op
+	if a.delivered != nil {
+		a.delivered()
+	}
 }
 
 // ErrorDropped 在错误通道满导致错误被丢弃时调用。
 // 映射为扩展接口的丢弃计数。
 func (a pipelineMetricsAdapter) ErrorDropped() {
+	if a.logger != nil {
+		a.logger.Warn("batchflow error dropped", "reason", "error_chan_full")
+	}
 	if pmr, ok := a.pmr(); ok && pmr != nil {
 		pmr.IncDropped("error_chan_full")
 	}
 }
 
 // attachPipelineMetrics 将适配器与 go-pipeline 挂接
-func attachPipelineMetrics[T any](p *gopipeline.StandardPipeline[T], r MetricsReporter) {
+func attachPipelineMetrics[T any](p *gopipeline.StandardPipeline[T], r MetricsReporter, l Logger, delivered func()) {
 	if p == nil || r == nil {
 		return
 	}
-	adapter := pipelineMetricsAdapter{reporter: r}
+	adapter := pipelineMetricsAdapter{reporter: r, logger: l, delivered: delivered}
 	p.WithMetrics(adapter)
 }
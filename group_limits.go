@@ -0,0 +1,67 @@
+package batchflow
+
+import (
+	"time"
+)
+
+// splitGroup 按 MaxGroupRows / MaxGroupBytes 将同一 schema 的请求切分为多段，依次执行。
+// 单个请求超过字节上限时独占一段，不会被丢弃。两个上限均 <= 0 时原样返回整组。
+func splitGroup(requests []*Request, maxRows, maxBytes int) [][]*Request {
+	if maxRows <= 0 && maxBytes <= 0 {
+		return [][]*Request{requests}
+	}
+	var chunks [][]*Request
+	start, bytes := 0, 0
+	for i, request := range requests {
+		size := 0
+		if maxBytes > 0 {
+			size = approxRequestBytes(request)
+		}
+		full := (maxRows > 0 && i-start >= maxRows) || (maxBytes > 0 && i > start && bytes+size > maxBytes)
+		if full {
+			chunks = append(chunks, requests[start:i])
+			start, bytes = i, 0
+		}
+		bytes += size
+	}
+	return append(chunks, requests[start:])
+}
+
+// approxRequestBytes 粗略估算请求的负载字节数：变长值按长度计，定长值按固定大小计，仅用于切分上限
+func approxRequestBytes(request *Request) int {
+	return approxRowBytes(request.columns)
+}
+
+// approxRowsBytes 估算组装后整批行数据的字节数（BatchBytesMetricsReporter）
+func approxRowsBytes(rows []map[string]any) int {
+	n := 0
+	for _, row := range rows {
+		n += approxRowBytes(row)
+	}
+	return n
+}
+
+func approxRowBytes(row map[string]any) int {
+	n := 0
+	for col, value := range row {
+		n += len(col)
+		switch v := value.(type) {
+		case nil:
+		case string:
+			n += len(v)
+		case []byte:
+			n += len(v)
+		case bool, int8, uint8:
+			n++
+		case int16, uint16:
+			n += 2
+		case int32, uint32, float32:
+			n += 4
+		case time.Time:
+			n += 24
+		default:
+			n += 8
+		}
+	}
+	return n
+}
@@ -0,0 +1,19 @@
+package batchflow
+
+// IdempotentSchema 可选接口：schema 声明重放同一批次是否安全（不会产生重复写入）。
+// RetryConfig.IdempotentOnly 开启时只对返回 true 的 schema 重试；未实现该接口的 schema 视为非幂等。
+type IdempotentSchema interface {
+	Idempotent() bool
+}
+
+// Idempotent 内置冲突策略（Ignore/Replace/Update/Touch）都依赖目标表的唯一键处理冲突，
+// 超时后实际已提交的批次再次执行不会产生重复行；AppendOnly 表没有唯一键，写入等同普通 INSERT，返回 false。
+func (s *SQLSchema) Idempotent() bool {
+	return !s.operationConfig.AppendOnly
+}
+
+// schemaIdempotent 判断 schema 是否声明为幂等
+func schemaIdempotent(schema SchemaInterface) bool {
+	is, ok := schema.(IdempotentSchema)
+	return ok && is.Idempotent()
+}
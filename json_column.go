@@ -0,0 +1,51 @@
+package batchflow
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"sync"
+)
+
+// jsonColumnValue SetMap/SetStruct 写入的列值：序列化延迟到 Validate 或批次组装时执行，结果缓存。
+// 组装后以 JSON 字符串交给驱动（MySQL JSON / PostgreSQL json(b) / SQLite TEXT 均可直接接收）。
+type jsonColumnValue struct {
+	value any
+
+	once    sync.Once
+	encoded string
+	err     error
+}
+
+func newJSONColumnValue(v any) *jsonColumnValue {
+	return &jsonColumnValue{value: v}
+}
+
+func (v *jsonColumnValue) encode() (string, error) {
+	v.once.Do(func() {
+		b, err := json.Marshal(v.value)
+		v.encoded, v.err = string(b), err
+	})
+	return v.encoded, v.err
+}
+
+// Value 实现 driver.Valuer，便于绕过批次组装直接交给 database/sql 的场景
+func (v *jsonColumnValue) Value() (driver.Value, error) {
+	return v.encode()
+}
+
+// MarshalJSON 保证嵌入其它 JSON 文档（如 RedisJSON 驱动）时输出原始结构
+func (v *jsonColumnValue) MarshalJSON() ([]byte, error) {
+	s, err := v.encode()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// resolveColumnValue 将延迟值转换为驱动可直接使用的值
+func resolveColumnValue(value any) (any, error) {
+	if jv, ok := value.(*jsonColumnValue); ok {
+		return jv.encode()
+	}
+	return value, nil
+}
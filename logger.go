@@ -0,0 +1,64 @@
+package batchflow
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger 最小结构化日志接口，用于输出重试、丢弃错误等诊断信息。
+// kv 为交替的键值对（与 slog 约定一致），便于适配 zap/slog/zerolog 等日志库。
+type Logger interface {
+	Debug(msg string, kv ...any)
+	Info(msg string, kv ...any)
+	Warn(msg string, kv ...any)
+	Error(msg string, kv ...any)
+}
+
+var _ Logger = (*NoopLogger)(nil)
+
+// NoopLogger 默认的无操作日志实现
+type NoopLogger struct{}
+
+func NewNoopLogger() *NoopLogger { return &NoopLogger{} }
+
+func (*NoopLogger) Debug(string, ...any) {}
+func (*NoopLogger) Info(string, ...any)  {}
+func (*NoopLogger) Warn(string, ...any)  {}
+func (*NoopLogger) Error(string, ...any) {}
+
+// slogLogger 将 Logger 适配到 *slog.Logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger 使用 *slog.Logger 实现 Logger；logger 为 nil 时使用 slog.Default()
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger: logger}
+}
+
+func (l *slogLogger) Debug(msg string, kv ...any) {
+	l.logger.Log(context.Background(), slog.LevelDebug, msg, kv...)
+}
+
+func (l *slogLogger) Info(msg string, kv ...any) {
+	l.logger.Log(context.Background(), slog.LevelInfo, msg, kv...)
+}
+
+func (l *slogLogger) Warn(msg string, kv ...any) {
+	l.logger.Log(context.Background(), slog.LevelWarn, msg, kv...)
+}
+
+func (l *slogLogger) Error(msg string, kv ...any) {
+	l.logger.Log(context.Background(), slog.LevelError, msg, kv...)
+}
+
+// loggerOrNoop 保证调用方始终拿到可用 Logger
+func loggerOrNoop(logger Logger) Logger {
+	if logger == nil {
+		return NewNoopLogger()
+	}
+	return logger
+}
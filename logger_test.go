@@ -0,0 +1,106 @@
+package batchflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type logEntry struct {
+	level string
+	msg   string
+	kv    map[string]any
+}
+
+type capturingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *capturingLogger) record(level, msg string, kv []any) {
+	fields := make(map[string]any, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		if key, ok := kv[i].(string); ok {
+			fields[key] = kv[i+1]
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, kv: fields})
+}
+
+func (l *capturingLogger) Debug(msg string, kv ...any) { l.record("debug", msg, kv) }
+func (l *capturingLogger) Info(msg string, kv ...any)  { l.record("info", msg, kv) }
+func (l *capturingLogger) Warn(msg string, kv ...any)  { l.record("warn", msg, kv) }
+func (l *capturingLogger) Error(msg string, kv ...any) { l.record("error", msg, kv) }
+
+func (l *capturingLogger) byLevel(level string) []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []logEntry
+	for _, e := range l.entries {
+		if e.level == level {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestThrottledExecutor_LogsRetryAndFinalFailure(t *testing.T) {
+	logger := &capturingLogger{}
+	exec := batchflow.NewThrottledBatchExecutor(alwaysRetryProcessor{}).
+		WithLogger(logger).
+		WithRetryConfig(batchflow.RetryConfig{
+			Enabled:     true,
+			MaxAttempts: 3,
+			BackoffBase: time.Millisecond,
+			MaxBackoff:  2 * time.Millisecond,
+		})
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	if err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}}); err == nil {
+		t.Fatalf("expected final failure")
+	}
+
+	retries := logger.byLevel("warn")
+	if len(retries) != 2 {
+		t.Fatalf("expected 2 retry log entries, got %d", len(retries))
+	}
+	for i, entry := range retries {
+		if entry.kv["schema"] != "users" || entry.kv["attempt"] != i+1 || entry.kv["reason"] != batchflow.ErrorReasonTimeout {
+			t.Fatalf("unexpected retry log fields: %+v", entry.kv)
+		}
+	}
+	finals := logger.byLevel("error")
+	if len(finals) != 1 {
+		t.Fatalf("expected 1 final failure log entry, got %d", len(finals))
+	}
+	if finals[0].kv["attempt"] != 3 || finals[0].kv["error"] == nil {
+		t.Fatalf("unexpected final log fields: %+v", finals[0].kv)
+	}
+}
+
+func TestBatchFlow_LoggerFromConfigAndDefaultNoop(t *testing.T) {
+	ctx := context.Background()
+	flow, _ := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{BufferSize: 4, FlushSize: 4, FlushInterval: time.Hour})
+	if _, ok := flow.Logger().(*batchflow.NoopLogger); !ok {
+		t.Fatalf("expected default noop logger, got %T", flow.Logger())
+	}
+	_ = flow.Close()
+
+	logger := &capturingLogger{}
+	flow, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{Logger: logger},
+		Executor: batchflow.NewMockExecutor(),
+	})
+	if err != nil {
+		t.Fatalf("NewBatchFlowWithConfig failed: %v", err)
+	}
+	defer flow.Close()
+	if flow.Logger() != batchflow.Logger(logger) {
+		t.Fatalf("expected configured logger to be used")
+	}
+}
@@ -0,0 +1,139 @@
+package batchflow
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// InMemoryExecutor 将写入的行保存在内存“表”中，用于在测试里断言最终状态（而非批次边界）。
+// 对 SQLSchema 按冲突键（ConflictColumns，缺省为首列）模拟冲突策略：
+//   - ConflictIgnore：保留已有行
+//   - ConflictUpdate：仅覆盖 UpdateColumns（缺省为全部非冲突列）
+//   - ConflictReplace：整行替换
+//   - ConflictTouch：同 ConflictIgnore，保留已有行
+//
+// 删除段（SQLSchema.IsDelete）按冲突键移除已有行。非 SQLSchema 没有键，直接追加。
+type InMemoryExecutor struct {
+	mu     sync.RWMutex
+	tables map[string]*memoryTable
+}
+
+type memoryTable struct {
+	rows  []map[string]any
+	index map[string]int // 冲突键 -> rows 下标
+}
+
+var _ BatchExecutor = (*InMemoryExecutor)(nil)
+
+// NewInMemoryExecutor 创建空的内存执行器
+func NewInMemoryExecutor() *InMemoryExecutor {
+	return &InMemoryExecutor{tables: make(map[string]*memoryTable)}
+}
+
+// ExecuteBatch 按 schema 的冲突策略将行合并到内存表（行会被拷贝，不持有池化缓冲）
+func (e *InMemoryExecutor) ExecuteBatch(ctx context.Context, schema SchemaInterface, data []map[string]any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	sqlSchema, _ := schema.(*SQLSchema)
+	var (
+		keyColumns []string
+		strategy   ConflictStrategy
+		updateCols []string
+	)
+	if sqlSchema != nil {
+		keyColumns = sqlConflictColumns(sqlSchema)
+		strategy = sqlSchema.operationConfig.ConflictStrategy
+		if strategy == ConflictUpdate {
+			updateCols = sqlUpdateColumns(sqlSchema, false)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	table, ok := e.tables[schema.Name()]
+	if !ok {
+		table = &memoryTable{index: make(map[string]int)}
+		e.tables[schema.Name()] = table
+	}
+	if sqlSchema != nil && sqlSchema.IsDelete() {
+		table.delete(data, keyColumns)
+		return nil
+	}
+	for _, row := range data {
+		if len(keyColumns) == 0 {
+			table.rows = append(table.rows, maps.Clone(row))
+			continue
+		}
+		key := recordKey(row, keyColumns)
+		idx, exists := table.index[key]
+		if !exists {
+			table.index[key] = len(table.rows)
+			table.rows = append(table.rows, maps.Clone(row))
+			continue
+		}
+		switch strategy {
+		case ConflictUpdate:
+			for _, col := range updateCols {
+				if v, ok := row[col]; ok {
+					table.rows[idx][col] = v
+				}
+			}
+		case ConflictReplace:
+			table.rows[idx] = maps.Clone(row)
+		default: // ConflictIgnore / ConflictTouch
+		}
+	}
+	return nil
+}
+
+// delete 移除键命中的行并重建索引，其余行保持写入顺序
+func (t *memoryTable) delete(data []map[string]any, keyColumns []string) {
+	removed := make(map[int]struct{}, len(data))
+	for _, row := range data {
+		if idx, ok := t.index[recordKey(row, keyColumns)]; ok {
+			removed[idx] = struct{}{}
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+	kept := t.rows[:0]
+	for i, row := range t.rows {
+		if _, ok := removed[i]; !ok {
+			kept = append(kept, row)
+		}
+	}
+	clear(t.rows[len(kept):])
+	t.rows = kept
+	clear(t.index)
+	for i, row := range t.rows {
+		t.index[recordKey(row, keyColumns)] = i
+	}
+}
+
+// Rows 返回表中所有行的拷贝，按首次写入顺序排列
+func (e *InMemoryExecutor) Rows(schema string) []map[string]any {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	table, ok := e.tables[schema]
+	if !ok {
+		return nil
+	}
+	out := make([]map[string]any, len(table.rows))
+	for i, row := range table.rows {
+		out[i] = maps.Clone(row)
+	}
+	return out
+}
+
+// Count 返回表中的行数
+func (e *InMemoryExecutor) Count(schema string) int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if table, ok := e.tables[schema]; ok {
+		return len(table.rows)
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+package batchflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestInMemoryExecutor_ConflictIgnoreKeepsFirst(t *testing.T) {
+	ctx := context.Background()
+	mem := batchflow.NewInMemoryExecutor()
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+
+	if err := mem.ExecuteBatch(ctx, schema, []map[string]any{
+		{"id": int64(1), "name": "alice"},
+		{"id": int64(2), "name": "bob"},
+		{"id": int64(1), "name": "alice-dup"},
+	}); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if err := mem.ExecuteBatch(ctx, schema, []map[string]any{{"id": int64(2), "name": "bob-dup"}}); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	if n := mem.Count("users"); n != 2 {
+		t.Fatalf("expected 2 rows, got %d", n)
+	}
+	rows := mem.Rows("users")
+	if rows[0]["name"] != "alice" || rows[1]["name"] != "bob" {
+		t.Fatalf("expected first writes to win, got %v", rows)
+	}
+}
+
+func TestInMemoryExecutor_ConflictUpdateOverwritesUpdateColumns(t *testing.T) {
+	ctx := context.Background()
+	mem := batchflow.NewInMemoryExecutor()
+	cfg := batchflow.ConflictUpdateOperationConfig.
+		WithConflictColumns("tenant", "id").
+		WithUpdateColumns("name")
+	schema := batchflow.NewSQLSchema("users", cfg, "tenant", "id", "name", "created")
+
+	_ = mem.ExecuteBatch(ctx, schema, []map[string]any{
+		{"tenant": "a", "id": int64(1), "name": "alice", "created": "t1"},
+		{"tenant": "b", "id": int64(1), "name": "other", "created": "t1"},
+	})
+	_ = mem.ExecuteBatch(ctx, schema, []map[string]any{
+		{"tenant": "a", "id": int64(1), "name": "alice-v2", "created": "t2"},
+	})
+
+	rows := mem.Rows("users")
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows keyed by (tenant, id), got %d", len(rows))
+	}
+	if rows[0]["name"] != "alice-v2" {
+		t.Fatalf("expected name overwritten, got %v", rows[0])
+	}
+	if rows[0]["created"] != "t1" {
+		t.Fatalf("expected non-update column preserved, got %v", rows[0])
+	}
+	if rows[1]["name"] != "other" {
+		t.Fatalf("expected other tenant untouched, got %v", rows[1])
+	}
+
+	// Rows 返回拷贝，修改不影响内部状态
+	rows[0]["name"] = "mutated"
+	if mem.Rows("users")[0]["name"] != "alice-v2" {
+		t.Fatal("expected Rows to return copies")
+	}
+	if mem.Count("missing") != 0 || mem.Rows("missing") != nil {
+		t.Fatal("expected unknown table to be empty")
+	}
+}
+
+func TestInMemoryExecutor_WithBatchFlow(t *testing.T) {
+	ctx := context.Background()
+	mem := batchflow.NewInMemoryExecutor()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 16, FlushSize: 4, FlushInterval: 10 * time.Millisecond},
+		Executor: mem,
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 10; i++ {
+		_ = b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i%5))
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for mem.Count("events") != 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 5 distinct rows, got %d", mem.Count("events"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	_ = b.Close()
+}
+
+func TestInMemoryExecutor_DeletesMarkedKeys(t *testing.T) {
+	ctx := context.Background()
+	mem := batchflow.NewInMemoryExecutor()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 16, FlushSize: 16, FlushInterval: time.Hour},
+		Executor: mem,
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	cfg := batchflow.ConflictUpdateOperationConfig.WithConflictColumns("tenant", "id")
+	schema := batchflow.NewSQLSchema("accounts", cfg, "tenant", "id", "name")
+	requests := []*batchflow.Request{
+		batchflow.NewRequest(schema).SetString("tenant", "a").SetInt64("id", 1).SetString("name", "alice"),
+		batchflow.NewRequest(schema).SetString("tenant", "a").SetInt64("id", 2).SetString("name", "bob"),
+		batchflow.NewRequest(schema).SetString("tenant", "b").SetInt64("id", 1).SetString("name", "carol"),
+		batchflow.NewRequest(schema).SetString("tenant", "a").SetInt64("id", 1).MarkDelete(),
+		batchflow.NewRequest(schema).SetString("tenant", "c").SetInt64("id", 9).MarkDelete(),
+	}
+	for _, r := range requests {
+		if err := b.Submit(ctx, r); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	rows := mem.Rows("accounts")
+	if len(rows) != 2 || rows[0]["name"] != "bob" || rows[1]["name"] != "carol" {
+		t.Fatalf("expected only bob and carol to remain, got %v", rows)
+	}
+	// 删除后重建的索引仍能正确处理冲突
+	if err := mem.ExecuteBatch(ctx, schema, []map[string]any{{"tenant": "b", "id": int64(1), "name": "carol-2"}}); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if rows := mem.Rows("accounts"); len(rows) != 2 || rows[1]["name"] != "carol-2" {
+		t.Fatalf("expected carol to be updated in place, got %v", rows)
+	}
+}
@@ -0,0 +1,59 @@
+package batchflow
+
+import (
+	"runtime"
+	"time"
+)
+
+const (
+	// defaultMemoryPressureCheckInterval MemoryPressureCheckInterval 的默认值
+	defaultMemoryPressureCheckInterval = 100 * time.Millisecond
+	// memoryPressureFlushSizeDivisor 内存压力期间 FlushSize 缩小的倍数
+	memoryPressureFlushSizeDivisor = 4
+)
+
+// HeapAllocFunc 返回当前堆内存占用（字节），用于内存压力检测
+type HeapAllocFunc func() uint64
+
+// runtimeHeapAlloc 默认的堆内存读取：runtime.ReadMemStats 的 HeapAlloc
+func runtimeHeapAlloc() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// UnderMemoryPressure 报告当前是否处于内存压力状态（堆占用超过 MemoryPressureThreshold）
+func (b *BatchFlow) UnderMemoryPressure() bool {
+	return b.memPressure.Load()
+}
+
+// watchMemoryPressure 周期性读取堆占用：超过阈值时将主通道定时间隔临时缩短为 forcedFlushInterval
+// 立即 flush，并把 FlushSize 缩小为 1/4，使缓冲数据以更小的批次尽快落库；回落到阈值以下后恢复配置值。
+// 协程随 BatchFlow 退出而结束。
+func (b *BatchFlow) watchMemoryPressure(threshold uint64, interval time.Duration, heapAlloc HeapAllocFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+		}
+		alloc := heapAlloc()
+		over := alloc > threshold
+		if over == b.memPressure.Load() {
+			continue
+		}
+		b.memPressure.Store(over)
+		if over {
+			b.pipeline.UpdateFlushSize(max(1, b.flushSize.Load()/memoryPressureFlushSizeDivisor))
+			b.pipeline.UpdateFlushInterval(forcedFlushInterval)
+			b.logger.Warn("batchflow memory pressure", "heap_alloc", alloc, "threshold", threshold)
+			continue
+		}
+		b.pipeline.UpdateFlushSize(b.flushSize.Load())
+		if !b.ageForcing.Load() {
+			b.pipeline.UpdateFlushInterval(b.EffectiveFlushInterval())
+		}
+	}
+}
@@ -65,6 +65,56 @@ type BatchFlowMetricsReporter interface {
 	ObserveSchemaGroupsPerFlush(n int)
 }
 
+// FlushGroupsMetricsReporter 是 flush 分组分布的可选扩展接口。
+// 每次 flush 完成按 schema 分组后调用一次，sizes 按 schema 在批内首次出现的顺序排列，
+// 可用于发现意外的 schema 扇出（单次 flush 涉及过多 schema 或组大小严重不均）。
+type FlushGroupsMetricsReporter interface {
+	ObserveFlushGroups(groupCount int, sizes []int)
+}
+
+// ConcurrencyMetricsReporter 是并发限流等待的可选扩展接口。
+// 启用 ConcurrencyLimit 时，每个批次获取执行令牌前的等待时长会通过该方法上报，
+// 用于区分“排队等待令牌”与“数据库执行慢”。未启用限流时不会调用。
+type ConcurrencyMetricsReporter interface {
+	ObserveConcurrencyWait(d time.Duration)
+}
+
+// QueueMetricsReporter 是数据通道高水位的可选扩展接口。
+// 每次 flush 开始时上报自上次 flush 以来 Submit 观测到的最大队列长度并重置窗口；
+// 相比 SetQueueLength 的瞬时值，更适合据此调整 BufferSize。
+type QueueMetricsReporter interface {
+	ObserveQueueHighWater(n int)
+}
+
+// SubmitBlockedMetricsReporter 是提交背压的可选扩展接口。
+// 数据通道已满、Submit 需要阻塞等待时，上报从开始等待到入队成功（或 ctx 取消放弃）的阻塞时长；
+// 未阻塞的提交不会调用。与 ObserveEnqueueLatency 相比，它只包含背压部分且覆盖放弃的等待。
+type SubmitBlockedMetricsReporter interface {
+	ObserveSubmitBlocked(d time.Duration)
+}
+
+// BatchBytesMetricsReporter 是批次内存占用的可选扩展接口。
+// 每次组装完成后（与 ObserveBatchAssemble 同时）上报该批行数据的近似字节数：
+// 字符串/[]byte 按长度、数值与时间按定长、列名按长度累加（压缩/加密后的值按结果长度计），
+// 用于按内存预算调整 BufferSize/FlushSize。未实现时不做估算，没有额外开销。
+type BatchBytesMetricsReporter interface {
+	ObserveBatchBytes(n int)
+}
+
+// SchemaMetricsReporter 是组装阶段按 schema 区分的可选扩展接口。
+// 实现后，每个 schema 分组组装完成时改为调用以下方法（不再调用 ObserveBatchSize/ObserveBatchAssemble），
+// 便于在多 schema 的 flush 中定位组装开销；schema 为 SchemaInterface.Name()。未实现时保持原有调用。
+type SchemaMetricsReporter interface {
+	ObserveSchemaBatchSize(schema string, n int)
+	ObserveSchemaBatchAssemble(schema string, d time.Duration)
+}
+
+// ConfigMetricsReporter 是管道配置导出的可选扩展接口。
+// BatchFlow 构造时调用一次，可导出为 gauge，便于在面板中将运行表现与配置对照。
+type ConfigMetricsReporter interface {
+	SetConfig(bufferSize, flushSize uint32, flushInterval time.Duration, concurrencyLimit int)
+}
+
 // OperationMetricsReporter is the preferred backend-neutral extension for generated
 // operation diagnostics. Implementations should keep labels low-cardinality and
 // never use raw payloads as labels.
@@ -0,0 +1,67 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestMockDriver_FailGenerationPropagatesToErrorChan(t *testing.T) {
+	ctx := context.Background()
+	db, conn := openFlakyDB(t, 0)
+	injected := errors.New("unsupported value type for column payload")
+	driver := batchflow.NewMockDriver("mysql").FailGeneration(injected)
+
+	exec := batchflow.NewThrottledBatchExecutor(batchflow.NewSQLBatchProcessor(db, driver)).
+		WithRetryConfig(batchflow.RetryConfig{Enabled: true, MaxAttempts: 3, BackoffBase: time.Millisecond})
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 8, FlushSize: 1, FlushInterval: time.Hour},
+		Executor: exec,
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	defer b.Close()
+	errs := b.ErrorChan(8)
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id", "payload")
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 1).SetString("payload", "x")); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	var got error
+	select {
+	case got = <-errs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected generation error on ErrorChan")
+	}
+	if !errors.Is(got, injected) {
+		t.Fatalf("expected injected error, got %v", got)
+	}
+	var batchErr *batchflow.BatchError
+	if !errors.As(got, &batchErr) || batchErr.Stage != batchflow.BatchStageGenerate {
+		t.Fatalf("expected generate-stage BatchError, got %#v", got)
+	}
+	if retryable, _ := batchflow.ClassifyError(got); retryable {
+		t.Fatal("generation error must be non-retryable")
+	}
+	if n := conn.execs.Load(); n != 0 {
+		t.Fatalf("batch must not be executed, got %d execs", n)
+	}
+}
+
+func TestMockDriver_FailGenerationReset(t *testing.T) {
+	driver := batchflow.NewMockDriver("mysql").FailGeneration(errors.New("boom"))
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id")
+	rows := []map[string]any{{"id": 1}}
+	if _, _, err := driver.GenerateInsertSQL(context.Background(), schema, rows); err == nil {
+		t.Fatal("expected injected error")
+	}
+	driver.FailGeneration(nil)
+	if _, _, err := driver.GenerateInsertSQL(context.Background(), schema, rows); err != nil {
+		t.Fatalf("expected generation to recover, got %v", err)
+	}
+}
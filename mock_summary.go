@@ -0,0 +1,81 @@
+package batchflow
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"time"
+)
+
+// MockSummary MockExecutor 的累计摘要，用于大规模测试校验行数与数据完整性
+type MockSummary struct {
+	// Batches 成功执行的批次数
+	Batches int64
+	// Rows 成功执行的总行数
+	Rows int64
+	// SchemaRows 按 schema 名称统计的行数
+	SchemaRows map[string]int64
+	// Checksum 所有行的校验和（见 ChecksumRows）；未启用校验和时为 0
+	Checksum uint64
+}
+
+// WithSummaryOnly 切换为摘要模式：不再保留 ExecutedBatches 中的行数据，只累计 Summary() 所需的统计，
+// 适合百万行级别的测试。checksum 为 true 时同时累计行校验和（每行一次哈希的额外开销）。
+// 应在开始执行前设置。
+func (e *MockExecutor) WithSummaryOnly(checksum bool) *MockExecutor {
+	e.mu.Lock()
+	e.summaryOnly = true
+	e.checksum = checksum
+	e.mu.Unlock()
+	return e
+}
+
+// Summary 返回当前累计摘要（拷贝）；非摘要模式下同样可用，此时 Checksum 为 0
+func (e *MockExecutor) Summary() MockSummary {
+	summary := MockSummary{SchemaRows: make(map[string]int64), Checksum: e.rowsSum.Load()}
+	e.statsMu.Lock()
+	for name, s := range e.stats {
+		summary.Batches += s.Batches
+		summary.Rows += s.Rows
+		summary.SchemaRows[name] = s.Rows
+	}
+	e.statsMu.Unlock()
+	return summary
+}
+
+// ChecksumRows 计算行集合的校验和：每行按列名排序后做 FNV-64a 哈希，再对各行哈希求和（模 2^64）。
+// 结果与行的顺序及批次划分无关，测试可以对提交的数据调用它，与 MockSummary.Checksum 比较。
+// 值按 fmt 的默认格式参与哈希（time.Time 统一为 UTC RFC3339Nano），因此 int 与 int64 的同值视为相同。
+func ChecksumRows(rows ...map[string]any) uint64 {
+	var sum uint64
+	h := fnv.New64a()
+	var keys []string
+	for _, row := range rows {
+		keys = keys[:0]
+		for k := range row {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		h.Reset()
+		for _, k := range keys {
+			h.Write([]byte(k))
+			h.Write([]byte{0})
+			switch v := row[k].(type) {
+			case nil:
+				h.Write([]byte{'n'})
+			case []byte:
+				h.Write([]byte{'b'})
+				h.Write(v)
+			case time.Time:
+				h.Write([]byte{'t'})
+				h.Write([]byte(v.UTC().Format(time.RFC3339Nano)))
+			default:
+				h.Write([]byte{'v'})
+				fmt.Fprint(h, v)
+			}
+			h.Write([]byte{0})
+		}
+		sum += h.Sum64()
+	}
+	return sum
+}
@@ -0,0 +1,64 @@
+package batchflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestMockExecutor_SummaryOnlyMatchesSubmittedRequests(t *testing.T) {
+	ctx := context.Background()
+	mock := batchflow.NewMockExecutor().WithSummaryOnly(true)
+	b := batchflow.NewBatchFlow(ctx, 256, 50, 5*time.Millisecond, mock)
+
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	orders := batchflow.NewSQLSchema("orders", batchflow.ConflictIgnoreOperationConfig, "id", "amount")
+	var expected []map[string]any
+	for i := 0; i < 1000; i++ {
+		if i%4 == 0 {
+			if err := b.Submit(ctx, batchflow.NewRequest(orders).SetInt64("id", int64(i)).SetFloat64("amount", float64(i)/2)); err != nil {
+				t.Fatalf("submit: %v", err)
+			}
+			expected = append(expected, map[string]any{"id": int64(i), "amount": float64(i) / 2})
+			continue
+		}
+		if err := b.Submit(ctx, batchflow.NewRequest(users).SetInt64("id", int64(i)).SetString("name", "u")); err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		expected = append(expected, map[string]any{"id": int64(i), "name": "u"})
+	}
+	waitUntilEmpty(t, b)
+	if err := b.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	summary := mock.Summary()
+	if summary.Rows != 1000 || summary.SchemaRows["users"] != 750 || summary.SchemaRows["orders"] != 250 {
+		t.Fatalf("unexpected summary counts: %+v", summary)
+	}
+	if summary.Batches == 0 {
+		t.Fatalf("expected batches to be counted")
+	}
+	if want := batchflow.ChecksumRows(expected...); summary.Checksum != want {
+		t.Fatalf("checksum mismatch: got %x want %x", summary.Checksum, want)
+	}
+	if got := len(mock.SnapshotExecutedBatches()); got != 0 {
+		t.Fatalf("summary mode should not retain rows, got %d batches", got)
+	}
+}
+
+func TestChecksumRows_DetectsChangedOrMissingRows(t *testing.T) {
+	rows := []map[string]any{{"id": 1, "name": "a"}, {"id": 2, "name": nil}}
+	base := batchflow.ChecksumRows(rows...)
+	if batchflow.ChecksumRows(rows[1], rows[0]) != base {
+		t.Fatalf("checksum should not depend on row order")
+	}
+	if batchflow.ChecksumRows(rows[0]) == base {
+		t.Fatalf("checksum should change when a row is missing")
+	}
+	if batchflow.ChecksumRows(rows[0], map[string]any{"id": 2, "name": ""}) == base {
+		t.Fatalf("checksum should distinguish NULL from empty string")
+	}
+}
@@ -0,0 +1,112 @@
+package batchflow
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MySQLOption 配置 MySQLDriver 的可选行为，用于 NewMySQLDriver 与 NewMySQLBatchFlow
+type MySQLOption func(*MySQLDriver)
+
+// mysqlRowAliasMinVersion MySQL 8.0.19 起支持 INSERT ... AS alias，并弃用 VALUES(col) 引用
+var mysqlRowAliasMinVersion = [3]int{8, 0, 19}
+
+// mysqlDefaultRowAlias WithMySQLVersion 自动启用行别名时使用的别名
+const mysqlDefaultRowAlias = "new"
+
+// WithMySQLVersion 声明服务端版本（如 "8.0.32"、"8.0.36-log"）。版本 >= 8.0.19 时，ConflictUpdate 使用
+// 行别名形式 "INSERT ... AS new ON DUPLICATE KEY UPDATE col = new.col"，避免已弃用的 VALUES(col) 产生警告；
+// 较低或无法解析的版本（如 MariaDB 的 "10.11.6-MariaDB"）保持 VALUES(col)。显式的 WithMySQLRowAlias 优先。
+func WithMySQLVersion(version string) MySQLOption {
+	return func(d *MySQLDriver) {
+		v, ok := parseMySQLVersion(version)
+		if !ok || d.rowAliasSet {
+			return
+		}
+		if compareVersion(v, mysqlRowAliasMinVersion) >= 0 {
+			d.rowAlias = mysqlDefaultRowAlias
+		} else {
+			d.rowAlias = ""
+		}
+	}
+}
+
+// WithMySQLRowAlias 指定 ConflictUpdate 使用的行别名（要求 MySQL 8.0.19+）；空字符串强制使用 VALUES(col)。
+func WithMySQLRowAlias(alias string) MySQLOption {
+	return func(d *MySQLDriver) {
+		d.rowAlias = alias
+		d.rowAliasSet = true
+	}
+}
+
+// WithMySQLQuotedIdentifiers 为 true 时用反引号引用表名（按 "." 分段）与列名，
+// 以支持保留字或特殊字符命名的表/列；已带反引号的标识符原样保留。
+func WithMySQLQuotedIdentifiers(enabled bool) MySQLOption {
+	return func(d *MySQLDriver) {
+		d.quoteIdentifiers = enabled
+	}
+}
+
+// DetectMySQLVersion 查询 SELECT VERSION()，结果可传给 WithMySQLVersion
+func DetectMySQLVersion(ctx context.Context, db *sql.DB) (string, error) {
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		return "", fmt.Errorf("detect mysql version: %w", err)
+	}
+	return version, nil
+}
+
+// parseMySQLVersion 解析 "major.minor.patch[-suffix]"；MariaDB 版本视为无法解析（不支持行别名）
+func parseMySQLVersion(version string) ([3]int, bool) {
+	var v [3]int
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		return v, false
+	}
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(strings.TrimSpace(version), ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+func compareVersion(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// quoteMySQLIdentifier 用反引号引用标识符，内部反引号按 MySQL 规则加倍
+func quoteMySQLIdentifier(name string) string {
+	if len(name) >= 2 && strings.HasPrefix(name, "`") && strings.HasSuffix(name, "`") {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteMySQLTable 按 "." 分段引用表名（如 db.table -> `db`.`table`）
+func quoteMySQLTable(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quoteMySQLIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,62 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestMySQLDriverOptions(t *testing.T) {
+	ctx := context.Background()
+	schema := batchflow.NewSQLSchema("app.order", batchflow.ConflictUpdateOperationConfig.WithConflictColumns("id"), "id", "status")
+	data := []map[string]any{{"id": 1, "status": "paid"}}
+
+	tests := []struct {
+		name string
+		opts []batchflow.MySQLOption
+		want string
+	}{
+		{"default", nil, "INSERT INTO app.order (id, status) VALUES (?, ?) ON DUPLICATE KEY UPDATE status = VALUES(status)"},
+		{"old version", []batchflow.MySQLOption{batchflow.WithMySQLVersion("5.7.44-log")}, "INSERT INTO app.order (id, status) VALUES (?, ?) ON DUPLICATE KEY UPDATE status = VALUES(status)"},
+		{"mariadb", []batchflow.MySQLOption{batchflow.WithMySQLVersion("10.11.6-MariaDB")}, "INSERT INTO app.order (id, status) VALUES (?, ?) ON DUPLICATE KEY UPDATE status = VALUES(status)"},
+		{"row alias by version", []batchflow.MySQLOption{batchflow.WithMySQLVersion("8.0.36")}, "INSERT INTO app.order (id, status) VALUES (?, ?) AS new ON DUPLICATE KEY UPDATE status = new.status"},
+		{"explicit alias wins", []batchflow.MySQLOption{batchflow.WithMySQLRowAlias("r"), batchflow.WithMySQLVersion("5.7.0")}, "INSERT INTO app.order (id, status) VALUES (?, ?) AS r ON DUPLICATE KEY UPDATE status = r.status"},
+		{"quoted", []batchflow.MySQLOption{batchflow.WithMySQLQuotedIdentifiers(true), batchflow.WithMySQLVersion("8.4.0")}, "INSERT INTO `app`.`order` (`id`, `status`) VALUES (?, ?) AS `new` ON DUPLICATE KEY UPDATE `status` = `new`.`status`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := batchflow.NewMySQLDriver(tt.opts...).GenerateInsertSQL(ctx, schema, data)
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMySQLBatchFlowWithOptions(t *testing.T) {
+	d := &execModeDriver{}
+	db := sql.OpenDB(execModeConnector{d: d})
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	b := batchflow.NewMySQLBatchFlow(ctx, db, batchflow.PipelineConfig{BufferSize: 10, FlushSize: 1, FlushInterval: time.Hour},
+		batchflow.WithMySQLQuotedIdentifiers(true))
+	schema := batchflow.NewSQLSchema("order", batchflow.ConflictIgnoreOperationConfig, "id", "key")
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 1).SetString("key", "k")); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	waitUntilEmpty(t, b)
+	b.Close()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if want := "INSERT IGNORE INTO `order` (`id`, `key`) VALUES (?, ?)"; len(d.queries) != 1 || d.queries[0] != want {
+		t.Fatalf("expected %q, got %v", want, d.queries)
+	}
+}
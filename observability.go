@@ -17,6 +17,7 @@ const (
 
 	OperationInsert  = "insert"
 	OperationUpsert  = "upsert"
+	OperationDelete  = "delete"
 	OperationCommand = "command"
 	OperationCustom  = "custom"
 
@@ -237,6 +238,9 @@ func (o *slogObserver) OnBatchEvent(ctx context.Context, event BatchEvent) {
 		"fingerprint", event.Fingerprint,
 		"reason", event.Reason,
 	}
+	if id, ok := BatchIDFromContext(ctx); ok {
+		attrs = append(attrs, "batch_id", id)
+	}
 	for key, value := range event.Attributes {
 		attrs = append(attrs, key, o.redact(key, value))
 	}
@@ -0,0 +1,170 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/rushairer/batchflow/v2"
+)
+
+// fakeExecMode 模拟 pgx.QueryExecMode：作为首个参数传入，驱动识别为查询选项
+type fakeExecMode int32
+
+const fakeExecModeBinary fakeExecMode = 2
+
+// execModeDriver 模拟 pgx 的 database/sql 驱动：接受任意参数类型（NamedValueChecker），
+// 剥离首个查询模式参数并记录语句与其余参数值
+type execModeDriver struct {
+	mu      sync.Mutex
+	modes   []fakeExecMode
+	args    [][]any
+	queries []string
+}
+
+func (d *execModeDriver) Open(string) (driver.Conn, error) { return &execModeConn{d: d}, nil }
+
+type execModeConn struct{ d *execModeDriver }
+
+func (c *execModeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *execModeConn) Close() error                        { return nil }
+func (c *execModeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *execModeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *execModeConn) ExecContext(_ context.Context, query string, named []driver.NamedValue) (driver.Result, error) {
+	mode := fakeExecMode(0)
+	if len(named) > 0 {
+		if m, ok := named[0].Value.(fakeExecMode); ok {
+			mode, named = m, named[1:]
+		}
+	}
+	args := make([]any, len(named))
+	for i, nv := range named {
+		args[i] = nv.Value
+	}
+	c.d.mu.Lock()
+	c.d.modes = append(c.d.modes, mode)
+	c.d.args = append(c.d.args, args)
+	c.d.queries = append(c.d.queries, query)
+	c.d.mu.Unlock()
+	return driver.RowsAffected(int64(len(args))), nil
+}
+
+type execModeConnector struct{ d *execModeDriver }
+
+func (c execModeConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c execModeConnector) Driver() driver.Driver                        { return c.d }
+
+func TestSQLBatchProcessor_PostgresBinaryFormatRoundTrip(t *testing.T) {
+	d := &execModeDriver{}
+	db := sql.OpenDB(execModeConnector{d: d})
+	t.Cleanup(func() { _ = db.Close() })
+
+	var hookArgs int
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultPostgreSQLDriver).
+		WithPostgresBinaryFormat(fakeExecModeBinary).
+		WithExecuteHook(func(_ context.Context, _ string, args []any, next func() error) error {
+			hookArgs = len(args)
+			return next()
+		})
+	schema := batchflow.NewSQLSchema("metrics", batchflow.ConflictIgnoreOperationConfig, "id", "value", "ratio")
+	data := []map[string]any{
+		{"id": int64(math.MaxInt64), "value": int32(math.MinInt32), "ratio": math.SmallestNonzeroFloat64},
+		{"id": int64(-1), "value": int32(0), "ratio": -1.5e300},
+	}
+	if err := batchflow.NewThrottledBatchExecutor(processor).ExecuteBatch(context.Background(), schema, data); err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+
+	if len(d.modes) != 1 || d.modes[0] != fakeExecModeBinary {
+		t.Fatalf("expected exec mode to reach the driver once, got %v", d.modes)
+	}
+	want := []any{int64(math.MaxInt64), int32(math.MinInt32), math.SmallestNonzeroFloat64, int64(-1), int32(0), -1.5e300}
+	if fmt.Sprint(d.args[0]) != fmt.Sprint(want) {
+		t.Fatalf("values did not round-trip: got %v want %v", d.args[0], want)
+	}
+	for i := range want {
+		if d.args[0][i] != want[i] {
+			t.Fatalf("arg %d: got %#v want %#v", i, d.args[0][i], want[i])
+		}
+	}
+	if hookArgs != len(want) {
+		t.Fatalf("execute hook should see only bind args, got %d", hookArgs)
+	}
+}
+
+func TestSQLBatchProcessor_PostgresBinaryFormatDisabledByDefault(t *testing.T) {
+	d := &execModeDriver{}
+	db := sql.OpenDB(execModeConnector{d: d})
+	t.Cleanup(func() { _ = db.Close() })
+
+	schema := batchflow.NewSQLSchema("metrics", batchflow.ConflictIgnoreOperationConfig, "id")
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultPostgreSQLDriver)
+	if err := batchflow.NewThrottledBatchExecutor(processor).ExecuteBatch(context.Background(), schema, []map[string]any{{"id": int64(7)}}); err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if d.modes[0] != 0 || len(d.args[0]) != 1 {
+		t.Fatalf("no exec mode expected without the option, got mode=%v args=%v", d.modes[0], d.args[0])
+	}
+}
+
+// BenchmarkPostgresParamFormat 对比数值密集批次在文本与二进制参数格式下的写入耗时。
+// 需要真实 PostgreSQL：设置 BATCHFLOW_POSTGRES_DSN（lib/pq 格式）启用，否则跳过。
+// lib/pq 通过 DSN 的 binary_parameters=yes 切换；使用 pgx 时改为 WithPostgresBinaryFormat(pgx.QueryExecModeCacheDescribe)。
+func BenchmarkPostgresParamFormat(b *testing.B) {
+	dsn := os.Getenv("BATCHFLOW_POSTGRES_DSN")
+	if dsn == "" {
+		b.Skip("set BATCHFLOW_POSTGRES_DSN to benchmark against PostgreSQL")
+	}
+	schema := batchflow.NewSQLSchema("batchflow_bench_numeric", batchflow.ConflictIgnoreOperationConfig, "id", "a", "b", "c", "d")
+	rows := make([]map[string]any, 500)
+
+	for _, mode := range []struct{ name, dsn string }{
+		{"text", dsn},
+		{"binary", dsnWithParam(dsn, "binary_parameters=yes")},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			db, err := sql.Open("postgres", mode.dsn)
+			if err != nil {
+				b.Fatalf("open: %v", err)
+			}
+			defer db.Close()
+			if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS batchflow_bench_numeric (id BIGINT PRIMARY KEY, a BIGINT, b DOUBLE PRECISION, c INTEGER, d DOUBLE PRECISION)`); err != nil {
+				b.Fatalf("create table: %v", err)
+			}
+			if _, err := db.Exec(`TRUNCATE batchflow_bench_numeric`); err != nil {
+				b.Fatalf("truncate: %v", err)
+			}
+			executor := batchflow.NewThrottledBatchExecutor(batchflow.NewSQLBatchProcessor(db, batchflow.DefaultPostgreSQLDriver))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := range rows {
+					id := int64(i*len(rows) + j)
+					rows[j] = map[string]any{"id": id, "a": id * 31, "b": float64(id) / 7, "c": int32(j), "d": math.Sqrt(float64(id))}
+				}
+				if err := executor.ExecuteBatch(context.Background(), schema, rows); err != nil {
+					b.Fatalf("ExecuteBatch: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func dsnWithParam(dsn, param string) string {
+	if strings.Contains(dsn, "://") {
+		if strings.Contains(dsn, "?") {
+			return dsn + "&" + param
+		}
+		return dsn + "?" + param
+	}
+	return dsn + " " + param
+}
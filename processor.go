@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -32,8 +35,28 @@ type SQLBatchProcessor struct {
 	db      *sql.DB   // 数据库连接
 	driver  SQLDriver // SQL生成器（数据库特定）
 	timeout time.Duration
+
+	healthCheckInterval time.Duration
+	lastExecNanos       atomic.Int64 // 上次执行完成时间（UnixNano），用于判断空闲时长
+
+	sampleRate float64
+	sampleSink StatementSink
+
+	executeHook ExecuteHook
+	execMode    any           // 非 nil 时作为首个参数传给 ExecContext（WithPostgresBinaryFormat）
+	maxConnWait time.Duration // 执行前获取连接的最长等待（WithMaxConnWait）；0 表示不预取连接
+
+	deadLetter               TaggedDeadLetterFunc // 非 nil 时启用逐行保存点模式（WithRowSavepoints/WithRowSavepointsTagged）
+	savepointDeadlockRetries int                  // 逐行保存点模式下死锁语句的事务内重试次数（WithSavepointDeadlockRetries）
 }
 
+// StatementSink 接收被采样的 SQL 语句：仅包含 SQL 文本与参数个数（不含参数值，避免泄露 PII）及执行耗时
+type StatementSink func(sql string, argCount int, d time.Duration)
+
+// ExecuteHook 包裹每次数据语句的 ExecContext 调用：sql/args 为生成的语句与参数，
+// 调用 next() 才会真正执行；不调用则短路，返回值即为本次执行结果
+type ExecuteHook func(ctx context.Context, sql string, args []any, next func() error) error
+
 var _ BatchProcessor = (*SQLBatchProcessor)(nil)
 
 // NewSQLBatchProcessor 创建SQL批量处理器
@@ -52,6 +75,95 @@ func (bp *SQLBatchProcessor) WithTimeout(timeout time.Duration) *SQLBatchProcess
 	return bp
 }
 
+// WithHealthCheck 启用空闲健康检查：距上次执行超过 interval 时，先 PingContext 再执行语句，
+// 让 database/sql 在真正执行前剔除已失效的空闲连接（如数据库重启后）。interval <= 0 表示关闭。
+func (bp *SQLBatchProcessor) WithHealthCheck(interval time.Duration) *SQLBatchProcessor {
+	bp.healthCheckInterval = interval
+	return bp
+}
+
+// WithStatementSampler 按 rate（0~1）采样批次，将执行的 SQL 文本、参数个数与耗时交给 sink，
+// 成功与失败的执行都会被采样。rate <= 0 或 sink 为 nil 时关闭，rate >= 1 表示全部采样。
+func (bp *SQLBatchProcessor) WithStatementSampler(rate float64, sink StatementSink) *SQLBatchProcessor {
+	bp.sampleRate = rate
+	bp.sampleSink = sink
+	return bp
+}
+
+// WithExecuteHook 在实际的数据库调用外包裹钩子，用于限流、审计或注入故障，而无需替换处理器。
+// 钩子对每条数据语句调用一次（WithRowSavepoints 模式下逐行调用，SAVEPOINT 控制语句不经过钩子）；
+// 钩子返回的错误与数据库错误一样参与重试分类。nil 表示移除钩子。
+func (bp *SQLBatchProcessor) WithExecuteHook(hook ExecuteHook) *SQLBatchProcessor {
+	bp.executeHook = hook
+	return bp
+}
+
+// WithPostgresBinaryFormat 为 pgx（github.com/jackc/pgx/v5/stdlib）请求二进制参数格式：
+// execMode 传入 pgx 的查询模式，如 pgx.QueryExecModeCacheDescribe，它会作为首个参数交给每次 ExecContext，
+// 由 pgx 的 database/sql 驱动识别为查询选项而非绑定参数，从而使用扩展协议按二进制格式编码数值等参数。
+// 为避免引入 pgx 依赖，这里不检查类型；仅在 pgx 驱动上使用——lib/pq 等驱动会把它当作多余参数并报错。
+// lib/pq 不支持按查询切换，可在 DSN 中设置 binary_parameters=yes（仅对 []byte 参数生效）。
+// 执行钩子与语句采样看到的 args 不包含该值。nil 表示关闭。
+func (bp *SQLBatchProcessor) WithPostgresBinaryFormat(execMode any) *SQLBatchProcessor {
+	bp.execMode = execMode
+	return bp
+}
+
+// execArgs 返回传给 ExecContext 的参数：配置了 execMode 时置于最前
+func (bp *SQLBatchProcessor) execArgs(args []any) []any {
+	if bp.execMode == nil {
+		return args
+	}
+	return append([]any{bp.execMode}, args...)
+}
+
+// runExec 依次应用执行钩子与语句采样后调用 execFn
+func (bp *SQLBatchProcessor) runExec(ctx context.Context, query string, args []any, execFn func() error) error {
+	next := execFn
+	if bp.shouldSample() {
+		next = func() error {
+			start := time.Now()
+			err := execFn()
+			bp.sampleSink(query, len(args), time.Since(start))
+			return err
+		}
+	}
+	if bp.executeHook == nil {
+		return next()
+	}
+	return bp.executeHook(ctx, query, args, next)
+}
+
+func (bp *SQLBatchProcessor) shouldSample() bool {
+	if bp.sampleSink == nil || bp.sampleRate <= 0 {
+		return false
+	}
+	return bp.sampleRate >= 1 || rand.Float64() < bp.sampleRate
+}
+
+// exec 执行 SQL，经过执行钩子，并在命中采样时上报语句与耗时
+func (bp *SQLBatchProcessor) exec(ctx context.Context, execer sqlExecer, query string, args []any) error {
+	return bp.runExec(ctx, query, args, func() error {
+		_, err := execer.ExecContext(ctx, query, bp.execArgs(args)...)
+		return err
+	})
+}
+
+// pingIfIdle 在空闲超过阈值时探活；失败返回可被重试分类识别的执行阶段错误
+func (bp *SQLBatchProcessor) pingIfIdle(ctx context.Context) error {
+	if bp.healthCheckInterval <= 0 {
+		return nil
+	}
+	last := bp.lastExecNanos.Load()
+	if last != 0 && time.Since(time.Unix(0, last)) < bp.healthCheckInterval {
+		return nil
+	}
+	if err := bp.db.PingContext(ctx); err != nil {
+		return &SQLError{Stage: SQLStageExecute, Cause: fmt.Errorf("health check ping: %w", err)}
+	}
+	return nil
+}
+
 func (bp *SQLBatchProcessor) GenerateSQLPreview(ctx context.Context, schema *SQLSchema, data []map[string]any) (SQLPreview, error) {
 	return GenerateSQLPreview(ctx, bp.driver, schema, data)
 }
@@ -66,6 +178,11 @@ func (bp *SQLBatchProcessor) GenerateOperationPreview(ctx context.Context, schem
 	if err != nil {
 		return nil, preview.OperationPreview(), err
 	}
+	if bp.deadLetter != nil {
+		// 预览仍描述整批，便于观测；实际执行走逐行保存点
+		operations, err := bp.generateSavepointOperations(ctx, s, data)
+		return operations, preview.OperationPreview(), err
+	}
 	operations := make(Operations, 0, 1+len(preview.Args))
 	operations = append(operations, preview.SQL)
 	operations = append(operations, preview.Args...)
@@ -77,6 +194,9 @@ func (bp *SQLBatchProcessor) GenerateOperations(ctx context.Context, schema Sche
 	if !ok {
 		return nil, &SQLError{Stage: SQLStageValidate, Table: schema.Name(), BatchSize: len(data), Cause: errors.New("schema is not a SQLSchema")}
 	}
+	if bp.deadLetter != nil {
+		return bp.generateSavepointOperations(ctx, s, data)
+	}
 
 	preview, innerErr := bp.GenerateSQLPreview(ctx, s, data)
 	if innerErr != nil {
@@ -92,9 +212,10 @@ SQL 执行语义：
   - 在设置了 bp.timeout 时，使用 context.WithTimeoutCause 派生子 ctx（具体 cause 如 "execute batch timeout"）。
   - 当子 ctx 达到超时时，驱动通常返回 context.DeadlineExceeded；本处理器会读取 context.Cause(ctx) 并原样返回该 cause，
     以便上层执行器的重试分类器可以区分“处理器内部超时”，按需实施重试与退避。
-  - 安全性：在执行前校验空 operations，避免越界；不持久化/返回子 ctx，defer cancel() 安全。
+  - 空 operations 或空 SQL 视为成功的空操作（返回 nil）；非法的操作类型仍返回 validate 阶段错误。
+  - 安全性：不持久化/返回子 ctx，defer cancel() 安全。
 */
-func (bp *SQLBatchProcessor) ExecuteOperations(ctx context.Context, operations Operations) error {
+func (bp *SQLBatchProcessor) ExecuteOperations(ctx context.Context, operations Operations) (err error) {
 	if bp.timeout > 0 {
 		ctxTimeout, cancel := context.WithTimeoutCause(ctx, bp.timeout, errors.New("execute batch timeout"))
 		defer cancel()
@@ -102,14 +223,37 @@ func (bp *SQLBatchProcessor) ExecuteOperations(ctx context.Context, operations O
 		ctx = ctxTimeout
 	}
 
-	if len(operations) < 1 {
-		return &SQLError{Stage: SQLStageValidate, Cause: errors.New("empty operations")}
+	// 真正的空批次（无操作，或驱动对空数据生成的空语句）视为成功的空操作，不探活也不访问数据库
+	if isEmptySQLOperations(operations) {
+		return nil
+	}
+
+	if err := bp.pingIfIdle(ctx); err != nil {
+		return err
+	}
+	if bp.healthCheckInterval > 0 {
+		// 仅成功执行后刷新空闲起点；失败（如连接失效）后的重试仍会先探活
+		defer func() {
+			if err == nil {
+				bp.lastExecNanos.Store(time.Now().UnixNano())
+			}
+		}()
+	}
+
+	execer, release, err := bp.acquireExecer(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if ops, ok := operations[0].(*savepointOperations); ok {
+		return bp.executeSavepoints(ctx, execer, ops)
 	}
 
 	// Compatibility path: older diagnostics/tests may pass SQLPreview directly as
 	// the first operation. Normal generation returns SQL string + args.
 	if preview, ok := operations[0].(SQLPreview); ok {
-		_, err := bp.db.ExecContext(ctx, preview.SQL, preview.Args...)
+		err := bp.exec(ctx, execer, preview.SQL, preview.Args)
 		if err != nil && errors.Is(err, context.DeadlineExceeded) {
 			if cause := context.Cause(ctx); cause != nil {
 				err = cause
@@ -133,7 +277,7 @@ func (bp *SQLBatchProcessor) ExecuteOperations(ctx context.Context, operations O
 
 	if sql, ok := operations[0].(string); ok {
 		args := sqlOperationArgs(operations)
-		_, err := bp.db.ExecContext(ctx, sql, args...)
+		err := bp.exec(ctx, execer, sql, args)
 		// processor 会捕获超时异常, 可以出发重试
 		if err != nil && errors.Is(err, context.DeadlineExceeded) {
 			if cause := context.Cause(ctx); cause != nil {
@@ -153,6 +297,19 @@ func (bp *SQLBatchProcessor) ExecuteOperations(ctx context.Context, operations O
 	return &SQLError{Stage: SQLStageValidate, Cause: errors.New("invalid operation type")}
 }
 
+func isEmptySQLOperations(operations Operations) bool {
+	if len(operations) == 0 {
+		return true
+	}
+	switch op := operations[0].(type) {
+	case string:
+		return op == "" && len(operations) == 1
+	case SQLPreview:
+		return op.SQL == "" && len(op.Args) == 0
+	}
+	return false
+}
+
 func sqlOperationArgs(operations Operations) []any {
 	return operations[1:]
 }
@@ -160,9 +317,13 @@ func sqlOperationArgs(operations Operations) []any {
 // RedisBatchProcessor Redis批量处理器
 // 实现 BatchProcessor 接口，专注于Redis的核心处理逻辑
 type RedisBatchProcessor struct {
-	client  *redis.Client // Redis客户端连接
-	driver  RedisDriver   // Redis操作生成器
-	timeout time.Duration
+	client    *redis.Client // Redis客户端连接
+	driver    RedisDriver   // Redis操作生成器
+	timeout   time.Duration
+	chunkSize int // 单个 Pipeline 的最大命令数；<= 0 表示整批一个 Pipeline
+
+	deadLetter TaggedDeadLetterFunc // 非 nil 时启用逐行死信模式（WithRowDeadLetter/WithRowDeadLetterTagged）
+	onResult   RedisCmdResultFunc   // 非 nil 时逐条回调命令结果（WithCmdResult）
 }
 
 var _ BatchProcessor = (*RedisBatchProcessor)(nil)
@@ -183,6 +344,15 @@ func (rp *RedisBatchProcessor) WithTimeout(timeout time.Duration) *RedisBatchPro
 	return rp
 }
 
+// WithPipelineChunkSize 将一批命令按每 n 条拆成多个 Pipeline 依次执行，避免超大 Pipeline 超出 Redis 输出缓冲限制。
+// 某个子 Pipeline 失败不会中断后续子 Pipeline，各段错误通过 errors.Join 聚合返回；ctx 取消/超时时立即返回。
+// 拆分不会切开 MULTI/EXEC 事务块；逐行模式（WithRowDeadLetter、WithCmdResult）下同一行的命令也不跨段，
+// 超过 n 条的单元独占一个 Pipeline。n <= 0 表示不拆分（默认）。
+func (rp *RedisBatchProcessor) WithPipelineChunkSize(n int) *RedisBatchProcessor {
+	rp.chunkSize = n
+	return rp
+}
+
 func (rp *RedisBatchProcessor) GenerateOperationPreview(ctx context.Context, schema SchemaInterface, data []map[string]any) (Operations, OperationPreview, error) {
 	operations, err := rp.GenerateOperations(ctx, schema, data)
 	preview := OperationPreview{
@@ -190,11 +360,11 @@ func (rp *RedisBatchProcessor) GenerateOperationPreview(ctx context.Context, sch
 		Operation:   OperationCommand,
 		Schema:      schema.Name(),
 		InputItems:  len(data),
-		OutputItems: len(operations),
+		OutputItems: len(redisOperationCmds(operations)),
 		ArgCount:    redisOperationArgCount(operations),
 		Fingerprint: redisOperationsFingerprint(schema, operations),
 		Attributes: map[string]any{
-			"commands": len(operations),
+			"commands": len(redisOperationCmds(operations)),
 			"columns":  len(schema.Columns()),
 		},
 	}
@@ -219,6 +389,14 @@ func (rp *RedisBatchProcessor) GenerateOperations(ctx context.Context, schema Sc
 		return nil, errors.New("schema is not a Schema")
 	}
 
+	if rp.deadLetter != nil || rp.onResult != nil {
+		ops, err := rp.generateRowCmds(ctx, s, data)
+		if err != nil {
+			return nil, err
+		}
+		return Operations{ops}, nil
+	}
+
 	cmds, innerErr := rp.driver.GenerateCmds(ctx, s, data)
 	if innerErr != nil {
 		return nil, innerErr
@@ -244,20 +422,98 @@ func (rp *RedisBatchProcessor) ExecuteOperations(ctx context.Context, operations
 		ctx = ctxTimeout
 	}
 
-	// 使用Pipeline批量执行
-	pipeline := rp.client.Pipeline()
+	if len(operations) == 1 {
+		if ops, ok := operations[0].(*redisRowOperations); ok {
+			return rp.executeRowOperations(ctx, ops)
+		}
+	}
+
+	cmds := redisOperationCmds(operations)
+	var errs []error
+	for _, bound := range redisChunkBounds(len(cmds), rp.chunkSize, redisTxUnitEnd(cmds)) {
+		if err := rp.execPipeline(ctx, cmds[bound[0]:bound[1]]); err != nil {
+			if ctx.Err() != nil {
+				// 已取消/超时，后续子 Pipeline 必然失败
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 1 {
+		// 单段失败保持原始错误值（与不拆分时一致）
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
 
-	for _, operation := range operations {
+// redisChunkBounds 按 size 将 n 条命令切分为若干 [start, end) 区间；unitEnd(i) 返回从 i 开始的不可拆分单元的结束下标。
+// 单元不跨段（超过 size 的单元独占一段），避免 MULTI 与 EXEC 落在两个 Pipeline（两条池化连接）上。size <= 0 表示不拆分
+func redisChunkBounds(n, size int, unitEnd func(start int) int) [][2]int {
+	if size <= 0 || size > n {
+		size = n
+	}
+	var bounds [][2]int
+	for start := 0; start < n; {
+		end := start
+		for end < n {
+			next := unitEnd(end)
+			if end > start && next-start > size {
+				break
+			}
+			end = next
+		}
+		bounds = append(bounds, [2]int{start, end})
+		start = end
+	}
+	return bounds
+}
+
+// redisTxUnitEnd 不可拆分单元为 MULTI…EXEC/DISCARD 事务块或单条命令
+func redisTxUnitEnd(cmds []RedisCmd) func(int) int {
+	return func(start int) int {
+		if !isRedisCommand(cmds[start], "MULTI") {
+			return start + 1
+		}
+		for i := start + 1; i < len(cmds); i++ {
+			if isRedisCommand(cmds[i], "EXEC") || isRedisCommand(cmds[i], "DISCARD") {
+				return i + 1
+			}
+		}
+		return len(cmds)
+	}
+}
+
+// redisRowUnitEnd 不可拆分单元为来源于同一行的连续命令（CompositeRedisDriver 的事务块位于单行之内）
+func redisRowUnitEnd(rowOf []int) func(int) int {
+	return func(start int) int {
+		end := start + 1
+		for end < len(rowOf) && rowOf[end] == rowOf[start] {
+			end++
+		}
+		return end
+	}
+}
+
+func isRedisCommand(cmd RedisCmd, name string) bool {
+	if len(cmd) == 0 {
+		return false
+	}
+	s, ok := cmd[0].(string)
+	return ok && strings.EqualFold(s, name)
+}
+
+// execPipeline 以单个 Pipeline 执行一组命令：Exec 失败时返回其错误，否则聚合各命令的错误
+func (rp *RedisBatchProcessor) execPipeline(ctx context.Context, cmds []RedisCmd) error {
+	pipeline := rp.client.Pipeline()
+	for _, cmd := range cmds {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
-		if cmd, ok := operation.(RedisCmd); ok {
-			pipeline.Do(ctx, cmd...)
-		}
+		pipeline.Do(ctx, cmd...)
 	}
 
 	// 执行Pipeline
-	cmds, err := pipeline.Exec(ctx)
+	results, err := pipeline.Exec(ctx)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			if cause := context.Cause(ctx); cause != nil {
@@ -268,7 +524,7 @@ func (rp *RedisBatchProcessor) ExecuteOperations(ctx context.Context, operations
 	}
 
 	// 检查每个命令的执行结果
-	for _, cmd := range cmds {
+	for _, cmd := range results {
 		if cmd.Err() != nil {
 			err = errors.Join(err, cmd.Err())
 		}
@@ -279,18 +535,16 @@ func (rp *RedisBatchProcessor) ExecuteOperations(ctx context.Context, operations
 
 func redisOperationArgCount(operations Operations) int {
 	count := 0
-	for _, operation := range operations {
-		if cmd, ok := operation.(RedisCmd); ok {
-			count += len(cmd)
-		}
+	for _, cmd := range redisOperationCmds(operations) {
+		count += len(cmd)
 	}
 	return count
 }
 
 func redisOperationsFingerprint(schema SchemaInterface, operations Operations) string {
 	parts := []string{BackendRedis, schema.Name()}
-	for _, operation := range operations {
-		if cmd, ok := operation.(RedisCmd); ok && len(cmd) > 0 {
+	for _, cmd := range redisOperationCmds(operations) {
+		if len(cmd) > 0 {
 			parts = append(parts, fmt.Sprint(cmd[0]))
 		}
 	}
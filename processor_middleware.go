@@ -0,0 +1,61 @@
+package batchflow
+
+import (
+	"context"
+	"time"
+)
+
+// BatchProcessorMiddleware 包装 BatchProcessor，用于叠加指标、日志、加密等横切逻辑
+type BatchProcessorMiddleware func(BatchProcessor) BatchProcessor
+
+// Chain 将中间件按顺序包装在 base 之外：Chain(base, a, b) 等价于 a(b(base))，
+// 即 a 最外层、最先执行。nil 中间件会被跳过。
+//
+// 注意：包装后的处理器不再暴露 base 的可选扩展接口（如 OperationPreviewer），
+// 执行器将回退到 GenerateOperations 路径生成通用预览信息。
+func Chain(base BatchProcessor, middleware ...BatchProcessorMiddleware) BatchProcessor {
+	processor := base
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			processor = middleware[i](processor)
+		}
+	}
+	return processor
+}
+
+// TimingMiddleware 观察到的处理阶段
+const (
+	ProcessorStageGenerate = "generate"
+	ProcessorStageExecute  = "execute"
+)
+
+// TimingMiddleware 示例中间件：记录 GenerateOperations / ExecuteOperations 的耗时与结果。
+// observe 的 stage 取值为 ProcessorStageGenerate 或 ProcessorStageExecute。
+func TimingMiddleware(observe func(stage string, d time.Duration, err error)) BatchProcessorMiddleware {
+	return func(next BatchProcessor) BatchProcessor {
+		return &timingProcessor{next: next, observe: observe}
+	}
+}
+
+type timingProcessor struct {
+	next    BatchProcessor
+	observe func(stage string, d time.Duration, err error)
+}
+
+func (p *timingProcessor) GenerateOperations(ctx context.Context, schema SchemaInterface, data []map[string]any) (Operations, error) {
+	start := time.Now()
+	ops, err := p.next.GenerateOperations(ctx, schema, data)
+	if p.observe != nil {
+		p.observe(ProcessorStageGenerate, time.Since(start), err)
+	}
+	return ops, err
+}
+
+func (p *timingProcessor) ExecuteOperations(ctx context.Context, operations Operations) error {
+	start := time.Now()
+	err := p.next.ExecuteOperations(ctx, operations)
+	if p.observe != nil {
+		p.observe(ProcessorStageExecute, time.Since(start), err)
+	}
+	return err
+}
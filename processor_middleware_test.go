@@ -0,0 +1,96 @@
+package batchflow_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type traceProcessor struct {
+	name  string
+	next  batchflow.BatchProcessor
+	trace *[]string
+}
+
+func (p *traceProcessor) GenerateOperations(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) (batchflow.Operations, error) {
+	*p.trace = append(*p.trace, p.name+":generate")
+	return p.next.GenerateOperations(ctx, schema, data)
+}
+
+func (p *traceProcessor) ExecuteOperations(ctx context.Context, ops batchflow.Operations) error {
+	*p.trace = append(*p.trace, p.name+":execute")
+	return p.next.ExecuteOperations(ctx, ops)
+}
+
+type countingProcessor struct {
+	trace *[]string
+}
+
+func (p *countingProcessor) GenerateOperations(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) (batchflow.Operations, error) {
+	*p.trace = append(*p.trace, "base:generate")
+	return batchflow.Operations{"ok"}, nil
+}
+
+func (p *countingProcessor) ExecuteOperations(ctx context.Context, ops batchflow.Operations) error {
+	*p.trace = append(*p.trace, "base:execute")
+	return nil
+}
+
+func tracing(name string, trace *[]string) batchflow.BatchProcessorMiddleware {
+	return func(next batchflow.BatchProcessor) batchflow.BatchProcessor {
+		return &traceProcessor{name: name, next: next, trace: trace}
+	}
+}
+
+func TestChain_MiddlewareOrder(t *testing.T) {
+	var trace []string
+	processor := batchflow.Chain(&countingProcessor{trace: &trace},
+		tracing("outer", &trace),
+		nil,
+		tracing("inner", &trace),
+	)
+
+	exec := batchflow.NewThrottledBatchExecutor(processor)
+	if err := exec.ExecuteBatch(context.Background(), batchflow.NewSchema("events", "id"), []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+
+	want := []string{
+		"outer:generate", "inner:generate", "base:generate",
+		"outer:execute", "inner:execute", "base:execute",
+	}
+	if !reflect.DeepEqual(trace, want) {
+		t.Fatalf("trace=%v, want %v", trace, want)
+	}
+}
+
+func TestTimingMiddleware_ObservesStages(t *testing.T) {
+	var mu sync.Mutex
+	stages := map[string]int{}
+	var trace []string
+	processor := batchflow.Chain(&countingProcessor{trace: &trace},
+		batchflow.TimingMiddleware(func(stage string, d time.Duration, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil || d < 0 {
+				t.Errorf("unexpected observation stage=%s d=%v err=%v", stage, d, err)
+			}
+			stages[stage]++
+		}),
+	)
+
+	exec := batchflow.NewThrottledBatchExecutor(processor)
+	if err := exec.ExecuteBatch(context.Background(), batchflow.NewSchema("events", "id"), []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if stages[batchflow.ProcessorStageGenerate] != 1 || stages[batchflow.ProcessorStageExecute] != 1 {
+		t.Fatalf("unexpected stage observations: %v", stages)
+	}
+	if len(trace) != 2 {
+		t.Fatalf("expected base processor to run, trace=%v", trace)
+	}
+}
@@ -0,0 +1,75 @@
+package batchflow
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter 限速器：Wait 阻塞直到取得一个令牌或 ctx 结束。
+// golang.org/x/time/rate 的 *rate.Limiter 直接满足该接口，核心包因此无需引入该依赖。
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RateLimitedExecutor 在每次 ExecuteBatch 前向限速器申请令牌，用于在整个进程内限制批次速率（如每秒 N 批），
+// 保护共享数据库。多个 BatchFlow 共用同一个限速器即共享同一全局配额。
+// 等待时长通过内部执行器 reporter 的 ConcurrencyMetricsReporter.ObserveConcurrencyWait 上报。
+type RateLimitedExecutor struct {
+	inner   BatchExecutor
+	limiter RateLimiter
+}
+
+var (
+	_ BatchExecutor           = (*RateLimitedExecutor)(nil)
+	_ ConcurrencyLimitUpdater = (*RateLimitedExecutor)(nil)
+)
+
+// NewRateLimitedExecutor 创建限速执行器；limiter 为 nil 时不限速，直接透传
+func NewRateLimitedExecutor(inner BatchExecutor, limiter RateLimiter) *RateLimitedExecutor {
+	return &RateLimitedExecutor{inner: inner, limiter: limiter}
+}
+
+// ExecuteBatch 取得令牌后委托给内部执行器；等待期间 ctx 结束时返回其错误，批次不执行
+func (e *RateLimitedExecutor) ExecuteBatch(ctx context.Context, schema SchemaInterface, data []map[string]any) error {
+	if e.limiter != nil {
+		waitStart := time.Now()
+		if err := e.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		if cmr, ok := e.MetricsReporter().(ConcurrencyMetricsReporter); ok && cmr != nil {
+			cmr.ObserveConcurrencyWait(time.Since(waitStart))
+		}
+	}
+	return e.inner.ExecuteBatch(ctx, schema, data)
+}
+
+// MetricsReporter 返回内部执行器的 reporter（供 BatchFlow 探测）；内部执行器未暴露时为 nil
+func (e *RateLimitedExecutor) MetricsReporter() MetricsReporter {
+	return executorMetricsReporter(e.inner)
+}
+
+// Logger 返回内部执行器的 Logger（供 BatchFlow 探测）；内部执行器未暴露时为 nil
+func (e *RateLimitedExecutor) Logger() Logger {
+	return executorLogger(e.inner)
+}
+
+// ConcurrencyLimit 返回内部执行器的并发上限；内部执行器未暴露时为 0
+func (e *RateLimitedExecutor) ConcurrencyLimit() int {
+	if cl, ok := e.inner.(interface{ ConcurrencyLimit() int }); ok {
+		return cl.ConcurrencyLimit()
+	}
+	return 0
+}
+
+// UpdateConcurrencyLimit 转发给内部执行器；内部执行器未实现 ConcurrencyLimitUpdater 时忽略
+// （BatchFlow.UpdateConfig 会在修改前拒绝此类执行器）
+func (e *RateLimitedExecutor) UpdateConcurrencyLimit(limit int) {
+	if u, ok := e.inner.(ConcurrencyLimitUpdater); ok {
+		u.UpdateConcurrencyLimit(limit)
+	}
+}
+
+func (e *RateLimitedExecutor) forwardsConcurrencyLimit() bool {
+	_, ok := e.inner.(ConcurrencyLimitUpdater)
+	return ok
+}
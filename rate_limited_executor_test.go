@@ -0,0 +1,193 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// intervalLimiter 每隔 interval 放行一个令牌，行为等价于 rate.NewLimiter(rate.Every(interval), 1)
+type intervalLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type timestampProcessor struct {
+	mu    sync.Mutex
+	times []time.Time
+}
+
+func (p *timestampProcessor) GenerateOperations(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) (batchflow.Operations, error) {
+	return batchflow.Operations{"ok"}, nil
+}
+
+func (p *timestampProcessor) ExecuteOperations(ctx context.Context, ops batchflow.Operations) error {
+	p.mu.Lock()
+	p.times = append(p.times, time.Now())
+	p.mu.Unlock()
+	return nil
+}
+
+func TestRateLimitedExecutor_SpacesBatches(t *testing.T) {
+	const interval = 40 * time.Millisecond
+	proc := &timestampProcessor{}
+	reporter := &concurrencyWaitMetrics{}
+	exec := batchflow.NewRateLimitedExecutor(
+		batchflow.NewThrottledBatchExecutor(proc).WithMetricsReporter(reporter),
+		&intervalLimiter{interval: interval},
+	)
+
+	schema := batchflow.NewSchema("events", "id")
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": i}}); err != nil {
+				t.Errorf("ExecuteBatch failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	if len(proc.times) != 3 {
+		t.Fatalf("expected 3 executed batches, got %d", len(proc.times))
+	}
+	for i := 1; i < len(proc.times); i++ {
+		// 执行时间戳与令牌发放之间存在调度抖动，留少量余量
+		if gap := proc.times[i].Sub(proc.times[i-1]); gap < interval-5*time.Millisecond {
+			t.Fatalf("batches %d and %d only %v apart, want >= %v", i-1, i, gap, interval)
+		}
+	}
+
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if len(reporter.waits) != 3 {
+		t.Fatalf("expected 3 wait observations, got %d", len(reporter.waits))
+	}
+	longest := max(reporter.waits[0], reporter.waits[1], reporter.waits[2])
+	if longest < interval {
+		t.Fatalf("expected a batch to wait at least %v for a token, waits=%v", interval, reporter.waits)
+	}
+}
+
+func TestRateLimitedExecutor_ContextCancelled(t *testing.T) {
+	proc := &timestampProcessor{}
+	limiter := &intervalLimiter{interval: time.Hour}
+	exec := batchflow.NewRateLimitedExecutor(batchflow.NewThrottledBatchExecutor(proc), limiter)
+	schema := batchflow.NewSchema("events", "id")
+
+	// 第一个令牌立即可用，第二个需要等待一小时
+	if err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("first ExecuteBatch failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := exec.ExecuteBatch(ctx, schema, []map[string]any{{"id": 2}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	if len(proc.times) != 1 {
+		t.Fatalf("expected the cancelled batch not to execute, got %d executions", len(proc.times))
+	}
+}
+
+func TestRateLimitedExecutor_ForwardsMetricsReporter(t *testing.T) {
+	reporter := &concurrencyWaitMetrics{}
+	exec := batchflow.NewRateLimitedExecutor(
+		batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter), nil)
+	if exec.MetricsReporter() != batchflow.MetricsReporter(reporter) {
+		t.Fatalf("expected the inner reporter to be forwarded")
+	}
+	if err := exec.ExecuteBatch(context.Background(), batchflow.NewSchema("events", "id"), []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if len(reporter.waits) != 0 {
+		t.Fatalf("expected no wait observations without a limiter, got %v", reporter.waits)
+	}
+}
+
+func TestRateLimitedExecutor_ForwardsConcurrencyLimitUpdates(t *testing.T) {
+	processor := &gateProcessor{release: make(chan struct{})}
+	ctx := context.Background()
+	exec := batchflow.NewRateLimitedExecutor(batchflow.NewThrottledBatchExecutor(processor).WithConcurrencyLimit(1), nil)
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{
+			BufferSize:           10,
+			FlushSize:            1,
+			FlushInterval:        time.Hour,
+			MaxConcurrentFlushes: 4,
+		},
+		Executor: exec,
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	var releaseOnce sync.Once
+	release := func() { releaseOnce.Do(func() { close(processor.release) }) }
+	defer func() {
+		release()
+		b.Close()
+	}()
+
+	schema := batchflow.NewSQLSchema("jobs", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 3; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	waitForRunning(t, processor, 1)
+
+	limit := 3
+	if err := b.UpdateConfig(batchflow.PipelineConfigPatch{ConcurrencyLimit: &limit}); err != nil {
+		t.Fatalf("update config failed: %v", err)
+	}
+	if got := exec.ConcurrencyLimit(); got != 3 {
+		t.Fatalf("expected the inner limit to be updated to 3, got %d", got)
+	}
+	waitForRunning(t, processor, 3)
+}
+
+func TestRateLimitedExecutor_RejectsConcurrencyUpdateWithoutInnerLimiter(t *testing.T) {
+	b := batchflow.NewBatchFlow(context.Background(), 10, 5, time.Hour,
+		batchflow.NewRateLimitedExecutor(batchflow.NewMockExecutor(), nil))
+	defer b.Close()
+
+	limit := 2
+	err := b.UpdateConfig(batchflow.PipelineConfigPatch{ConcurrencyLimit: &limit})
+	var cfgErr *batchflow.ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "ConcurrencyLimit" {
+		t.Fatalf("expected ConfigError for ConcurrencyLimit, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package batchflow
+
+import (
+	"sync"
+	"time"
+)
+
+// ErrorRecord 一条 flush 失败记录
+type ErrorRecord struct {
+	Time       time.Time
+	SchemaName string // 失败时正在处理的 schema；暂停等待等与具体 schema 无关的错误为空
+	Err        error
+}
+
+// recentErrors 固定容量的环形缓冲，保存最近的 flush 错误
+type recentErrors struct {
+	mu      sync.Mutex
+	records []ErrorRecord
+	next    int
+	full    bool
+}
+
+func newRecentErrors(size int) *recentErrors {
+	if size <= 0 {
+		return nil
+	}
+	return &recentErrors{records: make([]ErrorRecord, size)}
+}
+
+func (r *recentErrors) add(schemaName string, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.records[r.next] = ErrorRecord{Time: time.Now(), SchemaName: schemaName, Err: err}
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+}
+
+func (r *recentErrors) snapshot() []ErrorRecord {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]ErrorRecord(nil), r.records[:r.next]...)
+	}
+	out := make([]ErrorRecord, 0, len(r.records))
+	out = append(out, r.records[r.next:]...)
+	return append(out, r.records[:r.next]...)
+}
+
+// RecentErrors 返回最近的 flush 错误（由旧到新），最多 PipelineConfig.RecentErrorsSize 条，
+// 适合在 /debug 等诊断端点展示，无需接入指标。记录来自投递到错误通道的同一路径，
+// 不受 ErrorAggregationWindow 聚合影响；未配置 RecentErrorsSize 时返回 nil。
+func (b *BatchFlow) RecentErrors() []ErrorRecord {
+	return b.recentErrs.snapshot()
+}
@@ -0,0 +1,18 @@
+package batchflow
+
+import "context"
+
+// RedisCmdResultFunc 接收单条 Redis 命令的执行结果及其来源行。
+// val 为命令回复（如 RPUSH 返回的列表长度、SETNX 返回的 0/1），err 为该命令的错误（redis.Nil 表示空回复）。
+// ctx 携带批次信息，可用 RowTags(ctx, row) 取得该行标签；row 仅在回调期间有效，如需异步处理请自行拷贝。
+type RedisCmdResultFunc func(ctx context.Context, schema SchemaInterface, row map[string]any, cmd RedisCmd, val any, err error)
+
+// WithCmdResult 注册命令结果回调：批次的全部 Pipeline 执行完成后，按命令顺序对每条命令调用一次 fn，
+// 用于获取 Redis 回复值（如 SETNX 是否成功、去重结果）。一行生成多条命令时该行会收到多次回调。
+// 命令到行的映射与逐行死信模式相同（见 RedisRowMappedDriver）。
+// 未启用 WithRowDeadLetter 时，服务端命令错误仍使整批失败，此时不回调；批次失败（连接中断、超时）同样不回调，
+// 重试成功后才回调，因此同一批次最多回调一轮。fn 为 nil 时关闭。
+func (rp *RedisBatchProcessor) WithCmdResult(fn RedisCmdResultFunc) *RedisBatchProcessor {
+	rp.onResult = fn
+	return rp
+}
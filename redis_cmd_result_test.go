@@ -0,0 +1,172 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rushairer/batchflow/v2"
+)
+
+// setnxHook 不访问网络地模拟 SETNX：key 已存在返回 0，否则写入并返回 1；failKey 中的 key 返回指定错误
+type setnxHook struct {
+	existing map[string]bool
+	failKey  map[string]error
+}
+
+func (h setnxHook) DialHook(redis.DialHook) redis.DialHook {
+	return func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("dial disabled in test")
+	}
+}
+
+func (h setnxHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h setnxHook) ProcessPipelineHook(redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(_ context.Context, cmds []redis.Cmder) error {
+		for _, cmder := range cmds {
+			key := fmt.Sprint(cmder.Args()[1])
+			if err := h.failKey[key]; err != nil {
+				cmder.SetErr(err)
+				continue
+			}
+			cmd := cmder.(*redis.Cmd)
+			if !strings.EqualFold(cmder.Name(), "setnx") {
+				cmd.SetVal("OK")
+				continue
+			}
+			if h.existing[key] {
+				cmd.SetVal(int64(0))
+				continue
+			}
+			h.existing[key] = true
+			cmd.SetVal(int64(1))
+		}
+		return nil
+	}
+}
+
+type cmdResult struct {
+	key string
+	val any
+	err error
+}
+
+func newCmdResultProcessor(t *testing.T, hook setnxHook, results *[]cmdResult) *batchflow.RedisBatchProcessor {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	t.Cleanup(func() { _ = client.Close() })
+	client.AddHook(hook)
+	return batchflow.NewRedisBatchProcessor(client, batchflow.NewRedisPipelineDriver()).
+		WithCmdResult(func(_ context.Context, _ batchflow.SchemaInterface, row map[string]any, _ batchflow.RedisCmd, val any, err error) {
+			*results = append(*results, cmdResult{key: fmt.Sprint(row["key"]), val: val, err: err})
+		})
+}
+
+func TestRedisBatchProcessor_CmdResultReportsSetNXPerRow(t *testing.T) {
+	var results []cmdResult
+	processor := newCmdResultProcessor(t, setnxHook{existing: map[string]bool{"dup": true}}, &results)
+	executor := batchflow.NewThrottledBatchExecutor(processor)
+
+	schema := batchflow.NewSchema("dedup", "cmd", "key", "value")
+	data := []map[string]any{
+		{"cmd": "SETNX", "key": "a", "value": 1},
+		{"cmd": "SETNX", "key": "dup", "value": 2},
+		{"cmd": "SETNX", "key": "a", "value": 3},
+		{"cmd": "SETNX", "key": "b", "value": 4},
+	}
+	if err := executor.ExecuteBatch(context.Background(), schema, data); err != nil {
+		t.Fatalf("ExecuteBatch: %v", err)
+	}
+
+	want := []cmdResult{
+		{key: "a", val: int64(1)},
+		{key: "dup", val: int64(0)},
+		{key: "a", val: int64(0)},
+		{key: "b", val: int64(1)},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %v", len(want), results)
+	}
+	for i, w := range want {
+		if results[i].key != w.key || results[i].val != w.val || results[i].err != nil {
+			t.Fatalf("result %d: expected %+v, got %+v", i, w, results[i])
+		}
+	}
+}
+
+func TestRedisBatchProcessor_CmdResultWithChunkedPipelines(t *testing.T) {
+	var results []cmdResult
+	processor := newCmdResultProcessor(t, setnxHook{existing: map[string]bool{}}, &results).
+		WithPipelineChunkSize(2)
+	executor := batchflow.NewThrottledBatchExecutor(processor)
+
+	schema := batchflow.NewSchema("dedup", "cmd", "key", "value")
+	data := []map[string]any{
+		{"cmd": "SETNX", "key": "k0", "value": 0},
+		{"cmd": "SETNX", "key": "k1", "value": 1},
+		{"cmd": "SETNX", "key": "k0", "value": 2},
+	}
+	if err := executor.ExecuteBatch(context.Background(), schema, data); err != nil {
+		t.Fatalf("ExecuteBatch: %v", err)
+	}
+	if len(results) != 3 || results[2].key != "k0" || results[2].val != int64(0) {
+		t.Fatalf("expected per-row results across chunks, got %v", results)
+	}
+}
+
+func TestRedisBatchProcessor_CmdResultCommandErrorFailsBatchWithoutDeadLetter(t *testing.T) {
+	var results []cmdResult
+	wrongType := fakeRedisError("WRONGTYPE Operation against a key holding the wrong kind of value")
+	processor := newCmdResultProcessor(t, setnxHook{
+		existing: map[string]bool{},
+		failKey:  map[string]error{"bad": wrongType},
+	}, &results)
+	executor := batchflow.NewThrottledBatchExecutor(processor)
+
+	schema := batchflow.NewSchema("dedup", "cmd", "key", "value")
+	data := []map[string]any{
+		{"cmd": "SETNX", "key": "ok", "value": 0},
+		{"cmd": "SETNX", "key": "bad", "value": 1},
+	}
+	err := executor.ExecuteBatch(context.Background(), schema, data)
+	if !errors.Is(err, wrongType) {
+		t.Fatalf("expected command error to fail the batch, got %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no result callbacks for failed batch, got %v", results)
+	}
+}
+
+func TestRedisBatchProcessor_CmdResultWithRowDeadLetter(t *testing.T) {
+	var results []cmdResult
+	var dead []string
+	wrongType := fakeRedisError("WRONGTYPE Operation against a key holding the wrong kind of value")
+	processor := newCmdResultProcessor(t, setnxHook{
+		existing: map[string]bool{},
+		failKey:  map[string]error{"bad": wrongType},
+	}, &results).
+		WithRowDeadLetter(func(_ batchflow.SchemaInterface, row map[string]any, _ error) {
+			dead = append(dead, fmt.Sprint(row["key"]))
+		})
+	executor := batchflow.NewThrottledBatchExecutor(processor)
+
+	schema := batchflow.NewSchema("dedup", "cmd", "key", "value")
+	data := []map[string]any{
+		{"cmd": "SETNX", "key": "ok", "value": 0},
+		{"cmd": "SETNX", "key": "bad", "value": 1},
+	}
+	if err := executor.ExecuteBatch(context.Background(), schema, data); err != nil {
+		t.Fatalf("ExecuteBatch: %v", err)
+	}
+	if len(dead) != 1 || dead[0] != "bad" {
+		t.Fatalf("expected row bad dead-lettered, got %v", dead)
+	}
+	if len(results) != 2 || results[0].val != int64(1) || !errors.Is(results[1].err, wrongType) {
+		t.Fatalf("expected results for both rows with command error on bad, got %v", results)
+	}
+}
@@ -0,0 +1,120 @@
+package batchflow
+
+import (
+	"context"
+	"fmt"
+)
+
+// CompositeRedisDriver 组合多个 RedisDriver：对每一行，按子驱动顺序依次拼接各子驱动为该行生成的命令，
+// 如先 SET 数据再 SADD 索引。启用 WithAtomic 后每行的命令包裹在 MULTI/EXEC 中，保证同一行的写入原子生效。
+// 子驱动实现 RedisRowMappedDriver 时按其映射归并到行（可一行多条或跳过行）；否则须为每行恰好生成一条命令。
+// 组合驱动自身实现 RedisRowMappedDriver，可与 WithRowDeadLetter、WithCmdResult 配合使用。
+type CompositeRedisDriver struct {
+	drivers []RedisDriver
+	atomic  bool
+}
+
+var (
+	_ RedisDriver          = (*CompositeRedisDriver)(nil)
+	_ RedisRowMappedDriver = (*CompositeRedisDriver)(nil)
+)
+
+// NewCompositeRedisDriver 创建组合驱动，drivers 的顺序即每行内命令的顺序；nil 子驱动被忽略
+func NewCompositeRedisDriver(drivers ...RedisDriver) *CompositeRedisDriver {
+	d := &CompositeRedisDriver{drivers: make([]RedisDriver, 0, len(drivers))}
+	for _, driver := range drivers {
+		if driver != nil {
+			d.drivers = append(d.drivers, driver)
+		}
+	}
+	return d
+}
+
+// WithAtomic 设置是否将每行的命令包裹在 MULTI/EXEC 中。
+// 注意：事务内命令的执行错误（如 WRONGTYPE）由 EXEC 的回复携带，不会作为该条命令的错误返回，
+// 因此不会触发逐行死信；MULTI/EXEC 不提供回滚，Redis Cluster 下同一行的 key 须位于同一 slot。
+func (d *CompositeRedisDriver) WithAtomic(atomic bool) *CompositeRedisDriver {
+	d.atomic = atomic
+	return d
+}
+
+func (d *CompositeRedisDriver) GenerateCmds(ctx context.Context, schema SchemaInterface, data []map[string]any) ([]RedisCmd, error) {
+	cmds, _, err := d.GenerateRowCmds(ctx, schema, data)
+	return cmds, err
+}
+
+// GenerateRowCmds 生成按行归并后的命令及其来源行下标
+func (d *CompositeRedisDriver) GenerateRowCmds(ctx context.Context, schema SchemaInterface, data []map[string]any) ([]RedisCmd, []int, error) {
+	if len(d.drivers) == 0 {
+		return nil, nil, fmt.Errorf("composite redis driver has no sub-drivers")
+	}
+
+	perRow := make([][]RedisCmd, len(data))
+	total := 0
+	for i, driver := range d.drivers {
+		cmds, rows, err := redisDriverRowCmds(ctx, driver, schema, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("composite redis driver %d: %w", i, err)
+		}
+		for j, cmd := range cmds {
+			perRow[rows[j]] = append(perRow[rows[j]], cmd)
+		}
+		total += len(cmds)
+	}
+
+	if d.atomic {
+		total += 2 * len(data)
+	}
+	cmds := make([]RedisCmd, 0, total)
+	rowOf := make([]int, 0, total)
+	for row, rowCmds := range perRow {
+		if len(rowCmds) == 0 {
+			continue
+		}
+		if d.atomic {
+			cmds = append(cmds, RedisCmd{"MULTI"})
+			rowOf = append(rowOf, row)
+		}
+		for _, cmd := range rowCmds {
+			cmds = append(cmds, cmd)
+			rowOf = append(rowOf, row)
+		}
+		if d.atomic {
+			cmds = append(cmds, RedisCmd{"EXEC"})
+			rowOf = append(rowOf, row)
+		}
+	}
+	return cmds, rowOf, nil
+}
+
+// redisDriverRowCmds 取得驱动生成的命令及来源行；未实现 RedisRowMappedDriver 时要求每行恰好一条命令
+func redisDriverRowCmds(ctx context.Context, driver RedisDriver, schema SchemaInterface, data []map[string]any) ([]RedisCmd, []int, error) {
+	if mapped, ok := driver.(RedisRowMappedDriver); ok {
+		cmds, rows, err := mapped.GenerateRowCmds(ctx, schema, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rows) != len(cmds) {
+			return nil, nil, fmt.Errorf("redis driver %T returned %d row indexes for %d commands", driver, len(rows), len(cmds))
+		}
+		for i, row := range rows {
+			if row < 0 || row >= len(data) {
+				return nil, nil, fmt.Errorf("redis driver %T: command %d maps to row %d out of range", driver, i, row)
+			}
+		}
+		return cmds, rows, nil
+	}
+
+	cmds, err := driver.GenerateCmds(ctx, schema, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(cmds) != len(data) {
+		return nil, nil, fmt.Errorf("redis driver %T generated %d commands for %d rows; implement RedisRowMappedDriver to map commands to rows", driver, len(cmds), len(data))
+	}
+	rows := make([]int, len(cmds))
+	for i := range rows {
+		rows[i] = i
+	}
+	return cmds, rows, nil
+}
@@ -0,0 +1,120 @@
+package batchflow_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// redisSetDriver 每行生成 SET <key> <value>
+type redisSetDriver struct{}
+
+func (redisSetDriver) GenerateCmds(_ context.Context, _ batchflow.SchemaInterface, data []map[string]any) ([]batchflow.RedisCmd, error) {
+	cmds := make([]batchflow.RedisCmd, len(data))
+	for i, row := range data {
+		cmds[i] = batchflow.RedisCmd{"SET", row["key"], row["value"]}
+	}
+	return cmds, nil
+}
+
+// redisIndexDriver 每行生成 SADD idx:<tag> <key>，tag 为空的行不生成命令
+type redisIndexDriver struct{}
+
+func (d redisIndexDriver) GenerateCmds(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) ([]batchflow.RedisCmd, error) {
+	cmds, _, err := d.GenerateRowCmds(ctx, schema, data)
+	return cmds, err
+}
+
+func (redisIndexDriver) GenerateRowCmds(_ context.Context, _ batchflow.SchemaInterface, data []map[string]any) ([]batchflow.RedisCmd, []int, error) {
+	var cmds []batchflow.RedisCmd
+	var rows []int
+	for i, row := range data {
+		if row["tag"] == "" {
+			continue
+		}
+		cmds = append(cmds, batchflow.RedisCmd{"SADD", "idx:" + row["tag"].(string), row["key"]})
+		rows = append(rows, i)
+	}
+	return cmds, rows, nil
+}
+
+func compositeTestData() []map[string]any {
+	return []map[string]any{
+		{"key": "u1", "value": "alice", "tag": "vip"},
+		{"key": "u2", "value": "bob", "tag": ""},
+		{"key": "u3", "value": "carol", "tag": "new"},
+	}
+}
+
+func TestCompositeRedisDriver_ConcatenatesPerRow(t *testing.T) {
+	schema := batchflow.NewSchema("users", "key", "value", "tag")
+	driver := batchflow.NewCompositeRedisDriver(redisSetDriver{}, redisIndexDriver{})
+
+	cmds, rows, err := driver.GenerateRowCmds(context.Background(), schema, compositeTestData())
+	if err != nil {
+		t.Fatalf("GenerateRowCmds: %v", err)
+	}
+	want := []batchflow.RedisCmd{
+		{"SET", "u1", "alice"},
+		{"SADD", "idx:vip", "u1"},
+		{"SET", "u2", "bob"},
+		{"SET", "u3", "carol"},
+		{"SADD", "idx:new", "u3"},
+	}
+	if !reflect.DeepEqual(cmds, want) {
+		t.Fatalf("unexpected commands:\n got %v\nwant %v", cmds, want)
+	}
+	if wantRows := []int{0, 0, 1, 2, 2}; !reflect.DeepEqual(rows, wantRows) {
+		t.Fatalf("unexpected row mapping: got %v want %v", rows, wantRows)
+	}
+
+	plain, err := driver.GenerateCmds(context.Background(), schema, compositeTestData())
+	if err != nil || !reflect.DeepEqual(plain, want) {
+		t.Fatalf("GenerateCmds should match GenerateRowCmds, got %v (%v)", plain, err)
+	}
+}
+
+func TestCompositeRedisDriver_AtomicWrapsEachRow(t *testing.T) {
+	schema := batchflow.NewSchema("users", "key", "value", "tag")
+	driver := batchflow.NewCompositeRedisDriver(redisSetDriver{}, redisIndexDriver{}).WithAtomic(true)
+
+	cmds, rows, err := driver.GenerateRowCmds(context.Background(), schema, compositeTestData()[:1])
+	if err != nil {
+		t.Fatalf("GenerateRowCmds: %v", err)
+	}
+	want := []batchflow.RedisCmd{
+		{"MULTI"},
+		{"SET", "u1", "alice"},
+		{"SADD", "idx:vip", "u1"},
+		{"EXEC"},
+	}
+	if !reflect.DeepEqual(cmds, want) {
+		t.Fatalf("unexpected commands:\n got %v\nwant %v", cmds, want)
+	}
+	if wantRows := []int{0, 0, 0, 0}; !reflect.DeepEqual(rows, wantRows) {
+		t.Fatalf("unexpected row mapping: got %v want %v", rows, wantRows)
+	}
+}
+
+func TestCompositeRedisDriver_RejectsMismatchedSubDriver(t *testing.T) {
+	schema := batchflow.NewSchema("users", "key", "value", "tag")
+	short := batchflow.NewCompositeRedisDriver(redisSetDriver{}, shortRedisDriver{})
+	_, err := short.GenerateCmds(context.Background(), schema, compositeTestData())
+	if err == nil || !strings.Contains(err.Error(), "RedisRowMappedDriver") {
+		t.Fatalf("expected mismatched command count to be rejected, got %v", err)
+	}
+
+	if _, err := batchflow.NewCompositeRedisDriver().GenerateCmds(context.Background(), schema, compositeTestData()); err == nil {
+		t.Fatal("expected empty composite driver to fail")
+	}
+}
+
+// shortRedisDriver 只为首行生成命令且未声明行映射
+type shortRedisDriver struct{}
+
+func (shortRedisDriver) GenerateCmds(_ context.Context, _ batchflow.SchemaInterface, data []map[string]any) ([]batchflow.RedisCmd, error) {
+	return []batchflow.RedisCmd{{"SADD", "idx", data[0]["key"]}}, nil
+}
@@ -0,0 +1,128 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rushairer/batchflow/v2"
+)
+
+// pipelineRecorder 拦截 Pipeline 执行而不访问网络：记录每次 Exec 的命令数与命令参数，并让指定 key 的命令失败
+type pipelineRecorder struct {
+	execs   []int
+	args    [][]any
+	failKey map[string]bool
+}
+
+func (h *pipelineRecorder) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("dial disabled in test")
+	}
+}
+
+func (h *pipelineRecorder) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h *pipelineRecorder) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		h.execs = append(h.execs, len(cmds))
+		for _, cmd := range cmds {
+			h.args = append(h.args, cmd.Args())
+			if args := cmd.Args(); len(args) > 1 && h.failKey[fmt.Sprint(args[1])] {
+				cmd.SetErr(fmt.Errorf("write %s failed", args[1]))
+			}
+		}
+		return nil
+	}
+}
+
+func TestRedisBatchProcessor_PipelineChunkSize(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+	rec := &pipelineRecorder{failKey: map[string]bool{"k1": true, "k4": true}}
+	client.AddHook(rec)
+
+	processor := batchflow.NewRedisBatchProcessor(client, batchflow.NewRedisPipelineDriver()).WithPipelineChunkSize(2)
+	ops := batchflow.Operations{}
+	for i := 0; i < 5; i++ {
+		ops = append(ops, batchflow.RedisCmd{"SET", fmt.Sprintf("k%d", i), i})
+	}
+
+	err := processor.ExecuteOperations(context.Background(), ops)
+	if len(rec.execs) != 3 || rec.execs[0] != 2 || rec.execs[1] != 2 || rec.execs[2] != 1 {
+		t.Fatalf("expected Exec calls of sizes [2 2 1], got %v", rec.execs)
+	}
+	if err == nil {
+		t.Fatal("expected aggregated error")
+	}
+	for _, want := range []string{"write k1 failed", "write k4 failed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in aggregated error, got %v", want, err)
+		}
+	}
+}
+
+func TestRedisBatchProcessor_NoChunkingUsesSinglePipeline(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+	rec := &pipelineRecorder{}
+	client.AddHook(rec)
+
+	processor := batchflow.NewRedisBatchProcessor(client, batchflow.NewRedisPipelineDriver())
+	ops := batchflow.Operations{batchflow.RedisCmd{"SET", "a", 1}, batchflow.RedisCmd{"SET", "b", 2}, batchflow.RedisCmd{"SET", "c", 3}}
+	if err := processor.ExecuteOperations(context.Background(), ops); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rec.execs) != 1 || rec.execs[0] != 3 {
+		t.Fatalf("expected one Exec with 3 commands, got %v", rec.execs)
+	}
+}
+
+func TestRedisBatchProcessor_PipelineChunkSizeKeepsAtomicRowsTogether(t *testing.T) {
+	schema := batchflow.NewSchema("users", "key", "value", "tag")
+	driver := batchflow.NewCompositeRedisDriver(redisSetDriver{}, redisIndexDriver{}).WithAtomic(true)
+	data := []map[string]any{
+		{"key": "u1", "value": "alice", "tag": "vip"},
+		{"key": "u2", "value": "bob", "tag": "new"},
+	}
+	deadLetter := func(batchflow.SchemaInterface, map[string]any, error) {}
+
+	for _, tc := range []struct {
+		name      string
+		configure func(*batchflow.RedisBatchProcessor) *batchflow.RedisBatchProcessor
+	}{
+		{"plain", func(p *batchflow.RedisBatchProcessor) *batchflow.RedisBatchProcessor { return p }},
+		{"row dead letter", func(p *batchflow.RedisBatchProcessor) *batchflow.RedisBatchProcessor {
+			return p.WithRowDeadLetter(deadLetter)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+			defer client.Close()
+			rec := &pipelineRecorder{}
+			client.AddHook(rec)
+
+			processor := tc.configure(batchflow.NewRedisBatchProcessor(client, driver).WithPipelineChunkSize(2))
+			ops, err := processor.GenerateOperations(context.Background(), schema, data)
+			if err != nil {
+				t.Fatalf("GenerateOperations: %v", err)
+			}
+			if err := processor.ExecuteOperations(context.Background(), ops); err != nil {
+				t.Fatalf("ExecuteOperations: %v", err)
+			}
+			// 每行 MULTI + 2 条命令 + EXEC 超过 chunk 大小，仍须在同一个 Pipeline 内
+			if len(rec.execs) != 2 || rec.execs[0] != 4 || rec.execs[1] != 4 {
+				t.Fatalf("expected one Exec per atomic row of 4 commands, got %v", rec.execs)
+			}
+			for i := 0; i < len(rec.args); i += 4 {
+				if rec.args[i][0] != "multi" && rec.args[i][0] != "MULTI" || rec.args[i+3][0] != "exec" && rec.args[i+3][0] != "EXEC" {
+					t.Fatalf("pipeline %d does not wrap a whole transaction: %v", i/4, rec.args[i:i+4])
+				}
+			}
+		})
+	}
+}
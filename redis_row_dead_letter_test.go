@@ -0,0 +1,102 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rushairer/batchflow/v2"
+)
+
+// fakeRedisError 模拟服务端返回的命令错误（实现 redis.Error）
+type fakeRedisError string
+
+func (e fakeRedisError) Error() string { return string(e) }
+func (fakeRedisError) RedisError()     {}
+
+// failingKeyHook 不访问网络地执行 Pipeline，按 key 为命令设置指定错误
+type failingKeyHook struct {
+	failKey map[string]error
+}
+
+func (h failingKeyHook) DialHook(redis.DialHook) redis.DialHook {
+	return func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("dial disabled in test")
+	}
+}
+
+func (h failingKeyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h failingKeyHook) ProcessPipelineHook(redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(_ context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if err := h.failKey[fmt.Sprint(cmd.Args()[1])]; err != nil {
+				cmd.SetErr(err)
+			}
+		}
+		return nil
+	}
+}
+
+type deadLetteredRow struct {
+	row map[string]any
+	err error
+}
+
+func newDeadLetterRedisExecutor(t *testing.T, failKey map[string]error, dead *[]deadLetteredRow) *batchflow.ThrottledBatchExecutor {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	t.Cleanup(func() { _ = client.Close() })
+	client.AddHook(failingKeyHook{failKey: failKey})
+	processor := batchflow.NewRedisBatchProcessor(client, batchflow.NewRedisPipelineDriver()).
+		WithRowDeadLetter(func(_ batchflow.SchemaInterface, row map[string]any, err error) {
+			*dead = append(*dead, deadLetteredRow{row: map[string]any{"key": row["key"]}, err: err})
+		})
+	return batchflow.NewThrottledBatchExecutor(processor)
+}
+
+func TestRedisBatchProcessor_RowDeadLetterIsolatesFailedCommand(t *testing.T) {
+	var dead []deadLetteredRow
+	executor := newDeadLetterRedisExecutor(t, map[string]error{
+		"k1": fakeRedisError("WRONGTYPE Operation against a key holding the wrong kind of value"),
+	}, &dead)
+
+	schema := batchflow.NewSchema("cache", "cmd", "key", "value")
+	data := []map[string]any{
+		{"cmd": "SET", "key": "k0", "value": 0},
+		{"cmd": "SET", "key": "k1", "value": 1},
+		{"cmd": "SET", "key": "k2", "value": 2},
+	}
+	if err := executor.ExecuteBatch(context.Background(), schema, data); err != nil {
+		t.Fatalf("expected batch to succeed with row-level failure, got %v", err)
+	}
+	if len(dead) != 1 || dead[0].row["key"] != "k1" {
+		t.Fatalf("expected only row k1 dead-lettered, got %v", dead)
+	}
+	var redisErr redis.Error
+	if !errors.As(dead[0].err, &redisErr) {
+		t.Fatalf("expected the command error to be delivered, got %v", dead[0].err)
+	}
+}
+
+func TestRedisBatchProcessor_RowDeadLetterFailsBatchOnTransportError(t *testing.T) {
+	var dead []deadLetteredRow
+	executor := newDeadLetterRedisExecutor(t, map[string]error{
+		"k0": errors.New("connection reset"),
+	}, &dead)
+
+	schema := batchflow.NewSchema("cache", "cmd", "key", "value")
+	data := []map[string]any{
+		{"cmd": "SET", "key": "k0", "value": 0},
+		{"cmd": "SET", "key": "k1", "value": 1},
+	}
+	if err := executor.ExecuteBatch(context.Background(), schema, data); err == nil {
+		t.Fatal("expected transport error to fail the batch")
+	}
+	if len(dead) != 0 {
+		t.Fatalf("expected no dead letters on batch failure, got %v", dead)
+	}
+}
@@ -0,0 +1,140 @@
+package batchflow
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRowMappedDriver 可选扩展接口：驱动在生成命令的同时报告每条命令来源的行下标（rows[i] 为 cmds[i] 对应的 data 下标），
+// 一行可对应多条命令。逐行死信模式（WithRowDeadLetter）据此把命令错误映射回行；
+// 未实现时要求驱动为每行按顺序生成恰好一条命令（内置驱动均满足）。
+type RedisRowMappedDriver interface {
+	GenerateRowCmds(ctx context.Context, schema SchemaInterface, data []map[string]any) (cmds []RedisCmd, rows []int, err error)
+}
+
+// redisRowOperations 逐行死信模式下 GenerateOperations 产出的唯一操作
+type redisRowOperations struct {
+	schema SchemaInterface
+	cmds   []RedisCmd
+	rowOf  []int // cmds[i] 来源行在 data 中的下标
+	data   []map[string]any
+}
+
+// WithRowDeadLetter 启用逐行死信模式：Redis 服务端拒绝的命令（redis.Error，如 WRONGTYPE）不再使整批失败，
+// 所有 Pipeline 执行完成后将对应的源行逐一交给 deadLetter（一行的多条命令失败时只投递一次，错误合并）。
+// 网络、超时等非命令级错误仍按整批失败返回并参与重试，此时不投递死信；redis.Nil 回复视为成功。
+// deadLetter 为 nil 时关闭。
+func (rp *RedisBatchProcessor) WithRowDeadLetter(deadLetter DeadLetterFunc) *RedisBatchProcessor {
+	if deadLetter == nil {
+		rp.deadLetter = nil
+		return rp
+	}
+	rp.deadLetter = func(schema SchemaInterface, row map[string]any, _ map[string]string, err error) {
+		deadLetter(schema, row, err)
+	}
+	return rp
+}
+
+// WithRowDeadLetterTagged 与 WithRowDeadLetter 相同，死信回调额外收到失败行对应请求的标签。deadLetter 为 nil 时关闭。
+func (rp *RedisBatchProcessor) WithRowDeadLetterTagged(deadLetter TaggedDeadLetterFunc) *RedisBatchProcessor {
+	rp.deadLetter = deadLetter
+	return rp
+}
+
+// generateRowCmds 生成命令并保留命令到行的映射
+func (rp *RedisBatchProcessor) generateRowCmds(ctx context.Context, schema SchemaInterface, data []map[string]any) (*redisRowOperations, error) {
+	cmds, rows, err := redisDriverRowCmds(ctx, rp.driver, schema, data)
+	if err != nil {
+		return nil, err
+	}
+	return &redisRowOperations{schema: schema, cmds: cmds, rowOf: rows, data: data}, nil
+}
+
+// executeRowOperations 分段执行 Pipeline，收集命令级错误；全部成功执行后按行投递死信并回调命令结果
+func (rp *RedisBatchProcessor) executeRowOperations(ctx context.Context, ops *redisRowOperations) error {
+	results := make([]*redis.Cmd, 0, len(ops.cmds))
+	rowErrs := make(map[int]error)
+	var order []int
+	var cmdErrs error
+	for _, bound := range redisChunkBounds(len(ops.cmds), rp.chunkSize, redisRowUnitEnd(ops.rowOf)) {
+		start, end := bound[0], bound[1]
+		chunk, err := rp.execPipelineCmds(ctx, ops.cmds[start:end])
+		if err != nil {
+			return err
+		}
+		results = append(results, chunk...)
+		for i, cmd := range chunk {
+			cmdErr := cmd.Err()
+			if cmdErr == nil || errors.Is(cmdErr, redis.Nil) {
+				continue
+			}
+			var redisErr redis.Error
+			if !errors.As(cmdErr, &redisErr) {
+				// 非服务端命令错误（连接中断等）：整批失败以便重试
+				return cmdErr
+			}
+			if rp.deadLetter == nil {
+				// 仅启用结果回调：命令错误仍使整批失败
+				cmdErrs = errors.Join(cmdErrs, cmdErr)
+				continue
+			}
+			row := ops.rowOf[start+i]
+			if _, seen := rowErrs[row]; !seen {
+				order = append(order, row)
+			}
+			rowErrs[row] = errors.Join(rowErrs[row], cmdErr)
+		}
+	}
+	if cmdErrs != nil {
+		return cmdErrs
+	}
+
+	for _, row := range order {
+		data := ops.data[row]
+		rp.deadLetter(ops.schema, data, RowTags(ctx, data), rowErrs[row])
+	}
+	if rp.onResult != nil {
+		for i, cmd := range results {
+			rp.onResult(ctx, ops.schema, ops.data[ops.rowOf[i]], ops.cmds[i], cmd.Val(), cmd.Err())
+		}
+	}
+	return nil
+}
+
+// execPipelineCmds 以单个 Pipeline 执行一组命令，返回与 cmds 对齐的逐条结果
+func (rp *RedisBatchProcessor) execPipelineCmds(ctx context.Context, cmds []RedisCmd) ([]*redis.Cmd, error) {
+	pipeline := rp.client.Pipeline()
+	results := make([]*redis.Cmd, 0, len(cmds))
+	for _, cmd := range cmds {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		results = append(results, pipeline.Do(ctx, cmd...))
+	}
+
+	// Exec 的错误即首个失败命令的错误，逐条结果更完整，这里只关心 ctx 取消/超时
+	_, _ = pipeline.Exec(ctx)
+	if err := ctx.Err(); err != nil {
+		if cause := context.Cause(ctx); errors.Is(err, context.DeadlineExceeded) && cause != nil {
+			return nil, cause
+		}
+		return nil, err
+	}
+	return results, nil
+}
+
+// redisOperationCmds 展开操作中的 Redis 命令（兼容逐行死信模式的包装操作）
+func redisOperationCmds(operations Operations) []RedisCmd {
+	cmds := make([]RedisCmd, 0, len(operations))
+	for _, operation := range operations {
+		switch op := operation.(type) {
+		case RedisCmd:
+			cmds = append(cmds, op)
+		case *redisRowOperations:
+			cmds = append(cmds, op.cmds...)
+		}
+	}
+	return cmds
+}
@@ -1,14 +1,23 @@
 package batchflow
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // 用来存储请求的数据的各种字段信息和对应的schema
 type Request struct {
-	schema  SchemaInterface
-	columns map[string]any // 使用 map 存储列名到值的映射
+	schema       SchemaInterface
+	columns      map[string]any    // 使用 map 存储列名到值的映射
+	priority     int               // 优先级；> 0 且启用优先通道时走 PriorityFlushInterval
+	pooled       bool              // 由 AcquireRequest 取得，flush 后归还对象池
+	tags         map[string]string // WithTag 设置的元数据，不写入数据库
+	deleteMarked bool              // MarkDelete 标记：flush 时生成按冲突键的 DELETE
 }
 
 func NewRequest(schema SchemaInterface) *Request {
@@ -18,6 +27,27 @@ func NewRequest(schema SchemaInterface) *Request {
 	}
 }
 
+// NewRequestFromValues 按位置将 values 与 schema.Columns() 配对构建请求，适合 CSV 等按列顺序排列的数据。
+// 值原样存储（等同 Set，不做 SetUint64 等 setter 的类型转换）；数量与列数不一致时返回包装 ErrValueCountMismatch 的 *SchemaError。
+func NewRequestFromValues(schema SchemaInterface, values []any) (*Request, error) {
+	if schema == nil {
+		return nil, &SchemaError{Reason: "schema is nil", Err: ErrInvalidSchema}
+	}
+	columns := schema.Columns()
+	if len(values) != len(columns) {
+		return nil, &SchemaError{
+			SchemaName: schema.Name(),
+			Reason:     fmt.Sprintf("got %d values for %d columns", len(values), len(columns)),
+			Err:        ErrValueCountMismatch,
+		}
+	}
+	r := &Request{schema: schema, columns: make(map[string]any, len(columns))}
+	for i, col := range columns {
+		r.columns[col] = values[i]
+	}
+	return r, nil
+}
+
 // Schema 获取请求的 schema
 func (r *Request) Schema() SchemaInterface {
 	return r.schema
@@ -32,6 +62,18 @@ func (r *Request) Columns() map[string]any {
 	return columns
 }
 
+// WithPriority 设置请求优先级。p > 0 视为高优先级：当 PipelineConfig.PriorityFlushInterval > 0 时
+// 进入独立的优先通道，按更短的间隔 flush；未启用优先通道时该值被忽略。
+func (r *Request) WithPriority(p int) *Request {
+	r.priority = p
+	return r
+}
+
+// Priority 返回请求优先级（默认 0）
+func (r *Request) Priority() int {
+	return r.priority
+}
+
 // GetOrderedValues 按照 schema 中定义的列顺序返回值
 func (r *Request) GetOrderedValues() []any {
 	columns := r.schema.Columns()
@@ -88,11 +130,31 @@ func (r *Request) SetUint32(colName string, value uint32) *Request {
 	return r
 }
 
+// SetUint64 设置无符号 64 位整数（如 Snowflake ID）。超出 int64 范围的值以十进制字符串存储，
+// 避免 database/sql 拒绝高位为 1 的 uint64，由数据库解析为 NUMERIC/BIGINT UNSIGNED。
 func (r *Request) SetUint64(colName string, value uint64) *Request {
+	if value > math.MaxInt64 {
+		r.columns[colName] = strconv.FormatUint(value, 10)
+		return r
+	}
 	r.columns[colName] = value
 	return r
 }
 
+// SetBigInt 设置任意精度整数（如 128 位 ID）。int64 范围内存为 int64，超出部分存为十进制字符串；nil 写入 NULL。
+// 值在设置时即完成转换，后续修改 *big.Int 不影响请求。
+func (r *Request) SetBigInt(colName string, value *big.Int) *Request {
+	switch {
+	case value == nil:
+		r.columns[colName] = nil
+	case value.IsInt64():
+		r.columns[colName] = value.Int64()
+	default:
+		r.columns[colName] = value.String()
+	}
+	return r
+}
+
 func (r *Request) SetFloat32(colName string, value float32) *Request {
 	r.columns[colName] = value
 	return r
@@ -118,11 +180,73 @@ func (r *Request) SetTime(colName string, value time.Time) *Request {
 	return r
 }
 
+// SetUnixSeconds 以 Unix 秒写入时间列。若 schema 的 ColumnTypeHints 将该列标注为时间类型
+// （timestamp/timestamptz/datetime/date 等），则转换为 UTC time.Time；否则按整数（BIGINT）原样存储。
+func (r *Request) SetUnixSeconds(colName string, secs int64) *Request {
+	return r.setUnixTime(colName, secs, time.Unix(secs, 0))
+}
+
+// SetUnixMillis 以 Unix 毫秒写入时间列，存储规则同 SetUnixSeconds
+func (r *Request) SetUnixMillis(colName string, ms int64) *Request {
+	return r.setUnixTime(colName, ms, time.UnixMilli(ms))
+}
+
+func (r *Request) setUnixTime(colName string, raw int64, t time.Time) *Request {
+	if r.isTimestampColumn(colName) {
+		r.columns[colName] = t.UTC()
+	} else {
+		r.columns[colName] = raw
+	}
+	return r
+}
+
+// isTimestampColumn 根据 SQLSchema 的列类型提示判断是否为时间类型列
+func (r *Request) isTimestampColumn(colName string) bool {
+	s, ok := r.schema.(*SQLSchema)
+	if !ok || s == nil {
+		return false
+	}
+	hint := strings.ToLower(strings.TrimSpace(s.operationConfig.ColumnTypeHints[colName]))
+	return strings.HasPrefix(hint, "timestamp") || strings.HasPrefix(hint, "datetime") || hint == "date"
+}
+
 func (r *Request) SetBytes(colName string, value []byte) *Request {
 	r.columns[colName] = value
 	return r
 }
 
+// SetValuer 写入自定义 driver.Valuer（或已编码的驱动值），SQL 驱动不做任何转换，
+// 原样追加到 ExecContext 的参数中，由 database/sql 调用其 Value()。列类型检查同样跳过该值。
+func (r *Request) SetValuer(colName string, value driver.Valuer) *Request {
+	r.columns[colName] = value
+	return r
+}
+
+// SetIntArray 设置整型数组列（如 PostgreSQL int[]/bigint[]）
+// 仅 PostgreSQL 驱动支持数组绑定（使用 pq.Array 编码）；其他驱动在生成 SQL 时返回错误。
+func (r *Request) SetIntArray(colName string, value []int64) *Request {
+	r.columns[colName] = value
+	return r
+}
+
+// SetStringArray 设置字符串数组列（如 PostgreSQL text[]），支持范围同 SetIntArray
+func (r *Request) SetStringArray(colName string, value []string) *Request {
+	r.columns[colName] = value
+	return r
+}
+
+// SetMap 将 map 聚合写入单个 JSON 列；序列化延迟到批次组装，序列化错误通过 Validate() 暴露
+func (r *Request) SetMap(colName string, value map[string]any) *Request {
+	r.columns[colName] = newJSONColumnValue(value)
+	return r
+}
+
+// SetStruct 将结构体（遵循 json tag）写入单个 JSON 列，语义同 SetMap
+func (r *Request) SetStruct(colName string, value any) *Request {
+	r.columns[colName] = newJSONColumnValue(value)
+	return r
+}
+
 func (r *Request) SetNull(colName string) *Request {
 	r.columns[colName] = nil
 	return r
@@ -134,6 +258,15 @@ func (r *Request) Set(colName string, value any) *Request {
 	return r
 }
 
+// SetIfAbsent 仅当该列尚未设置时写入 value，保留首次写入的值；已通过 SetNull 设置为 NULL 的列也视为已设置。
+// 适用于“先业务赋值、后补默认值”的增量构建场景。
+func (r *Request) SetIfAbsent(colName string, value any) *Request {
+	if _, exists := r.columns[colName]; !exists {
+		r.columns[colName] = value
+	}
+	return r
+}
+
 // 类型化的获取方法
 func (r *Request) GetInt32(colName string) (int32, error) {
 	value, exists := r.columns[colName]
@@ -201,13 +334,51 @@ func (r *Request) GetTime(colName string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("column %s is not time.Time", colName)
 }
 
-// 验证请求是否包含所有必需的列
+// 验证请求是否包含所有必需的列（SparseColumns 的 schema 允许缺列）；若 schema 声明了 ColumnTypes，同时检查值类型是否兼容；
+// 声明了 ColumnMaxLengths 时检查 string/[]byte 值长度；StrictColumns 的 schema 拒绝未声明的列；
+// MarkDelete 的请求只检查冲突键列
 func (r *Request) Validate() error {
+	if err := r.checkUnknownColumns(); err != nil {
+		return err
+	}
+	if r.deleteMarked {
+		return r.validateDelete()
+	}
 	columns := r.schema.Columns()
+	defaults := schemaDefaults(r.schema)
+	sparse := false
+	if s, ok := r.schema.(*SQLSchema); ok {
+		sparse = s.operationConfig.SparseColumns
+	}
 	for _, colName := range columns {
-		if _, exists := r.columns[colName]; !exists {
+		if _, exists := r.columns[colName]; !exists && !sparse {
+			if _, hasDefault := defaults[colName]; hasDefault {
+				continue
+			}
 			return fmt.Errorf("missing required column: %s", colName)
 		}
 	}
-	return nil
+	types := schemaColumnTypes(r.schema)
+	for _, colName := range columns {
+		value, exists := r.columns[colName]
+		if t := types[colName]; exists && !columnTypeAccepts(t, value) {
+			return &ColumnError{
+				SchemaName: r.schema.Name(),
+				Column:     colName,
+				Reason:     fmt.Sprintf("value of type %T is not compatible with declared column type %s", value, t),
+				Err:        ErrInvalidColumnType,
+			}
+		}
+		if jv, ok := value.(*jsonColumnValue); ok {
+			if _, err := jv.encode(); err != nil {
+				return &ColumnError{
+					SchemaName: r.schema.Name(),
+					Column:     colName,
+					Reason:     "json marshal failed: " + err.Error(),
+					Err:        ErrInvalidColumnType,
+				}
+			}
+		}
+	}
+	return r.checkColumnMaxLengths()
 }
@@ -0,0 +1,65 @@
+package batchflow_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestPostgreSQLDriver_ArrayColumnsWrappedWithPQArray(t *testing.T) {
+	schema := batchflow.NewSQLSchema("docs", batchflow.ConflictIgnoreOperationConfig, "id", "scores", "labels")
+	req := batchflow.NewRequest(schema).
+		SetInt64("id", 1).
+		SetIntArray("scores", []int64{1, 2, 3}).
+		SetStringArray("labels", []string{"a", "b"})
+
+	row := req.Columns()
+	_, args, err := batchflow.DefaultPostgreSQLDriver.GenerateInsertSQL(context.Background(), schema, []map[string]any{row})
+	if err != nil {
+		t.Fatalf("GenerateInsertSQL failed: %v", err)
+	}
+	if len(args) != 3 {
+		t.Fatalf("expected 3 args, got %d", len(args))
+	}
+	scores, ok := args[1].(*pq.Int64Array)
+	if !ok {
+		t.Fatalf("expected *pq.Int64Array for int array, got %T", args[1])
+	}
+	if !reflect.DeepEqual([]int64(*scores), []int64{1, 2, 3}) {
+		t.Fatalf("unexpected int array value: %v", *scores)
+	}
+	labels, ok := args[2].(*pq.StringArray)
+	if !ok {
+		t.Fatalf("expected *pq.StringArray for string array, got %T", args[2])
+	}
+	encoded, err := labels.Value()
+	if err != nil || encoded != `{"a","b"}` {
+		t.Fatalf("unexpected string array encoding: %v (err=%v)", encoded, err)
+	}
+}
+
+func TestSQLDrivers_ArrayColumnsRejectedWithoutNativeArrays(t *testing.T) {
+	schema := batchflow.NewSQLSchema("docs", batchflow.ConflictIgnoreOperationConfig, "id", "labels")
+	row := batchflow.NewRequest(schema).
+		SetInt64("id", 1).
+		SetStringArray("labels", []string{"a"}).
+		Columns()
+
+	drivers := map[string]batchflow.SQLDriver{
+		"mysql":  batchflow.DefaultMySQLDriver,
+		"sqlite": batchflow.DefaultSQLiteDriver,
+	}
+	for name, driver := range drivers {
+		_, _, err := driver.GenerateInsertSQL(context.Background(), schema, []map[string]any{row})
+		if err == nil {
+			t.Fatalf("%s: expected array value to be rejected", name)
+		}
+		if !strings.Contains(err.Error(), "labels") || !strings.Contains(err.Error(), "not supported") {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
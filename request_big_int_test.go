@@ -0,0 +1,74 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRequest_SetUint64BeyondInt64(t *testing.T) {
+	schema := batchflow.NewSQLSchema("ids", batchflow.ConflictIgnoreOperationConfig, "small", "max")
+	r := batchflow.NewRequest(schema).
+		SetUint64("small", 42).
+		SetUint64("max", math.MaxUint64)
+
+	cols := r.Columns()
+	if got := cols["small"]; got != uint64(42) {
+		t.Fatalf("small=%T(%v), want uint64(42)", got, got)
+	}
+	if got := cols["max"]; got != "18446744073709551615" {
+		t.Fatalf("max=%T(%v), want decimal string", got, got)
+	}
+
+	_, args, err := batchflow.DefaultPostgreSQLDriver.GenerateInsertSQL(context.Background(), schema, []map[string]any{cols})
+	if err != nil {
+		t.Fatalf("GenerateInsertSQL failed: %v", err)
+	}
+	for _, arg := range args {
+		// database/sql 默认转换器拒绝高位为 1 的 uint64，参数必须能通过转换
+		v, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil {
+			t.Fatalf("arg %T(%v) rejected by database/sql: %v", arg, arg, err)
+		}
+		if i, ok := v.(int64); ok && i < 0 {
+			t.Fatalf("uint64 overflowed to %d", i)
+		}
+	}
+	if args[1] != "18446744073709551615" {
+		t.Fatalf("expected MaxUint64 to survive as string arg, got %T(%v)", args[1], args[1])
+	}
+}
+
+func TestRequest_SetBigInt(t *testing.T) {
+	schema := batchflow.NewSQLSchema("ids", batchflow.ConflictIgnoreOperationConfig, "fits", "huge", "negative", "null")
+	huge, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10) // 2^128-1
+	negative, _ := new(big.Int).SetString("-9223372036854775809", 10)                // MinInt64-1
+	fits := big.NewInt(math.MaxInt64)
+
+	r := batchflow.NewRequest(schema).
+		SetBigInt("fits", fits).
+		SetBigInt("huge", huge).
+		SetBigInt("negative", negative).
+		SetBigInt("null", nil)
+	fits.SetInt64(0) // 设置后修改原值不影响请求
+
+	cols := r.Columns()
+	cases := map[string]any{
+		"fits":     int64(math.MaxInt64),
+		"huge":     "340282366920938463463374607431768211455",
+		"negative": "-9223372036854775809",
+		"null":     nil,
+	}
+	for col, want := range cases {
+		if got := cols[col]; got != want {
+			t.Fatalf("%s=%T(%v), want %T(%v)", col, got, got, want, want)
+		}
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Validate err=%v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package batchflow_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func columnTypesSchema() *batchflow.SQLSchema {
+	cfg := batchflow.DefaultOperationConfig.WithColumnTypes(map[string]batchflow.ColumnType{
+		"id":         batchflow.ColumnTypeInteger,
+		"score":      batchflow.ColumnTypeFloat,
+		"name":       batchflow.ColumnTypeString,
+		"active":     batchflow.ColumnTypeBool,
+		"created_at": batchflow.ColumnTypeTime,
+		"avatar":     batchflow.ColumnTypeBytes,
+		"meta":       batchflow.ColumnTypeJSON,
+	})
+	return batchflow.NewSQLSchema("users", cfg, "id", "score", "name", "active", "created_at", "avatar", "meta", "note")
+}
+
+func validColumnTypesRequest() *batchflow.Request {
+	return batchflow.NewRequest(columnTypesSchema()).
+		SetInt64("id", 1).
+		SetFloat64("score", 9.5).
+		SetString("name", "alice").
+		SetBool("active", true).
+		SetTime("created_at", time.Now()).
+		SetBytes("avatar", []byte{0x1}).
+		SetMap("meta", map[string]any{"k": "v"}).
+		SetTime("note", time.Now()) // 未声明类型的列不检查
+}
+
+func TestRequestValidate_ColumnTypesAcceptCompatibleValues(t *testing.T) {
+	if err := validColumnTypesRequest().Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// NULL 与超出 int64 的十进制字符串对任何声明类型均合法
+	req := validColumnTypesRequest().SetNull("created_at").SetUint64("id", math.MaxUint64)
+	if err := req.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRequestValidate_ColumnTypeMismatches(t *testing.T) {
+	cases := []struct {
+		column string
+		mutate func(*batchflow.Request) *batchflow.Request
+		gotTyp string
+	}{
+		{"id", func(r *batchflow.Request) *batchflow.Request { return r.SetTime("id", time.Now()) }, "time.Time"},
+		{"score", func(r *batchflow.Request) *batchflow.Request { return r.SetBytes("score", []byte("1")) }, "[]uint8"},
+		{"name", func(r *batchflow.Request) *batchflow.Request { return r.SetInt("name", 7) }, "int"},
+		{"active", func(r *batchflow.Request) *batchflow.Request { return r.SetString("active", "yes") }, "string"},
+		{"created_at", func(r *batchflow.Request) *batchflow.Request { return r.SetInt64("created_at", 1) }, "int64"},
+		{"id", func(r *batchflow.Request) *batchflow.Request { return r.SetString("id", "12a") }, "string"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.column+"/"+tc.gotTyp, func(t *testing.T) {
+			err := tc.mutate(validColumnTypesRequest()).Validate()
+			if !errors.Is(err, batchflow.ErrInvalidColumnType) {
+				t.Fatalf("expected ErrInvalidColumnType, got %v", err)
+			}
+			var colErr *batchflow.ColumnError
+			if !errors.As(err, &colErr) || colErr.Column != tc.column {
+				t.Fatalf("expected ColumnError for %q, got %v", tc.column, err)
+			}
+			if !strings.Contains(err.Error(), tc.gotTyp) {
+				t.Fatalf("expected message to name %s, got %q", tc.gotTyp, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestNewRequestFromValues_MatchesNamedSetters(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name", "email")
+
+	positional, err := batchflow.NewRequestFromValues(schema, []any{int64(1), "alice", "alice@example.com"})
+	if err != nil {
+		t.Fatalf("NewRequestFromValues failed: %v", err)
+	}
+	named := batchflow.NewRequest(schema).SetInt64("id", 1).SetString("name", "alice").SetString("email", "alice@example.com")
+
+	if !reflect.DeepEqual(positional.GetOrderedValues(), named.GetOrderedValues()) {
+		t.Fatalf("ordered values differ: %v vs %v", positional.GetOrderedValues(), named.GetOrderedValues())
+	}
+
+	ctx := context.Background()
+	gotPreview, err := batchflow.GenerateSQLPreview(ctx, batchflow.DefaultPostgreSQLDriver, schema, []map[string]any{positional.Columns()})
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	wantPreview, err := batchflow.GenerateSQLPreview(ctx, batchflow.DefaultPostgreSQLDriver, schema, []map[string]any{named.Columns()})
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	if gotPreview.SQL != wantPreview.SQL || !reflect.DeepEqual(gotPreview.Args, wantPreview.Args) {
+		t.Fatalf("generated SQL differs:\n%s %v\n%s %v", gotPreview.SQL, gotPreview.Args, wantPreview.SQL, wantPreview.Args)
+	}
+}
+
+func TestNewRequestFromValues_LengthMismatch(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	for _, values := range [][]any{{1}, {1, "a", "extra"}} {
+		_, err := batchflow.NewRequestFromValues(schema, values)
+		var schemaErr *batchflow.SchemaError
+		if !errors.Is(err, batchflow.ErrValueCountMismatch) || !errors.As(err, &schemaErr) || schemaErr.SchemaName != "users" {
+			t.Fatalf("expected ErrValueCountMismatch for %d values, got %v", len(values), err)
+		}
+	}
+	if _, err := batchflow.NewRequestFromValues(nil, nil); !errors.Is(err, batchflow.ErrInvalidSchema) {
+		t.Fatalf("expected ErrInvalidSchema for nil schema, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package batchflow
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Get 返回列中保存的原始值及是否已设置；SetNull 过的列返回 (nil, true)
+func (r *Request) Get(colName string) (any, bool) {
+	value, exists := r.columns[colName]
+	return value, exists
+}
+
+// GetAs 读取列值并尝试转换为 T，供不预知列类型的动态代码使用：
+//   - 类型一致时直接返回；nil 值仅可读取为可为 nil 的类型（指针、接口、切片、map）
+//   - 数值类型之间仅做无损转换（溢出或丢失小数返回错误）
+//   - string 与 []byte 互转；string 可解析为数值与 bool
+//
+// 列不存在时返回 *ColumnError（ErrMissingColumn），无法转换时返回 *ColumnError（ErrInvalidColumnType）。
+func GetAs[T any](r *Request, colName string) (T, error) {
+	var zero T
+	value, exists := r.columns[colName]
+	if !exists {
+		return zero, &ColumnError{SchemaName: r.schemaName(), Column: colName, Reason: "column not set", Err: ErrMissingColumn}
+	}
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+	target := reflect.TypeOf((*T)(nil)).Elem()
+	if value == nil {
+		switch target.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			return zero, nil
+		}
+		return zero, &ColumnError{SchemaName: r.schemaName(), Column: colName, Reason: fmt.Sprintf("cannot convert NULL to %s", target), Err: ErrInvalidColumnType}
+	}
+	converted, err := convertValue(reflect.ValueOf(value), target)
+	if err != nil {
+		return zero, &ColumnError{SchemaName: r.schemaName(), Column: colName, Reason: fmt.Sprintf("cannot convert %T to %s: %v", value, target, err), Err: ErrInvalidColumnType}
+	}
+	return converted.Interface().(T), nil
+}
+
+func (r *Request) schemaName() string {
+	if r.schema == nil {
+		return ""
+	}
+	return r.schema.Name()
+}
+
+// convertValue 在受支持的类型组合间转换，失败时返回原因
+func convertValue(src reflect.Value, target reflect.Type) (reflect.Value, error) {
+	srcKind, dstKind := src.Kind(), target.Kind()
+	switch {
+	case isNumericKind(srcKind) && isNumericKind(dstKind):
+		out := src.Convert(target)
+		// 回转比较确认无损：覆盖溢出、符号翻转与小数截断
+		if !out.Convert(src.Type()).Equal(src) {
+			return reflect.Value{}, fmt.Errorf("value %v out of range", src.Interface())
+		}
+		return out, nil
+	case srcKind == reflect.String && dstKind == reflect.Slice && target.Elem().Kind() == reflect.Uint8:
+		return reflect.ValueOf([]byte(src.String())).Convert(target), nil
+	case srcKind == reflect.Slice && src.Type().Elem().Kind() == reflect.Uint8 && dstKind == reflect.String:
+		return reflect.ValueOf(string(src.Bytes())).Convert(target), nil
+	case srcKind == reflect.String:
+		return parseString(src.String(), target)
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported conversion")
+}
+
+func parseString(s string, target reflect.Type) (reflect.Value, error) {
+	out := reflect.New(target).Elem()
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, target.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, target.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, target.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.SetBool(b)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported conversion")
+	}
+	return out, nil
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Int64) || (k >= reflect.Uint && k <= reflect.Uintptr)
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	return isIntegerKind(k) || k == reflect.Float32 || k == reflect.Float64
+}
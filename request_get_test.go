@@ -0,0 +1,80 @@
+package batchflow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRequest_GetReturnsRawValueAndPresence(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name", "deleted_at")
+	r := batchflow.NewRequest(schema).SetInt64("id", 7).SetNull("deleted_at")
+
+	if v, ok := r.Get("id"); !ok || v != int64(7) {
+		t.Fatalf("expected (7, true), got (%v, %v)", v, ok)
+	}
+	if v, ok := r.Get("deleted_at"); !ok || v != nil {
+		t.Fatalf("expected (nil, true) for NULL column, got (%v, %v)", v, ok)
+	}
+	if _, ok := r.Get("name"); ok {
+		t.Fatal("expected absent column to report false")
+	}
+}
+
+func TestGetAs_Conversions(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	r := batchflow.NewRequest(schema).
+		SetInt("count", 42).
+		SetString("age", "31").
+		SetBytes("raw", []byte("hello")).
+		SetFloat64("ratio", 2.5).
+		SetInt64("big", 1<<40).
+		SetNull("nothing")
+
+	if v, err := batchflow.GetAs[int](r, "count"); err != nil || v != 42 {
+		t.Fatalf("same type: got (%v, %v)", v, err)
+	}
+	if v, err := batchflow.GetAs[int64](r, "count"); err != nil || v != 42 {
+		t.Fatalf("int -> int64: got (%v, %v)", v, err)
+	}
+	if v, err := batchflow.GetAs[float64](r, "count"); err != nil || v != 42 {
+		t.Fatalf("int -> float64: got (%v, %v)", v, err)
+	}
+	if v, err := batchflow.GetAs[int](r, "age"); err != nil || v != 31 {
+		t.Fatalf("string -> int: got (%v, %v)", v, err)
+	}
+	if v, err := batchflow.GetAs[string](r, "raw"); err != nil || v != "hello" {
+		t.Fatalf("[]byte -> string: got (%v, %v)", v, err)
+	}
+	if v, err := batchflow.GetAs[*string](r, "nothing"); err != nil || v != nil {
+		t.Fatalf("NULL -> pointer: got (%v, %v)", v, err)
+	}
+	if v, err := batchflow.GetAs[any](r, "count"); err != nil || v != 42 {
+		t.Fatalf("any: got (%v, %v)", v, err)
+	}
+
+	failures := []struct {
+		name string
+		get  func() error
+	}{
+		{"lossy float -> int", func() error { _, err := batchflow.GetAs[int](r, "ratio"); return err }},
+		{"overflow int64 -> int32", func() error { _, err := batchflow.GetAs[int32](r, "big"); return err }},
+		{"unparsable string", func() error { _, err := batchflow.GetAs[bool](r, "raw"); return err }},
+		{"NULL -> int", func() error { _, err := batchflow.GetAs[int](r, "nothing"); return err }},
+		{"unsupported", func() error { _, err := batchflow.GetAs[[]int](r, "count"); return err }},
+	}
+	for _, tc := range failures {
+		err := tc.get()
+		var colErr *batchflow.ColumnError
+		if !errors.Is(err, batchflow.ErrInvalidColumnType) || !errors.As(err, &colErr) {
+			t.Fatalf("%s: expected ErrInvalidColumnType ColumnError, got %v", tc.name, err)
+		}
+	}
+
+	_, err := batchflow.GetAs[int](r, "missing")
+	var colErr *batchflow.ColumnError
+	if !errors.Is(err, batchflow.ErrMissingColumn) || !errors.As(err, &colErr) || colErr.Column != "missing" || colErr.SchemaName != "users" {
+		t.Fatalf("expected ErrMissingColumn ColumnError for absent column, got %v", err)
+	}
+}
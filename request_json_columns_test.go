@@ -0,0 +1,81 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type profileDoc struct {
+	Nickname string   `json:"nickname"`
+	Tags     []string `json:"tags,omitempty"`
+	Internal string   `json:"-"`
+	Age      int      `json:"age"`
+}
+
+func TestRequest_SetMapAndSetStructAssembledAsJSON(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    8,
+		FlushSize:     8,
+		FlushInterval: time.Hour,
+	})
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "attrs", "profile")
+	req := batchflow.NewRequest(schema).
+		SetInt64("id", 1).
+		SetMap("attrs", map[string]any{
+			"scores": []int{1, 2, 3},
+			"nested": map[string]any{"labels": []string{"a", "b"}},
+		}).
+		SetStruct("profile", profileDoc{Nickname: "neo", Tags: []string{"x"}, Internal: "secret", Age: 30})
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if err := b.Submit(ctx, req); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	var row map[string]any
+	deadline := time.Now().Add(2 * time.Second)
+	for row == nil {
+		if batches := mock.SnapshotExecutedBatches(); len(batches) > 0 {
+			row = batches[0][0]
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("batch was not executed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got, want := row["attrs"], `{"nested":{"labels":["a","b"]},"scores":[1,2,3]}`; got != want {
+		t.Fatalf("attrs=%v, want %s", got, want)
+	}
+	if got, want := row["profile"], `{"nickname":"neo","tags":["x"],"age":30}`; got != want {
+		t.Fatalf("profile=%v, want %s", got, want)
+	}
+}
+
+func TestRequest_SetMapMarshalErrorSurfacesInValidate(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "attrs")
+	// 链式调用不会因序列化失败而中断
+	req := batchflow.NewRequest(schema).
+		SetMap("attrs", map[string]any{"bad": make(chan int)}).
+		SetInt64("id", 1)
+
+	err := req.Validate()
+	if !errors.Is(err, batchflow.ErrInvalidColumnType) {
+		t.Fatalf("expected ErrInvalidColumnType, got %v", err)
+	}
+	var colErr *batchflow.ColumnError
+	if !errors.As(err, &colErr) || colErr.Column != "attrs" {
+		t.Fatalf("expected ColumnError for attrs, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+// statementRecorder 记录处理器实际执行的语句
+type statementRecorder struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (r *statementRecorder) hook() batchflow.ExecuteHook {
+	return func(_ context.Context, query string, _ []any, next func() error) error {
+		r.mu.Lock()
+		r.queries = append(r.queries, query)
+		r.mu.Unlock()
+		return next()
+	}
+}
+
+func (r *statementRecorder) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.queries...)
+}
+
+func TestMarkDelete_MixedFlushProducesInsertAndDelete(t *testing.T) {
+	db := openSavepointLedger(t, "batchflow_mark_delete_mixed")
+	if _, err := db.Exec("INSERT INTO ledger (id, amount) VALUES (1, 10), (2, 20), (3, 30)"); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	recorder := &statementRecorder{}
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).WithExecuteHook(recorder.hook())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b := batchflow.NewBatchFlow(ctx, 16, 4, time.Hour, batchflow.NewThrottledBatchExecutor(processor))
+
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig, "id", "amount")
+	requests := []*batchflow.Request{
+		batchflow.NewRequest(schema).SetInt64("id", 4).SetInt64("amount", 40),
+		batchflow.NewRequest(schema).SetInt64("id", 5).SetInt64("amount", 50),
+		batchflow.NewRequest(schema).SetInt64("id", 2).MarkDelete(),
+		batchflow.NewRequest(schema).SetInt64("id", 3).MarkDelete(),
+	}
+	for _, r := range requests {
+		if err := b.Submit(ctx, r); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	waitUntilEmpty(t, b)
+
+	queries := recorder.snapshot()
+	if len(queries) != 2 {
+		t.Fatalf("expected one INSERT and one DELETE, got %q", queries)
+	}
+	if !strings.HasPrefix(queries[0], "INSERT INTO ledger") {
+		t.Fatalf("expected the insert segment first, got %q", queries[0])
+	}
+	if want := "DELETE FROM ledger WHERE id IN (?, ?)"; queries[1] != want {
+		t.Fatalf("delete statement = %q, want %q", queries[1], want)
+	}
+
+	rows, err := db.Query("SELECT id FROM ledger ORDER BY id")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if want := []int64{1, 4, 5}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("remaining ids = %v, want %v", ids, want)
+	}
+}
+
+func TestMarkDelete_PreservesSubmissionOrder(t *testing.T) {
+	db := openSavepointLedger(t, "batchflow_mark_delete_order")
+	if _, err := db.Exec("INSERT INTO ledger (id, amount) VALUES (1, 10)"); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	recorder := &statementRecorder{}
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).WithExecuteHook(recorder.hook())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b := batchflow.NewBatchFlow(ctx, 16, 2, time.Hour, batchflow.NewThrottledBatchExecutor(processor))
+
+	// 先删后插：同一键最终应存在
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig, "id", "amount")
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 1).MarkDelete()); err != nil {
+		t.Fatalf("Submit delete failed: %v", err)
+	}
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 1).SetInt64("amount", 99)); err != nil {
+		t.Fatalf("Submit insert failed: %v", err)
+	}
+	waitUntilEmpty(t, b)
+
+	queries := recorder.snapshot()
+	if len(queries) != 2 || !strings.HasPrefix(queries[0], "DELETE") || !strings.HasPrefix(queries[1], "INSERT") {
+		t.Fatalf("expected DELETE then INSERT, got %q", queries)
+	}
+	var amount int64
+	if err := db.QueryRow("SELECT amount FROM ledger WHERE id = 1").Scan(&amount); err != nil {
+		t.Fatalf("expected re-inserted row: %v", err)
+	}
+	if amount != 99 {
+		t.Fatalf("amount = %d, want 99", amount)
+	}
+}
+
+func TestMarkDelete_Validate(t *testing.T) {
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig.WithConflictColumns("tenant", "id"), "tenant", "id", "amount")
+	if err := batchflow.NewRequest(schema).SetString("tenant", "a").SetInt64("id", 1).MarkDelete().Validate(); err != nil {
+		t.Fatalf("expected key-only delete to validate, got %v", err)
+	}
+	if err := batchflow.NewRequest(schema).SetInt64("id", 1).MarkDelete().Validate(); err == nil {
+		t.Fatalf("expected missing key column to fail validation")
+	}
+
+	redisSchema := batchflow.NewSchema("cache", "key", "value")
+	err := batchflow.NewRequest(redisSchema).Set("key", "k").MarkDelete().Validate()
+	if !errors.Is(err, batchflow.ErrDeleteUnsupported) {
+		t.Fatalf("expected ErrDeleteUnsupported for non-SQL schema, got %v", err)
+	}
+}
+
+func TestGenerateDeleteSQL_CompositeKeys(t *testing.T) {
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig.WithConflictColumns("tenant", "id"), "tenant", "id", "amount")
+	data := []map[string]any{
+		{"tenant": "a", "id": int64(1)},
+		{"tenant": "b", "id": int64(2)},
+		{"tenant": "a", "id": int64(1)},
+	}
+	query, args, err := batchflow.DefaultPostgreSQLDriver.GenerateDeleteSQL(context.Background(), schema, data)
+	if err != nil {
+		t.Fatalf("GenerateDeleteSQL failed: %v", err)
+	}
+	if want := "DELETE FROM ledger WHERE (tenant, id) IN (($1, $2), ($3, $4))"; query != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+	if want := []any{"a", int64(1), "b", int64(2)}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+
+	if _, _, err := batchflow.DefaultMySQLDriver.GenerateDeleteSQL(context.Background(), schema, []map[string]any{{"tenant": nil, "id": int64(1)}}); err == nil {
+		t.Fatalf("expected nil key to be rejected")
+	}
+}
+
+func TestMarkDelete_DriverWithoutDeleteSupport(t *testing.T) {
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig, "id", "amount")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b, _ := batchflow.NewBatchFlowWithMockDriver(ctx, batchflow.PipelineConfig{BufferSize: 4, FlushSize: 1, FlushInterval: time.Hour}, plainSQLDriver{})
+	errs := b.ErrorChan(1)
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 1).MarkDelete()); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, batchflow.ErrDeleteUnsupported) {
+			t.Fatalf("expected ErrDeleteUnsupported, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected a flush error for a driver without GenerateDeleteSQL")
+	}
+}
@@ -0,0 +1,92 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func maxLengthSchema() *batchflow.SQLSchema {
+	cfg := batchflow.DefaultOperationConfig.WithColumnMaxLengths(map[string]int{
+		"name":   4,
+		"avatar": 3,
+		"bio":    0, // 非正数不限制
+	})
+	return batchflow.NewSQLSchema("users", cfg, "id", "name", "avatar", "bio")
+}
+
+func TestRequestValidate_ColumnMaxLengthWithinLimit(t *testing.T) {
+	req := batchflow.NewRequest(maxLengthSchema()).
+		SetInt64("id", 1).
+		SetString("name", "世界你好"). // 4 个字符、12 字节：按字符计数
+		SetBytes("avatar", []byte{1, 2, 3}).
+		SetString("bio", strings.Repeat("x", 1024))
+	if err := req.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := req.SetNull("name").Validate(); err != nil {
+		t.Fatalf("NULL should not be length-checked: %v", err)
+	}
+}
+
+func TestRequestValidate_ColumnMaxLengthExceeded(t *testing.T) {
+	cases := []struct {
+		column string
+		set    func(*batchflow.Request) *batchflow.Request
+		length int
+	}{
+		{"name", func(r *batchflow.Request) *batchflow.Request { return r.SetString("name", "alice") }, 5},
+		{"name", func(r *batchflow.Request) *batchflow.Request { return r.SetString("name", "世界你好!") }, 5},
+		{"avatar", func(r *batchflow.Request) *batchflow.Request { return r.SetBytes("avatar", []byte{1, 2, 3, 4}) }, 4},
+	}
+	for _, tc := range cases {
+		req := batchflow.NewRequest(maxLengthSchema()).SetInt64("id", 1).SetString("name", "bob").
+			SetBytes("avatar", nil).SetNull("bio")
+		err := tc.set(req).Validate()
+		var colErr *batchflow.ColumnError
+		if !errors.As(err, &colErr) || !errors.Is(err, batchflow.ErrValueTooLong) {
+			t.Fatalf("%s: expected ColumnError wrapping ErrValueTooLong, got %v", tc.column, err)
+		}
+		if colErr.Column != tc.column || colErr.SchemaName != "users" {
+			t.Fatalf("unexpected column error: %+v", colErr)
+		}
+		if want := fmt.Sprintf("value length %d ", tc.length); !strings.Contains(colErr.Reason, want) {
+			t.Fatalf("reason %q should contain %q", colErr.Reason, want)
+		}
+	}
+}
+
+func TestBatchFlow_SubmitRejectsOversizedValueBeforeBuffering(t *testing.T) {
+	ctx := context.Background()
+	mock := batchflow.NewMockExecutor()
+	b := batchflow.NewBatchFlow(ctx, 10, 1, time.Hour, mock)
+	defer b.Close()
+
+	cfg := batchflow.DefaultOperationConfig.WithColumnMaxLengths(map[string]int{"payload": 64 << 10})
+	schema := batchflow.NewSQLSchema("blobs", cfg, "id", "payload")
+	huge := strings.Repeat("a", 1<<20)
+	err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 1).SetString("payload", huge))
+	var colErr *batchflow.ColumnError
+	if !errors.As(err, &colErr) || !errors.Is(err, batchflow.ErrValueTooLong) || colErr.Column != "payload" {
+		t.Fatalf("expected ErrValueTooLong for payload, got %v", err)
+	}
+	if !strings.Contains(colErr.Reason, "1048576") {
+		t.Fatalf("reason should report actual length: %q", colErr.Reason)
+	}
+
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 2).SetString("payload", "ok")); err != nil {
+		t.Fatalf("within-limit submit failed: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) < 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := executedRows(mock); got != 1 {
+		t.Fatalf("expected only the within-limit row to execute, got %d rows", got)
+	}
+}
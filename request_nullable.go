@@ -0,0 +1,49 @@
+package batchflow
+
+import "time"
+
+// SetNullable 按指针写入可空列：v 为 nil 时调用 SetNull，否则按 *v 的类型调用对应的类型化 setter
+// （如 uint64 经 SetUint64 处理超出 int64 的值），其余类型经 Set 原样写入。
+// 便于将 *string、*int64、*time.Time 等可空结构体字段映射为列，无需逐个判断 nil。
+// Go 方法不支持类型参数，因此以函数形式提供（同 GetAs）。
+func SetNullable[T any](r *Request, colName string, v *T) *Request {
+	if v == nil {
+		return r.SetNull(colName)
+	}
+	switch value := any(*v).(type) {
+	case string:
+		return r.SetString(colName, value)
+	case int:
+		return r.SetInt(colName, value)
+	case int8:
+		return r.SetInt8(colName, value)
+	case int16:
+		return r.SetInt16(colName, value)
+	case int32:
+		return r.SetInt32(colName, value)
+	case int64:
+		return r.SetInt64(colName, value)
+	case uint:
+		return r.SetUint(colName, value)
+	case uint8:
+		return r.SetUint8(colName, value)
+	case uint16:
+		return r.SetUint16(colName, value)
+	case uint32:
+		return r.SetUint32(colName, value)
+	case uint64:
+		return r.SetUint64(colName, value)
+	case float32:
+		return r.SetFloat32(colName, value)
+	case float64:
+		return r.SetFloat64(colName, value)
+	case bool:
+		return r.SetBool(colName, value)
+	case time.Time:
+		return r.SetTime(colName, value)
+	case []byte:
+		return r.SetBytes(colName, value)
+	default:
+		return r.Set(colName, value)
+	}
+}
@@ -0,0 +1,51 @@
+package batchflow_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestSetNullable(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "name", "age", "seen_at", "quota")
+
+	var (
+		nilName *string
+		nilAge  *int64
+		nilSeen *time.Time
+	)
+	r := batchflow.NewRequest(schema)
+	batchflow.SetNullable(r, "name", nilName)
+	batchflow.SetNullable(r, "age", nilAge)
+	batchflow.SetNullable(r, "seen_at", nilSeen)
+	for _, col := range []string{"name", "age", "seen_at"} {
+		if v, ok := r.Get(col); !ok || v != nil {
+			t.Fatalf("expected %s set to NULL, got %v (set=%v)", col, v, ok)
+		}
+	}
+
+	name, age, seen := "alice", int64(42), time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	quota := uint64(math.MaxUint64)
+	r = batchflow.NewRequest(schema)
+	batchflow.SetNullable(batchflow.SetNullable(r, "name", &name), "age", &age)
+	batchflow.SetNullable(r, "seen_at", &seen)
+	batchflow.SetNullable(r, "quota", &quota)
+	if err := r.Validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if v, _ := r.Get("name"); v != "alice" {
+		t.Fatalf("name: got %#v", v)
+	}
+	if v, _ := r.Get("age"); v != int64(42) {
+		t.Fatalf("age: got %#v", v)
+	}
+	if v, _ := r.Get("seen_at"); v != seen {
+		t.Fatalf("seen_at: got %#v", v)
+	}
+	// 经 SetUint64 处理：超出 int64 的值以十进制字符串存储
+	if v, _ := r.Get("quota"); v != "18446744073709551615" {
+		t.Fatalf("quota: got %#v", v)
+	}
+}
@@ -0,0 +1,53 @@
+package batchflow
+
+import "sync"
+
+var requestPool = sync.Pool{
+	New: func() any {
+		return &Request{columns: make(map[string]any, rowMapDefaultCap)}
+	},
+}
+
+// Reset 清空已设置的列值、优先级与删除标记，保留 schema 与 map 容量，便于同一 Request 重新填充后再次提交。
+// 已提交的 Request 在其批次 flush 前仍被 BatchFlow 引用，此时不得 Reset。
+func (r *Request) Reset() *Request {
+	clear(r.columns)
+	clear(r.tags)
+	r.priority = 0
+	r.deleteMarked = false
+	return r
+}
+
+// AcquireRequest 从对象池取出一个空的 Request 并绑定 schema，用于高吞吐场景减少分配。
+//
+// 所有权约定：
+//   - Submit 返回 nil 后，Request 归 BatchFlow 所有，flush 完成后自动归还对象池；
+//     调用方在 Submit 返回后不得再读写或 ReleaseRequest 该 Request。
+//   - 未提交或 Submit 返回错误时，由调用方通过 ReleaseRequest 归还（也可直接丢弃，交给 GC）。
+func AcquireRequest(schema SchemaInterface) *Request {
+	r := requestPool.Get().(*Request)
+	r.schema = schema
+	r.pooled = true
+	return r
+}
+
+// ReleaseRequest 将 AcquireRequest 取得的 Request 清空后归还对象池；nil 或非池化的 Request 被忽略。
+// 归还后不得再使用该 Request。
+func ReleaseRequest(r *Request) {
+	if r == nil || !r.pooled {
+		return
+	}
+	r.Reset()
+	r.schema = nil
+	r.pooled = false
+	requestPool.Put(r)
+}
+
+// releasePooledRequests flush 结束后归还批次中的池化请求
+func releasePooledRequests(batchData []*queuedRequest) {
+	for _, item := range batchData {
+		if item != nil && item.request != nil && item.request.pooled {
+			ReleaseRequest(item.request)
+		}
+	}
+}
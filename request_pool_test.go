@@ -0,0 +1,114 @@
+package batchflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRequest_ResetAndRefillProducesIndependentRows(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    10,
+		FlushSize:     1,
+		FlushInterval: time.Hour,
+	})
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name", "email")
+	request := batchflow.NewRequest(schema)
+	for i := 1; i <= 3; i++ {
+		request.Reset().SetInt64("id", int64(i)).SetString("name", "user")
+		if i == 1 {
+			request.SetString("email", "first@example.com")
+		}
+		if err := b.Submit(ctx, request); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+		// 复用前需等待上一次提交 flush 完成
+		deadline := time.Now().Add(2 * time.Second)
+		for executedRows(mock) != i {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d rows executed, got %d", i, executedRows(mock))
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	batches := mock.SnapshotExecutedBatches()
+	for i, batch := range batches {
+		if got := batch[0]["id"]; got != int64(i+1) {
+			t.Fatalf("row %d: expected id %d, got %v", i, i+1, got)
+		}
+	}
+	if got := batches[0][0]["email"]; got != "first@example.com" {
+		t.Fatalf("expected first row email preserved, got %v", got)
+	}
+	if got := batches[1][0]["email"]; got != nil {
+		t.Fatalf("expected Reset to clear email, got %v", got)
+	}
+}
+
+func TestAcquireRequest_ReleasedAfterFlush(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    100,
+		FlushSize:     10,
+		FlushInterval: time.Hour,
+	})
+	defer b.Close()
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 100; i++ {
+		if err := b.Submit(ctx, batchflow.AcquireRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 100 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 100 rows executed, got %d", executedRows(mock))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	seen := make(map[any]bool)
+	for _, batch := range mock.SnapshotExecutedBatches() {
+		for _, row := range batch {
+			seen[row["id"]] = true
+		}
+	}
+	if len(seen) != 100 {
+		t.Fatalf("expected 100 distinct ids, got %d", len(seen))
+	}
+
+	r := batchflow.AcquireRequest(schema)
+	if len(r.Columns()) != 0 || r.Schema() != schema {
+		t.Fatalf("expected clean pooled request bound to schema, got %v", r.Columns())
+	}
+	batchflow.ReleaseRequest(r)
+	batchflow.ReleaseRequest(r) // 重复归还被忽略
+}
+
+// requestSink 防止基准中的 Request 被逃逸分析优化为栈分配
+var requestSink *batchflow.Request
+
+func BenchmarkRequest_New(b *testing.B) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name", "age")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := batchflow.NewRequest(schema).SetInt64("id", int64(i)).SetString("name", "user").SetInt("age", 30)
+		requestSink = r
+	}
+}
+
+func BenchmarkRequest_Pooled(b *testing.B) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name", "age")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := batchflow.AcquireRequest(schema).SetInt64("id", int64(i)).SetString("name", "user").SetInt("age", 30)
+		requestSink = r
+		batchflow.ReleaseRequest(r)
+	}
+}
@@ -0,0 +1,114 @@
+package batchflow
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RequestsFromRows 将查询结果逐行扫描为 schema 的 Request，用于表到表的复制/迁移（BatchFlow 作为写入端）。
+//
+// 结果列按名称匹配 schema 列（SQLSchema 会同时识别 NewSQLSchemaWithColumns 映射的数据库列名），
+// 未在 schema 中声明的结果列被忽略；schema 列缺失时不报错，由 Request.Validate 判断。
+// 值按 rows.ColumnTypes() 选择 setter：NULL -> SetNull，整数/浮点/布尔/时间使用对应 setter，
+// 文本类列的 []byte 转为 string，其余 []byte 拷贝后 SetBytes（驱动会复用扫描缓冲）。
+// 函数读取全部剩余行但不关闭 rows，调用方负责 Close。
+func RequestsFromRows(schema SchemaInterface, rows *sql.Rows) ([]*Request, error) {
+	if schema == nil {
+		return nil, &SchemaError{Reason: "schema is nil", Err: ErrInvalidSchema}
+	}
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	logical := resultColumnNames(schema, names)
+	textual := make([]bool, len(names))
+	for i, ct := range types {
+		textual[i] = isTextColumnType(ct.DatabaseTypeName())
+	}
+
+	values := make([]any, len(names))
+	dest := make([]any, len(names))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	var requests []*Request
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		request := NewRequest(schema)
+		for i, col := range logical {
+			if col == "" {
+				continue
+			}
+			if err := setScannedValue(request, col, values[i], textual[i]); err != nil {
+				return nil, &ColumnError{SchemaName: schema.Name(), Column: col, Reason: err.Error(), Err: ErrInvalidColumnType}
+			}
+		}
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// resultColumnNames 将结果列名映射为 schema 的逻辑列名，未声明的列返回空串
+func resultColumnNames(schema SchemaInterface, names []string) []string {
+	byName := make(map[string]string, len(schema.Columns()))
+	sqlSchema, _ := schema.(*SQLSchema)
+	for _, col := range schema.Columns() {
+		byName[col] = col
+		if sqlSchema != nil {
+			byName[sqlSchema.DBColumn(col)] = col
+		}
+	}
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = byName[name]
+	}
+	return out
+}
+
+func isTextColumnType(name string) bool {
+	name = strings.ToUpper(name)
+	for _, marker := range []string{"CHAR", "TEXT", "CLOB", "STRING", "JSON", "UUID", "ENUM", "DECIMAL", "NUMERIC"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func setScannedValue(request *Request, col string, value any, textual bool) error {
+	switch v := value.(type) {
+	case nil:
+		request.SetNull(col)
+	case int64:
+		request.SetInt64(col, v)
+	case float64:
+		request.SetFloat64(col, v)
+	case bool:
+		request.SetBool(col, v)
+	case time.Time:
+		request.SetTime(col, v)
+	case string:
+		request.SetString(col, v)
+	case []byte:
+		if textual {
+			request.SetString(col, string(v))
+		} else {
+			request.SetBytes(col, append([]byte(nil), v...))
+		}
+	default:
+		return fmt.Errorf("unsupported scanned value of type %T", value)
+	}
+	return nil
+}
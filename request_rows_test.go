@@ -0,0 +1,88 @@
+package batchflow_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRequestsFromRows_ScansValuesIntoRequests(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`CREATE TABLE source (id INTEGER, user_name VARCHAR(32), score REAL, avatar BLOB, note TEXT, extra TEXT)`); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO source VALUES (1, 'alice', 9.5, x'0102', NULL, 'ignored'), (2, 'bob', 7.25, x'03', 'hi', 'ignored')`); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	schema := batchflow.NewSQLSchemaWithColumns("target", batchflow.ConflictIgnoreOperationConfig,
+		batchflow.Column{Logical: "id"},
+		batchflow.Column{Logical: "name", DB: "user_name"},
+		batchflow.Column{Logical: "score"},
+		batchflow.Column{Logical: "avatar"},
+		batchflow.Column{Logical: "note"},
+	)
+	rows, err := db.Query("SELECT id, user_name, score, avatar, note, extra FROM source ORDER BY id")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer rows.Close()
+
+	requests, err := batchflow.RequestsFromRows(schema, rows)
+	if err != nil {
+		t.Fatalf("RequestsFromRows failed: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	first := requests[0].Columns()
+	if first["id"] != int64(1) || first["name"] != "alice" || first["score"] != 9.5 {
+		t.Fatalf("unexpected scalar values: %v", first)
+	}
+	if avatar, ok := first["avatar"].([]byte); !ok || !bytes.Equal(avatar, []byte{1, 2}) {
+		t.Fatalf("expected blob bytes, got %#v", first["avatar"])
+	}
+	if v, ok := first["note"]; !ok || v != nil {
+		t.Fatalf("expected NULL note, got %v (present=%v)", v, ok)
+	}
+	if _, ok := first["extra"]; ok {
+		t.Fatal("expected undeclared result column to be ignored")
+	}
+	if note := requests[1].Columns()["note"]; note != "hi" {
+		t.Fatalf("expected text column scanned as string, got %#v", note)
+	}
+	for _, r := range requests {
+		if err := r.Validate(); err != nil {
+			t.Fatalf("expected scanned request to validate, got %v", err)
+		}
+	}
+
+	// 作为写入端完成表到表复制
+	b, mock := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{
+		BufferSize: 10, FlushSize: 2, FlushInterval: time.Hour,
+	})
+	defer b.Close()
+	for _, r := range requests {
+		if err := b.Submit(context.Background(), r); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 rows written, got %d", executedRows(mock))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
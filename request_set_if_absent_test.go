@@ -0,0 +1,32 @@
+package batchflow_test
+
+import (
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRequest_SetIfAbsent(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "source", "note", "deleted_at")
+	r := batchflow.NewRequest(schema).
+		Set("source", "import").
+		SetNull("deleted_at").
+		SetIfAbsent("source", "api").
+		SetIfAbsent("note", "default note").
+		SetIfAbsent("deleted_at", "2024-01-01").
+		SetIfAbsent("note", "second note")
+
+	cols := r.Columns()
+	if cols["source"] != "import" {
+		t.Fatalf("SetIfAbsent must not overwrite an earlier Set, got %v", cols["source"])
+	}
+	if cols["note"] != "default note" {
+		t.Fatalf("SetIfAbsent must set an absent column and keep the first write, got %v", cols["note"])
+	}
+	if v, ok := cols["deleted_at"]; !ok || v != nil {
+		t.Fatalf("explicit NULL must be preserved, got %v (present=%v)", v, ok)
+	}
+	if _, ok := cols["id"]; ok {
+		t.Fatal("untouched column must stay absent")
+	}
+}
@@ -0,0 +1,72 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRequest_StrictColumnsRejectsOffSchemaColumn(t *testing.T) {
+	config := batchflow.ConflictIgnoreOperationConfig.WithStrictColumns(true)
+	schema := batchflow.NewSQLSchema("users", config, "id", "name")
+
+	r := batchflow.NewRequest(schema).SetInt64("id", 1).SetString("name", "alice")
+	if err := r.Validate(); err != nil {
+		t.Fatalf("declared columns only: unexpected error %v", err)
+	}
+
+	r.Set("nmae", "typo")
+	err := r.Validate()
+	if !errors.Is(err, batchflow.ErrUnknownColumn) {
+		t.Fatalf("expected ErrUnknownColumn, got %v", err)
+	}
+	var colErr *batchflow.ColumnError
+	if !errors.As(err, &colErr) || colErr.Column != "nmae" || colErr.SchemaName != "users" {
+		t.Fatalf("expected ColumnError for column nmae, got %#v", err)
+	}
+
+	b, mock := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{
+		BufferSize:    8,
+		FlushSize:     1,
+		FlushInterval: time.Hour,
+	})
+	defer b.Close()
+	if err := b.Submit(context.Background(), r); !errors.Is(err, batchflow.ErrUnknownColumn) {
+		t.Fatalf("expected Submit to reject off-schema column, got %v", err)
+	}
+	waitUntilEmpty(t, b)
+	if got := executedRows(mock); got != 0 {
+		t.Fatalf("expected rejected request not to be executed, got %d rows", got)
+	}
+}
+
+func TestRequest_OffSchemaColumnIgnoredWithoutStrictColumns(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	r := batchflow.NewRequest(schema).SetInt64("id", 1).SetString("name", "alice")
+	r.Set("custom", 123)
+	if err := r.Validate(); err != nil {
+		t.Fatalf("non-strict schema should ignore extra columns, got %v", err)
+	}
+
+	b, mock := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{
+		BufferSize:    8,
+		FlushSize:     1,
+		FlushInterval: time.Hour,
+	})
+	defer b.Close()
+	if err := b.Submit(context.Background(), r); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitUntilEmpty(t, b)
+
+	rows := flattenBatches(mock)
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 executed row, got %d", len(rows))
+	}
+	if _, ok := rows[0]["custom"]; ok {
+		t.Fatalf("expected off-schema column to be dropped, got row %v", rows[0])
+	}
+}
@@ -0,0 +1,78 @@
+package batchflow
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"unsafe"
+)
+
+// WithTag 为请求附加元数据标签（如来源、租户、追踪 ID）。标签与列数据分开存储，不会写入数据库，
+// 随批次传递给死信回调（WithRowSavepointsTagged）与 after-flush 回调（BatchTagsFromContext）。
+func (r *Request) WithTag(key, value string) *Request {
+	if r.tags == nil {
+		r.tags = make(map[string]string, 1)
+	}
+	r.tags[key] = value
+	return r
+}
+
+// Tags 返回请求标签的副本；未设置时返回 nil
+func (r *Request) Tags() map[string]string {
+	if len(r.tags) == 0 {
+		return nil
+	}
+	return maps.Clone(r.tags)
+}
+
+type rowTagsKey struct{}
+
+// rowTags 一次 ExecuteBatch 的行标签：按 data 顺序保存，并按行 map 标识索引
+type rowTags struct {
+	ordered []map[string]string
+	byRow   map[unsafe.Pointer]map[string]string
+}
+
+// withRowTags 在批次中存在带标签的请求时，将标签挂到 ctx；data 与 requests 一一对应
+func withRowTags(ctx context.Context, requests []*Request, data []map[string]any) context.Context {
+	var tags *rowTags
+	for i, request := range requests {
+		if len(request.tags) == 0 {
+			continue
+		}
+		if tags == nil {
+			tags = &rowTags{
+				ordered: make([]map[string]string, len(requests)),
+				byRow:   make(map[unsafe.Pointer]map[string]string),
+			}
+		}
+		// 拷贝一份：池化请求在 flush 结束后会被重置
+		t := maps.Clone(request.tags)
+		tags.ordered[i] = t
+		tags.byRow[reflect.ValueOf(data[i]).UnsafePointer()] = t
+	}
+	if tags == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, rowTagsKey{}, tags)
+}
+
+// RowTags 返回 ExecuteBatch 收到的某一行对应请求的标签；ctx 须为该次 ExecuteBatch 的上下文，
+// row 须为 data 中的原始行（按 map 标识匹配，拷贝或改写后的行无法匹配）。无标签时返回 nil。
+func RowTags(ctx context.Context, row map[string]any) map[string]string {
+	tags, ok := ctx.Value(rowTagsKey{}).(*rowTags)
+	if !ok || row == nil {
+		return nil
+	}
+	return tags.byRow[reflect.ValueOf(row).UnsafePointer()]
+}
+
+// BatchTagsFromContext 返回当前批次各行的标签，顺序与 ExecuteBatch 的 data 一致，未打标签的行为 nil；
+// 批次中没有任何标签时返回 nil。可在 after-flush 回调与自定义处理器中使用。
+func BatchTagsFromContext(ctx context.Context) []map[string]string {
+	tags, ok := ctx.Value(rowTagsKey{}).(*rowTags)
+	if !ok {
+		return nil
+	}
+	return tags.ordered
+}
@@ -0,0 +1,106 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRequest_TagsSurviveToDeadLetterAndAfterFlush(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:batchflow_request_tags_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE ledger (id INTEGER PRIMARY KEY, amount INTEGER NOT NULL CHECK (amount >= 0))"); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+
+	var (
+		mu         sync.Mutex
+		deadTags   []map[string]string
+		deadRows   []map[string]any
+		flushTags  []map[string]string
+		flushCalls int
+	)
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).
+		WithRowSavepointsTagged(func(_ batchflow.SchemaInterface, row map[string]any, tags map[string]string, _ error) {
+			mu.Lock()
+			deadRows = append(deadRows, map[string]any{"id": row["id"], "amount": row["amount"]})
+			deadTags = append(deadTags, tags)
+			mu.Unlock()
+		})
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 3, FlushInterval: time.Hour},
+		Executor: batchflow.NewThrottledBatchExecutor(processor),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	b.WithAfterFlush(func(ctx context.Context, _ batchflow.SchemaInterface, _ int, _ error) {
+		mu.Lock()
+		flushCalls++
+		flushTags = batchflow.BatchTagsFromContext(ctx)
+		mu.Unlock()
+	})
+
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig, "id", "amount")
+	requests := []*batchflow.Request{
+		batchflow.NewRequest(schema).SetInt64("id", 1).SetInt64("amount", 10).WithTag("source", "import-a"),
+		batchflow.NewRequest(schema).SetInt64("id", 2).SetInt64("amount", -1).WithTag("source", "import-b").WithTag("line", "42"),
+		batchflow.NewRequest(schema).SetInt64("id", 3).SetInt64("amount", 30),
+	}
+	for _, r := range requests {
+		if err := b.Submit(ctx, r); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	// 恰好满批时 flush 异步触发，Close 不等待它完成
+	waitUntilEmpty(t, b)
+	b.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(deadRows) != 1 || deadRows[0]["id"] != int64(2) {
+		t.Fatalf("expected row 2 dead-lettered, got %v", deadRows)
+	}
+	if _, ok := deadRows[0]["source"]; ok {
+		t.Fatalf("tags must not be stored in row data: %v", deadRows[0])
+	}
+	if deadTags[0]["source"] != "import-b" || deadTags[0]["line"] != "42" {
+		t.Fatalf("expected dead-letter tags of request 2, got %v", deadTags[0])
+	}
+	if flushCalls != 1 || len(flushTags) != 3 {
+		t.Fatalf("expected one flush with 3 tag slots, got calls=%d tags=%v", flushCalls, flushTags)
+	}
+	if flushTags[0]["source"] != "import-a" || flushTags[1]["source"] != "import-b" || flushTags[2] != nil {
+		t.Fatalf("unexpected after-flush tags: %v", flushTags)
+	}
+}
+
+func TestRequest_TagsAreCopiedAndResetWithPool(t *testing.T) {
+	schema := batchflow.NewSQLSchema("t", batchflow.ConflictIgnoreOperationConfig, "id")
+	r := batchflow.NewRequest(schema).SetInt("id", 1)
+	if r.Tags() != nil {
+		t.Fatalf("expected nil tags by default")
+	}
+	r.WithTag("k", "v")
+	tags := r.Tags()
+	tags["k"] = "changed"
+	if r.Tags()["k"] != "v" {
+		t.Fatalf("Tags must return a copy")
+	}
+	if _, ok := r.Columns()["k"]; ok {
+		t.Fatalf("tags leaked into columns")
+	}
+	if r.Reset().Tags() != nil {
+		t.Fatalf("Reset must clear tags")
+	}
+}
@@ -0,0 +1,48 @@
+package batchflow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestRequest_SetUnixStoresIntegerWithoutHint(t *testing.T) {
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "created_at", "updated_at")
+	req := batchflow.NewRequest(schema).
+		SetUnixSeconds("created_at", 1700000000).
+		SetUnixMillis("updated_at", 1700000000123)
+
+	cols := req.Columns()
+	if got, ok := cols["created_at"].(int64); !ok || got != 1700000000 {
+		t.Fatalf("expected int64 seconds, got %T(%v)", cols["created_at"], cols["created_at"])
+	}
+	if got, ok := cols["updated_at"].(int64); !ok || got != 1700000000123 {
+		t.Fatalf("expected int64 millis, got %T(%v)", cols["updated_at"], cols["updated_at"])
+	}
+}
+
+func TestRequest_SetUnixConvertsForTimestampHint(t *testing.T) {
+	cfg := batchflow.ConflictIgnoreOperationConfig.WithColumnTypeHints(map[string]string{
+		"created_at": "timestamptz",
+		"updated_at": "DATETIME",
+		"seq":        "bigint",
+	})
+	schema := batchflow.NewSQLSchema("events", cfg, "created_at", "updated_at", "seq")
+	req := batchflow.NewRequest(schema).
+		SetUnixSeconds("created_at", 0).
+		SetUnixMillis("updated_at", 1500).
+		SetUnixSeconds("seq", 42)
+
+	created, err := req.GetTime("created_at")
+	if err != nil || !created.Equal(time.Unix(0, 0)) || created.Location() != time.UTC {
+		t.Fatalf("unexpected created_at: %v (err=%v)", created, err)
+	}
+	updated, err := req.GetTime("updated_at")
+	if err != nil || !updated.Equal(time.UnixMilli(1500)) {
+		t.Fatalf("unexpected updated_at: %v (err=%v)", updated, err)
+	}
+	if seq, err := req.GetInt64("seq"); err != nil || seq != 42 {
+		t.Fatalf("expected non-timestamp hint to keep integer, got %v (err=%v)", seq, err)
+	}
+}
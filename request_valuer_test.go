@@ -0,0 +1,65 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// pointValuer 自定义 driver.Valuer，编码为 PostgreSQL point 文本
+type pointValuer struct{ x, y float64 }
+
+func (p *pointValuer) Value() (driver.Value, error) {
+	return fmt.Sprintf("(%g,%g)", p.x, p.y), nil
+}
+
+func TestRequest_SetValuerReachesArgsIntact(t *testing.T) {
+	d := &execModeDriver{}
+	db := sql.OpenDB(execModeConnector{d: d})
+	t.Cleanup(func() { _ = db.Close() })
+
+	var hookArgs []any
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultPostgreSQLDriver).
+		WithExecuteHook(func(_ context.Context, _ string, args []any, next func() error) error {
+			hookArgs = append([]any(nil), args...)
+			return next()
+		})
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 10, FlushInterval: time.Hour},
+		Executor: batchflow.NewThrottledBatchExecutor(processor),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+
+	loc := &pointValuer{x: 1, y: 2}
+	schema := batchflow.NewSQLSchema("places", batchflow.ConflictIgnoreOperationConfig, "id", "loc")
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt64("id", 7).SetValuer("loc", loc)); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	b.Close()
+
+	if len(hookArgs) != 2 || hookArgs[1] != driver.Valuer(loc) {
+		t.Fatalf("expected valuer in processor args, got %#v", hookArgs)
+	}
+	if len(d.args) != 1 || len(d.args[0]) != 2 {
+		t.Fatalf("expected one exec with 2 args, got %#v", d.args)
+	}
+	if got, ok := d.args[0][1].(*pointValuer); !ok || got != loc {
+		t.Fatalf("expected the same valuer to reach the driver, got %#v", d.args[0][1])
+	}
+}
+
+func TestRequest_SetValuerNilIsNull(t *testing.T) {
+	schema := batchflow.NewSQLSchema("places", batchflow.ConflictIgnoreOperationConfig, "id", "loc")
+	r := batchflow.NewRequest(schema).SetValuer("loc", nil)
+	if v, ok := r.Get("loc"); !ok || v != nil {
+		t.Fatalf("expected NULL for nil valuer, got %v (set=%v)", v, ok)
+	}
+}
@@ -0,0 +1,113 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// 每轮返回不同的可重试错误，便于断言错误链保留了全部尝试
+type distinctAttemptProcessor struct {
+	calls int32
+	errs  []error
+}
+
+func (p *distinctAttemptProcessor) GenerateOperations(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) (batchflow.Operations, error) {
+	return batchflow.Operations{}, nil
+}
+
+func (p *distinctAttemptProcessor) ExecuteOperations(ctx context.Context, ops batchflow.Operations) error {
+	i := atomic.AddInt32(&p.calls, 1) - 1
+	return p.errs[i]
+}
+
+func TestThrottledExecutor_Retry_PreserveAttemptErrors(t *testing.T) {
+	proc := &distinctAttemptProcessor{}
+	for i := 1; i <= 3; i++ {
+		proc.errs = append(proc.errs, fmt.Errorf("timeout: attempt %d", i))
+	}
+	exec := batchflow.NewThrottledBatchExecutor(proc)
+	exec.WithRetryConfig(batchflow.RetryConfig{
+		Enabled:               true,
+		MaxAttempts:           3,
+		BackoffBase:           1 * time.Millisecond,
+		MaxBackoff:            2 * time.Millisecond,
+		PreserveAttemptErrors: true,
+	})
+	m := &retryMetrics{}
+	exec.WithMetricsReporter(m)
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}})
+	if err == nil {
+		t.Fatalf("expected final failure, got nil")
+	}
+	for i, attemptErr := range proc.errs {
+		if !errors.Is(err, attemptErr) {
+			t.Fatalf("expected joined error to wrap attempt %d error %v, got %v", i+1, attemptErr, err)
+		}
+	}
+	if got := atomic.LoadInt32(&m.final); got != 1 {
+		t.Fatalf("expected exactly one final error, got %d", got)
+	}
+	if got := atomic.LoadInt32(&m.retry); got != 2 {
+		t.Fatalf("expected two retry errors, got %d", got)
+	}
+}
+
+func TestThrottledExecutor_Retry_DefaultReturnsLastAttemptError(t *testing.T) {
+	proc := &distinctAttemptProcessor{errs: []error{
+		errors.New("timeout: first"),
+		errors.New("timeout: last"),
+	}}
+	exec := batchflow.NewThrottledBatchExecutor(proc)
+	exec.WithRetryConfig(batchflow.RetryConfig{
+		Enabled:     true,
+		MaxAttempts: 2,
+		BackoffBase: 1 * time.Millisecond,
+		MaxBackoff:  2 * time.Millisecond,
+	})
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}})
+	if !errors.Is(err, proc.errs[1]) {
+		t.Fatalf("expected last attempt error, got %v", err)
+	}
+	if errors.Is(err, proc.errs[0]) {
+		t.Fatalf("did not expect first attempt error without PreserveAttemptErrors")
+	}
+}
+
+func TestThrottledExecutor_Retry_PreserveAttemptErrorsOnCancelDuringBackoff(t *testing.T) {
+	proc := &distinctAttemptProcessor{errs: []error{
+		errors.New("timeout: first"),
+		errors.New("timeout: second"),
+	}}
+	exec := batchflow.NewThrottledBatchExecutor(proc)
+	exec.WithRetryConfig(batchflow.RetryConfig{
+		Enabled:               true,
+		MaxAttempts:           2,
+		BackoffBase:           time.Hour,
+		MaxBackoff:            time.Hour,
+		PreserveAttemptErrors: true,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	err := exec.ExecuteBatch(ctx, schema, []map[string]any{{"id": 1}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected ctx error from the cancelled backoff, got %v", err)
+	}
+	if !errors.Is(err, proc.errs[0]) {
+		t.Fatalf("expected joined error to keep the first attempt error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&proc.calls); got != 1 {
+		t.Fatalf("expected no second attempt after cancellation, got %d calls", got)
+	}
+}
@@ -0,0 +1,66 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// generateFailProcessor 生成阶段总是失败；错误文本会被默认分类器判为可重试
+type generateFailProcessor struct {
+	generates atomic.Int32
+	executes  atomic.Int32
+}
+
+func (p *generateFailProcessor) GenerateOperations(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) (batchflow.Operations, error) {
+	p.generates.Add(1)
+	return nil, errors.New("connection reset while encoding value")
+}
+
+func (p *generateFailProcessor) ExecuteOperations(ctx context.Context, ops batchflow.Operations) error {
+	p.executes.Add(1)
+	return nil
+}
+
+func TestRetry_GenerationErrorNotRetried(t *testing.T) {
+	p := &generateFailProcessor{}
+	exec := batchflow.NewThrottledBatchExecutor(p).WithRetryConfig(batchflow.RetryConfig{
+		Enabled:     true,
+		MaxAttempts: 3,
+		BackoffBase: time.Millisecond,
+		Classifier: func(error) (bool, string) {
+			return true, batchflow.ErrorReasonConnection
+		},
+	})
+
+	err := exec.ExecuteBatch(context.Background(), batchflow.NewSchema("events", "id"), []map[string]any{{"id": 1}})
+	if err == nil {
+		t.Fatal("expected generation error")
+	}
+	var batchErr *batchflow.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Stage != batchflow.BatchStageGenerate {
+		t.Fatalf("expected generate-stage BatchError, got %v", err)
+	}
+	if n := p.generates.Load(); n != 1 {
+		t.Fatalf("expected exactly one attempt, got %d", n)
+	}
+	if n := p.executes.Load(); n != 0 {
+		t.Fatalf("expected no execution, got %d", n)
+	}
+}
+
+func TestRetry_ExecutionErrorStillRetried(t *testing.T) {
+	p := &sqlStateProcessor{err: errors.New("connection reset by peer"), failures: 2}
+	exec := retryingExecutor(p)
+
+	if err := exec.ExecuteBatch(context.Background(), batchflow.NewSchema("events", "id"), []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("expected execution retry to succeed, got %v", err)
+	}
+	if n := p.calls.Load(); n != 3 {
+		t.Fatalf("expected 3 execution attempts, got %d", n)
+	}
+}
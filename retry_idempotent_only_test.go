@@ -0,0 +1,75 @@
+package batchflow_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// finalReasonMetrics 记录 IncError 的最终失败原因
+type finalReasonMetrics struct {
+	batchflow.NoopMetricsReporter
+
+	mu    sync.Mutex
+	kinds []string
+}
+
+func (m *finalReasonMetrics) IncError(_ string, kind string) {
+	m.mu.Lock()
+	m.kinds = append(m.kinds, kind)
+	m.mu.Unlock()
+}
+
+func runIdempotentOnly(t *testing.T, schema batchflow.SchemaInterface) (int32, []string) {
+	t.Helper()
+	processor := &badConnProcessor{}
+	metrics := &finalReasonMetrics{}
+	executor := batchflow.NewThrottledBatchExecutor(processor).
+		WithMetricsReporter(metrics).
+		WithRetryConfig(batchflow.RetryConfig{
+			Enabled:        true,
+			MaxAttempts:    3,
+			BackoffBase:    time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+			IdempotentOnly: true,
+		})
+	if err := executor.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}}); err == nil {
+		t.Fatal("expected batch to fail")
+	}
+	return processor.attempts.Load(), metrics.kinds
+}
+
+func TestRetryConfig_IdempotentOnlySkipsAppendOnlySchema(t *testing.T) {
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig.WithAppendOnly(true), "id")
+	attempts, kinds := runIdempotentOnly(t, schema)
+	if attempts != 1 {
+		t.Fatalf("expected a single attempt for an append-only schema, got %d", attempts)
+	}
+	if len(kinds) != 1 || kinds[0] != "final:"+batchflow.ErrorReasonNonIdempotent {
+		t.Fatalf("expected final:%s, got %v", batchflow.ErrorReasonNonIdempotent, kinds)
+	}
+}
+
+func TestRetryConfig_IdempotentOnlyRetriesConflictIgnoreSchema(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	attempts, kinds := runIdempotentOnly(t, schema)
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts for a conflict-ignore schema, got %d", attempts)
+	}
+	for _, kind := range kinds {
+		if strings.Contains(kind, batchflow.ErrorReasonNonIdempotent) {
+			t.Fatalf("unexpected non-idempotent reason: %v", kinds)
+		}
+	}
+}
+
+func TestRetryConfig_IdempotentOnlySkipsUndeclaredSchema(t *testing.T) {
+	attempts, _ := runIdempotentOnly(t, batchflow.NewSchema("counters", "cmd", "key"))
+	if attempts != 1 {
+		t.Fatalf("expected schemas without IdempotentSchema to skip retries, got %d attempts", attempts)
+	}
+}
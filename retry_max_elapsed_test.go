@@ -0,0 +1,51 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestThrottledExecutor_MaxElapsedTimeStopsRetries(t *testing.T) {
+	retryable := errors.New("i/o timeout")
+	p := &sqlStateProcessor{err: retryable, failures: 1 << 30}
+	executor := batchflow.NewThrottledBatchExecutor(p).WithRetryConfig(batchflow.RetryConfig{
+		Enabled:        true,
+		MaxAttempts:    1000,
+		BackoffBase:    20 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+		MaxElapsedTime: 50 * time.Millisecond,
+	})
+
+	schema := batchflow.NewSchema("events", "id")
+	start := time.Now()
+	err := executor.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, retryable) {
+		t.Fatalf("expected last attempt error, got %v", err)
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Fatalf("expected retries to stop promptly, took %v", elapsed)
+	}
+	if calls := p.calls.Load(); calls < 2 || calls > 5 {
+		t.Fatalf("expected a few attempts within 50ms budget, got %d", calls)
+	}
+}
+
+func TestThrottledExecutor_MaxElapsedTimeZeroKeepsAttemptLimit(t *testing.T) {
+	p := &sqlStateProcessor{err: errors.New("i/o timeout"), failures: 1 << 30}
+	executor := batchflow.NewThrottledBatchExecutor(p).WithRetryConfig(batchflow.RetryConfig{
+		Enabled:     true,
+		MaxAttempts: 4,
+		BackoffBase: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+	_ = executor.ExecuteBatch(context.Background(), batchflow.NewSchema("events", "id"), []map[string]any{{"id": 1}})
+	if calls := p.calls.Load(); calls != 4 {
+		t.Fatalf("expected MaxAttempts to bound retries, got %d attempts", calls)
+	}
+}
@@ -0,0 +1,111 @@
+package batchflow_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/rushairer/batchflow/v2"
+)
+
+// sqlStateProcessor 前 failures 次执行返回 err，之后成功
+type sqlStateProcessor struct {
+	err      error
+	failures int32
+	calls    atomic.Int32
+}
+
+func (p *sqlStateProcessor) GenerateOperations(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) (batchflow.Operations, error) {
+	return batchflow.Operations{}, nil
+}
+
+func (p *sqlStateProcessor) ExecuteOperations(ctx context.Context, ops batchflow.Operations) error {
+	if p.calls.Add(1) <= p.failures {
+		return p.err
+	}
+	return nil
+}
+
+func retryingExecutor(p batchflow.BatchProcessor) *batchflow.ThrottledBatchExecutor {
+	return batchflow.NewThrottledBatchExecutor(p).WithRetryConfig(batchflow.RetryConfig{
+		Enabled:     true,
+		MaxAttempts: 3,
+		BackoffBase: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+}
+
+func TestRetryableSQLStates_PostgreSQLErrorRetried(t *testing.T) {
+	// 40001（serialization_failure）默认判为 non_retryable
+	p := &sqlStateProcessor{err: &pq.Error{Code: "40001", Message: "restart transaction"}, failures: 1}
+	exec := retryingExecutor(p).WithRetryableSQLStates("40001")
+
+	schema := batchflow.NewSchema("events", "id")
+	if err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if n := p.calls.Load(); n != 2 {
+		t.Fatalf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestRetryableSQLStates_MySQLErrorRetried(t *testing.T) {
+	p := &sqlStateProcessor{
+		err:      &mysqlDriver.MySQLError{Number: 1105, SQLState: [5]byte{'H', 'Y', '0', '0', '0'}, Message: "unknown"},
+		failures: 1,
+	}
+	exec := retryingExecutor(p).WithRetryableSQLStates("hy000")
+
+	schema := batchflow.NewSchema("events", "id")
+	if err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if n := p.calls.Load(); n != 2 {
+		t.Fatalf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestRetryableSQLStates_UnlistedStateKeepsDefault(t *testing.T) {
+	p := &sqlStateProcessor{err: &pq.Error{Code: "40001"}, failures: 1}
+	exec := retryingExecutor(p).WithRetryableSQLStates("40P01")
+
+	schema := batchflow.NewSchema("events", "id")
+	if err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}}); err == nil {
+		t.Fatal("expected unlisted SQLSTATE to stay non-retryable")
+	}
+	if n := p.calls.Load(); n != 1 {
+		t.Fatalf("expected a single attempt, got %d", n)
+	}
+}
+
+// sqlStateError 模拟 pgx *pgconn.PgError 等仅通过 SQLState() 暴露状态码的驱动错误
+type sqlStateError struct{ code, msg string }
+
+func (e *sqlStateError) Error() string    { return e.msg }
+func (e *sqlStateError) SQLState() string { return e.code }
+
+func TestRetryableSQLStates_UnlistedStateOverridesMessageMatch(t *testing.T) {
+	// 消息命中 "lock timeout" 子串，默认分类为可重试；配置列表后以 SQLSTATE 为准
+	p := &sqlStateProcessor{err: &sqlStateError{code: "55000", msg: "canceling statement due to lock timeout"}, failures: 1}
+	if retryable, _ := batchflow.ClassifyError(p.err); !retryable {
+		t.Fatalf("precondition: expected the message to be classified as retryable by default")
+	}
+	exec := retryingExecutor(p).WithRetryableSQLStates("40001")
+
+	schema := batchflow.NewSchema("events", "id")
+	if err := exec.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}}); err == nil {
+		t.Fatal("expected SQLSTATE outside the list to be non-retryable")
+	}
+	if n := p.calls.Load(); n != 1 {
+		t.Fatalf("expected a single attempt, got %d", n)
+	}
+
+	// 不配置列表时仍按消息重试
+	p = &sqlStateProcessor{err: p.err, failures: 1}
+	if err := retryingExecutor(p).ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("expected message-based retry without a list, got %v", err)
+	}
+}
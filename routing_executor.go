@@ -0,0 +1,108 @@
+package batchflow
+
+import (
+	"context"
+	"sort"
+)
+
+// RoutingExecutor 按 schema 将批次分发到不同后端执行器，使一个 BatchFlow 同时写入多个数据库
+// （例如部分 schema 写 MySQL、部分写 Redis）。BatchFlow 的 flush 已按 schema 分组，
+// 每个组的 ExecuteBatch 会路由到对应执行器。路由按 schema 实例匹配（与分组一致）；
+// 未命中时依次尝试 flush 派生副本的源 schema（SQLSchema.Source，如按表分区与稀疏列投影）
+// 与逻辑标识（IdentifiableSchema，对应 GroupSchemasByIdentity 合并后的首个实例）。
+type RoutingExecutor struct {
+	routes     map[SchemaInterface]BatchExecutor
+	byIdentity map[string]BatchExecutor
+	fallback   BatchExecutor
+}
+
+var _ BatchExecutor = (*RoutingExecutor)(nil)
+
+// NewRoutingExecutor 创建路由执行器；未命中路由的 schema 交给 fallback，fallback 为 nil 时返回错误。
+func NewRoutingExecutor(routes map[SchemaInterface]BatchExecutor, fallback BatchExecutor) *RoutingExecutor {
+	copied := make(map[SchemaInterface]BatchExecutor, len(routes))
+	byIdentity := make(map[string]BatchExecutor)
+	for schema, executor := range routes {
+		copied[schema] = executor
+		if is, ok := schema.(IdentifiableSchema); ok && executor != nil {
+			byIdentity[is.Identity()] = executor
+		}
+	}
+	return &RoutingExecutor{routes: copied, byIdentity: byIdentity, fallback: fallback}
+}
+
+// ExecuteBatch 将批次分发到 schema 对应的执行器
+func (e *RoutingExecutor) ExecuteBatch(ctx context.Context, schema SchemaInterface, data []map[string]any) error {
+	executor := e.route(schema)
+	if executor == nil {
+		executor = e.fallback
+	}
+	if executor == nil {
+		return &SchemaError{SchemaName: schemaName(schema), Reason: "no executor routed for schema", Err: ErrInvalidSchema}
+	}
+	return executor.ExecuteBatch(ctx, schema, data)
+}
+
+// MetricsReporter 转发内部执行器的 reporter（供 BatchFlow 探测）：优先 fallback，
+// 否则按 schema 名称顺序取首个暴露 reporter 的路由执行器；均未暴露时为 nil
+func (e *RoutingExecutor) MetricsReporter() MetricsReporter {
+	for _, executor := range e.executors() {
+		if reporter := executorMetricsReporter(executor); reporter != nil {
+			return reporter
+		}
+	}
+	return nil
+}
+
+// Logger 按与 MetricsReporter 相同的顺序转发内部执行器的 Logger；均未暴露时为 nil
+func (e *RoutingExecutor) Logger() Logger {
+	for _, executor := range e.executors() {
+		if logger := executorLogger(executor); logger != nil {
+			return logger
+		}
+	}
+	return nil
+}
+
+// executors 返回 fallback 与按 schema 名称排序的路由执行器，保证探测结果稳定
+func (e *RoutingExecutor) executors() []BatchExecutor {
+	schemas := make([]SchemaInterface, 0, len(e.routes))
+	for schema, executor := range e.routes {
+		if executor != nil {
+			schemas = append(schemas, schema)
+		}
+	}
+	sort.SliceStable(schemas, func(i, j int) bool { return schemaName(schemas[i]) < schemaName(schemas[j]) })
+	executors := make([]BatchExecutor, 0, len(schemas)+1)
+	if e.fallback != nil {
+		executors = append(executors, e.fallback)
+	}
+	for _, schema := range schemas {
+		executors = append(executors, e.routes[schema])
+	}
+	return executors
+}
+
+// route 按实例、源 schema、逻辑标识的顺序查找路由；均未命中时返回 nil
+func (e *RoutingExecutor) route(schema SchemaInterface) BatchExecutor {
+	if executor := e.routes[schema]; executor != nil {
+		return executor
+	}
+	if s, ok := schema.(*SQLSchema); ok && s != nil && s.source != nil {
+		schema = s.source
+		if executor := e.routes[schema]; executor != nil {
+			return executor
+		}
+	}
+	if is, ok := schema.(IdentifiableSchema); ok && len(e.byIdentity) > 0 {
+		return e.byIdentity[is.Identity()]
+	}
+	return nil
+}
+
+func schemaName(schema SchemaInterface) string {
+	if schema == nil {
+		return ""
+	}
+	return schema.Name()
+}
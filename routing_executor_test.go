@@ -0,0 +1,182 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+type recordingExecutor struct {
+	mu   sync.Mutex
+	rows map[string]int
+}
+
+func (e *recordingExecutor) ExecuteBatch(ctx context.Context, schema batchflow.SchemaInterface, data []map[string]any) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.rows == nil {
+		e.rows = make(map[string]int)
+	}
+	e.rows[schema.Name()] += len(data)
+	return nil
+}
+
+func (e *recordingExecutor) count(name string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.rows[name]
+}
+
+func TestRoutingExecutor_DispatchesBySchema(t *testing.T) {
+	ctx := context.Background()
+	sqlExec := batchflow.NewMockExecutor()
+	redisExec := &recordingExecutor{}
+
+	users := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	sessions := batchflow.NewSchema("sessions", "cmd", "key", "value")
+	router := batchflow.NewRoutingExecutor(map[batchflow.SchemaInterface]batchflow.BatchExecutor{
+		users:    sqlExec,
+		sessions: redisExec,
+	}, nil)
+
+	b := batchflow.NewBatchFlow(ctx, 32, 100, time.Hour, router)
+	for i := 0; i < 3; i++ {
+		_ = b.Submit(ctx, batchflow.NewRequest(users).SetInt("id", i))
+	}
+	for i := 0; i < 2; i++ {
+		_ = b.Submit(ctx, batchflow.NewRequest(sessions).SetString("cmd", "SET").SetString("key", "k").SetInt("value", i))
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		sqlRows := 0
+		for _, batch := range sqlExec.SnapshotExecutedBatches() {
+			sqlRows += len(batch)
+		}
+		if sqlRows == 3 && redisExec.count("sessions") == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected routing: sql rows=%d redis rows=%d", sqlRows, redisExec.count("sessions"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if redisExec.count("users") != 0 {
+		t.Fatal("users rows must not reach the redis executor")
+	}
+}
+
+func TestRoutingExecutor_FallbackAndMissingRoute(t *testing.T) {
+	ctx := context.Background()
+	known := batchflow.NewSchema("known", "id")
+	other := batchflow.NewSchema("other", "id")
+
+	fallback := &recordingExecutor{}
+	router := batchflow.NewRoutingExecutor(map[batchflow.SchemaInterface]batchflow.BatchExecutor{
+		known: &recordingExecutor{},
+	}, fallback)
+	if err := router.ExecuteBatch(ctx, other, []map[string]any{{"id": 1}}); err != nil {
+		t.Fatalf("fallback ExecuteBatch failed: %v", err)
+	}
+	if fallback.count("other") != 1 {
+		t.Fatal("expected unrouted schema to reach fallback")
+	}
+
+	strict := batchflow.NewRoutingExecutor(map[batchflow.SchemaInterface]batchflow.BatchExecutor{known: fallback}, nil)
+	err := strict.ExecuteBatch(ctx, other, []map[string]any{{"id": 1}})
+	var schemaErr *batchflow.SchemaError
+	if !errors.Is(err, batchflow.ErrInvalidSchema) || !errors.As(err, &schemaErr) || schemaErr.SchemaName != "other" {
+		t.Fatalf("expected missing route SchemaError, got %v", err)
+	}
+}
+
+func TestRoutingExecutor_RoutesDerivedSchemaCopies(t *testing.T) {
+	ctx := context.Background()
+	routed := &recordingExecutor{}
+	fallback := &recordingExecutor{}
+
+	// 按表分区 + 稀疏列：flush 以派生副本调用 ExecuteBatch，路由仍应命中源 schema
+	config := batchflow.ConflictIgnoreOperationConfig.
+		WithSparseColumns(true).
+		WithPartitionFunc(func(row map[string]any) string {
+			if row["id"].(int) < 2 {
+				return "_a"
+			}
+			return "_b"
+		})
+	events := batchflow.NewSQLSchema("events", config, "id", "name", "note")
+	router := batchflow.NewRoutingExecutor(map[batchflow.SchemaInterface]batchflow.BatchExecutor{events: routed}, fallback)
+
+	b := batchflow.NewBatchFlow(ctx, 32, 100, time.Hour, router)
+	for i := 0; i < 4; i++ {
+		r := batchflow.NewRequest(events).SetInt("id", i).SetString("name", "n")
+		if i%2 == 0 {
+			r.SetString("note", "x")
+		}
+		if err := b.Submit(ctx, r); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	if got := routed.count("events_a") + routed.count("events_b"); got != 4 {
+		t.Fatalf("expected 4 rows routed through the source schema, got %v", routed.rows)
+	}
+	if len(fallback.rows) != 0 {
+		t.Fatalf("expected no rows on the fallback executor, got %v", fallback.rows)
+	}
+}
+
+func TestRoutingExecutor_RoutesIdentityMergedSchemas(t *testing.T) {
+	ctx := context.Background()
+	routed := &recordingExecutor{}
+	fallback := &recordingExecutor{}
+
+	first := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	second := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	// 仅为第二个实例注册路由；按标识合并后 flush 以首个实例执行
+	router := batchflow.NewRoutingExecutor(map[batchflow.SchemaInterface]batchflow.BatchExecutor{second: routed}, fallback)
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 32, FlushSize: 100, FlushInterval: time.Hour, GroupSchemasByIdentity: true},
+		Executor: router,
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	_ = b.Submit(ctx, batchflow.NewRequest(first).SetInt("id", 1))
+	_ = b.Submit(ctx, batchflow.NewRequest(second).SetInt("id", 2))
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	if got := routed.count("users"); got != 2 {
+		t.Fatalf("expected both rows routed by identity, got %v (fallback %v)", routed.rows, fallback.rows)
+	}
+}
+
+func TestRoutingExecutor_ForwardsMetricsReporterAndLogger(t *testing.T) {
+	reporter := &concurrencyWaitMetrics{}
+	logger := &capturingLogger{}
+	exec := batchflow.NewRoutingExecutor(map[batchflow.SchemaInterface]batchflow.BatchExecutor{
+		batchflow.NewSchema("events", "id"): batchflow.NewMockExecutor(),
+	}, batchflow.NewThrottledBatchExecutor(okProcessor{}).WithMetricsReporter(reporter).WithLogger(logger))
+	if exec.MetricsReporter() != batchflow.MetricsReporter(reporter) {
+		t.Fatalf("expected the fallback reporter to be forwarded")
+	}
+	if exec.Logger() != batchflow.Logger(logger) {
+		t.Fatalf("expected the fallback logger to be forwarded")
+	}
+
+	if got := batchflow.NewRoutingExecutor(nil, batchflow.NewMockExecutor()).MetricsReporter(); got != nil {
+		t.Fatalf("expected nil reporter when no inner executor exposes one, got %v", got)
+	}
+}
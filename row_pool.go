@@ -0,0 +1,83 @@
+package batchflow
+
+import (
+	"fmt"
+	"sync"
+)
+
+// 行组装缓冲复用：flush 热路径上每行一个 map、每个 schema 组一个切片，
+// 高 RPS 下是主要分配来源。这里通过 sync.Pool 复用，ExecuteBatch 返回后即归还。
+//
+// 约定：BatchExecutor / BatchProcessor / Driver 不得在 ExecuteBatch 返回后继续持有 data
+// 或其中的 map（需要保留时请自行拷贝，参见 MockExecutor）。
+
+// rowMapDefaultCap 新建行 map 的初始容量，覆盖大多数 schema 的列数
+const rowMapDefaultCap = 8
+
+var rowSlicePool = sync.Pool{
+	New: func() any {
+		rows := make([]map[string]any, 0, 64)
+		return &rows
+	},
+}
+
+var rowMapPool = sync.Pool{
+	New: func() any {
+		return make(map[string]any, rowMapDefaultCap)
+	},
+}
+
+// acquireRows 取出长度为 n 的行切片，元素尚未填充
+func acquireRows(n int) *[]map[string]any {
+	rows := rowSlicePool.Get().(*[]map[string]any)
+	if cap(*rows) < n {
+		*rows = make([]map[string]any, n)
+	} else {
+		*rows = (*rows)[:n]
+	}
+	return rows
+}
+
+// acquireRowMap 取出一个空的行 map
+func acquireRowMap() map[string]any {
+	return rowMapPool.Get().(map[string]any)
+}
+
+// releaseRows 清空并归还行切片及其中的行 map
+func releaseRows(rows *[]map[string]any) {
+	for i, row := range *rows {
+		if row != nil {
+			clear(row)
+			rowMapPool.Put(row)
+		}
+		(*rows)[i] = nil
+	}
+	*rows = (*rows)[:0]
+	rowSlicePool.Put(rows)
+}
+
+// assembleRows 将同一 schema 的请求按 schema 列顺序组装为行数据，写入 rows。
+// 未赋值的列优先使用 schema 默认值（NewSQLSchemaWithDefaults），否则以 nil 填充，与 GetOrderedValues 语义一致；
+// SetMap/SetStruct 的延迟值在此序列化。
+func assembleRows(columns []string, requests []*Request, rows []map[string]any) error {
+	var defaults map[string]any
+	if len(requests) > 0 {
+		defaults = schemaDefaults(requests[0].schema)
+	}
+	for i, request := range requests {
+		row := acquireRowMap()
+		rows[i] = row
+		for _, col := range columns {
+			raw, ok := request.columns[col]
+			if !ok {
+				raw = defaults[col]
+			}
+			value, err := resolveColumnValue(raw)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", col, err)
+			}
+			row[col] = value
+		}
+	}
+	return nil
+}
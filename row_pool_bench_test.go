@@ -0,0 +1,76 @@
+package batchflow
+
+import (
+	"fmt"
+	"testing"
+)
+
+func benchmarkRowRequests(n int) (*SQLSchema, []*Request) {
+	schema := NewSQLSchema("users", ConflictIgnoreOperationConfig, "id", "name", "email", "age", "created_at")
+	requests := make([]*Request, n)
+	for i := range requests {
+		requests[i] = NewRequest(schema).
+			SetInt64("id", int64(i)).
+			SetString("name", fmt.Sprintf("user_%d", i)).
+			SetString("email", fmt.Sprintf("user_%d@example.com", i)).
+			SetInt("age", 20+i%50).
+			SetInt64("created_at", 1700000000)
+	}
+	return schema, requests
+}
+
+// BenchmarkAssembleRows_Alloc 旧实现：每行新建 map + 每组新建切片
+func BenchmarkAssembleRows_Alloc(b *testing.B) {
+	schema, requests := benchmarkRowRequests(1000)
+	columns := schema.Columns()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data := make([]map[string]any, len(requests))
+		for j, request := range requests {
+			rowData := make(map[string]any)
+			values := request.GetOrderedValues()
+			for k, col := range columns {
+				if k < len(values) {
+					rowData[col] = values[k]
+				}
+			}
+			data[j] = rowData
+		}
+		_ = data
+	}
+}
+
+// BenchmarkAssembleRows_Pooled 池化实现：复用行切片与行 map
+func BenchmarkAssembleRows_Pooled(b *testing.B) {
+	schema, requests := benchmarkRowRequests(1000)
+	columns := schema.Columns()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows := acquireRows(len(requests))
+		if err := assembleRows(columns, requests, *rows); err != nil {
+			b.Fatal(err)
+		}
+		releaseRows(rows)
+	}
+}
+
+func TestAssembleRows_ReleaseClearsRows(t *testing.T) {
+	schema, requests := benchmarkRowRequests(3)
+	rows := acquireRows(len(requests))
+	if err := assembleRows(schema.Columns(), requests, *rows); err != nil {
+		t.Fatal(err)
+	}
+	if got := (*rows)[2]["email"]; got != "user_2@example.com" {
+		t.Fatalf("unexpected assembled value: %v", got)
+	}
+	row := (*rows)[0]
+	releaseRows(rows)
+	if len(row) != 0 {
+		t.Fatalf("expected released row map to be cleared, got %v", row)
+	}
+	if len(*rows) != 0 {
+		t.Fatalf("expected released slice to be reset, got len=%d", len(*rows))
+	}
+}
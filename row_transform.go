@@ -0,0 +1,34 @@
+package batchflow
+
+import "fmt"
+
+// RowTransformFunc 在批次组装时对每一行调用，可原地修改 row（如写入派生列、分区键）。
+// 返回错误时整组 flush 失败。
+type RowTransformFunc func(schema SchemaInterface, row map[string]any) error
+
+// WithRowTransform 注册行变换钩子：在请求转换为行 map 之后、列压缩/加密与 SQL 生成之前逐行调用，
+// 用于补充计算列（如其他列的哈希）。派生列须已在 schema 中声明才会出现在生成的 SQL 中。
+// 与处理器中间件不同，钩子作用于组装后的行数据；row 为池化对象，仅在回调期间有效。
+// 钩子返回错误时该组以 BatchStageValidate 阶段的 *BatchError 失败，不会执行。传入 nil 取消注册。
+func (b *BatchFlow) WithRowTransform(fn RowTransformFunc) *BatchFlow {
+	if fn == nil {
+		b.rowTransform.Store(nil)
+		return b
+	}
+	b.rowTransform.Store(&fn)
+	return b
+}
+
+// transformRows 对已组装的行依次应用行变换钩子
+func (b *BatchFlow) transformRows(schema SchemaInterface, rows []map[string]any) error {
+	fn := b.rowTransform.Load()
+	if fn == nil {
+		return nil
+	}
+	for _, row := range rows {
+		if err := (*fn)(schema, row); err != nil {
+			return fmt.Errorf("row transform: %w", err)
+		}
+	}
+	return nil
+}
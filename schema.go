@@ -1,5 +1,11 @@
 package batchflow
 
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
 type SchemaInterface interface {
 	Name() string
 	Columns() []string
@@ -12,6 +18,9 @@ const (
 	ConflictIgnore ConflictStrategy = iota
 	ConflictReplace
 	ConflictUpdate
+	// ConflictTouch 冲突时执行自赋值的空更新（MySQL: ON DUPLICATE KEY UPDATE k = k），
+	// 不修改已有行，但影响行数可用于区分新插入与已存在；仅 MySQL 驱动支持
+	ConflictTouch
 )
 
 // 操作配置
@@ -28,6 +37,44 @@ type SQLOperationConfig struct {
 	// WithDeduplicateByConflictColumns(false) to disable it.
 	DeduplicateByConflictColumns bool
 	deduplicateConfigured        bool
+	// ColumnTypeHints maps column names to driver-specific type casts. The
+	// PostgreSQL driver appends them to placeholders (e.g. $1::jsonb) when the
+	// server cannot infer a parameter type. Other drivers ignore the hints.
+	// Request.SetUnixSeconds/SetUnixMillis also consult them: timestamp-like
+	// hints store a time.Time, anything else stores the raw integer.
+	ColumnTypeHints map[string]string
+	// ColumnTypes declares the logical value type of columns. Request.Validate
+	// checks set values against it and reports mismatches as
+	// ErrInvalidColumnType before the batch reaches the database. Columns not
+	// listed are not checked.
+	ColumnTypes map[string]ColumnType
+	// ColumnMaxLengths caps the length of string and []byte column values:
+	// strings are measured in characters (runes), byte slices in bytes.
+	// Oversized values are rejected by Request.Validate and by Submit with
+	// ErrValueTooLong, before they are buffered. Non-positive limits and
+	// columns not listed are not checked.
+	ColumnMaxLengths map[string]int
+	// SparseColumns allows requests to populate only a subset of the schema
+	// columns. At flush time requests sharing the same populated column set
+	// are written together, one INSERT per distinct set, so omitted columns
+	// fall back to the database column defaults instead of NULL.
+	SparseColumns bool
+	// PartitionFunc routes rows to per-partition tables. At flush time each
+	// schema group is split by the returned suffix and every subgroup is
+	// written to Name()+suffix, e.g. events_202401 for time-partitioned or
+	// sharded tables. An empty suffix keeps the schema table name.
+	PartitionFunc PartitionFunc
+	// AppendOnly marks targets without a unique key (logs, event tables). The
+	// conflict strategy then cannot deduplicate and a replayed batch inserts
+	// duplicates, so the schema reports Idempotent() == false and
+	// RetryConfig.IdempotentOnly skips retries for it. SQL generation is
+	// unchanged.
+	AppendOnly bool
+	// StrictColumns rejects requests that set columns not declared by the
+	// schema, which are otherwise silently dropped at flush time. Such
+	// requests fail Request.Validate and Submit with ErrUnknownColumn, so
+	// typos in column names surface immediately.
+	StrictColumns bool
 }
 
 // Schema 表结构定义
@@ -58,6 +105,30 @@ func (s *Schema) Columns() []string {
 type SQLSchema struct {
 	*Schema
 	operationConfig SQLOperationConfig
+	// dbColumns 逻辑列名 -> 数据库列名；为空表示两者一致
+	dbColumns map[string]string
+	// defaults 请求未设置该列时使用的默认值（逻辑列名 -> 值或 DefaultExpr）
+	defaults map[string]any
+	// deleteRows 删除段（MarkDelete）使用的副本：生成阶段调用 GenerateDeleteSQL
+	deleteRows bool
+	// source flush 期间派生副本（按表分区、稀疏列投影、删除段）的源 schema；用户创建的实例为 nil
+	source *SQLSchema
+}
+
+// Source 返回调用方创建的 schema：flush 派生的副本（按表分区、稀疏列投影、删除段）返回其源实例，其余返回自身。
+// 按 schema 实例查表的自定义执行器应使用 Source() 作为键。
+func (s *SQLSchema) Source() *SQLSchema {
+	if s.source != nil {
+		return s.source
+	}
+	return s
+}
+
+// Column 列定义：Logical 为 Request setter 使用的逻辑名，DB 为生成 SQL 时使用的数据库列名。
+// DB 为空时与 Logical 相同。
+type Column struct {
+	Logical string
+	DB      string
 }
 
 func NewSQLSchema(name string, operationConfig SQLOperationConfig, columns ...string) *SQLSchema {
@@ -68,6 +139,107 @@ func NewSQLSchema(name string, operationConfig SQLOperationConfig, columns ...st
 	}
 }
 
+// NewSQLSchemaE 与 NewSQLSchema 相同，但在构造时执行 Validate，提前暴露表名/列名错误
+func NewSQLSchemaE(name string, operationConfig SQLOperationConfig, columns ...string) (*SQLSchema, error) {
+	schema := NewSQLSchema(name, operationConfig, columns...)
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Validate 校验表名非空、至少声明一列，且列名非空、不重复。
+// NewSQLSchema 为保持兼容不做校验，这些错误否则要到 Submit 时才会出现。
+func (s *SQLSchema) Validate() error {
+	if s.Name() == "" {
+		return &SchemaError{Reason: "schema name is empty", Err: ErrEmptySchemaName}
+	}
+	columns := s.Columns()
+	if len(columns) == 0 {
+		return &ColumnError{SchemaName: s.Name(), Reason: "schema declares no columns", Err: ErrMissingColumn}
+	}
+	seen := make(map[string]struct{}, len(columns))
+	for i, col := range columns {
+		if col == "" {
+			return &ColumnError{SchemaName: s.Name(), Reason: fmt.Sprintf("column name at index %d is empty", i), Err: ErrInvalidSchema}
+		}
+		if _, dup := seen[col]; dup {
+			return &ColumnError{SchemaName: s.Name(), Column: col, Reason: "duplicate column name", Err: ErrInvalidSchema}
+		}
+		seen[col] = struct{}{}
+	}
+	return nil
+}
+
+// NewSQLSchemaWithColumns 使用逻辑名/数据库列名映射创建 SQLSchema。
+// Columns()、Request setter、ConflictColumns/UpdateColumns/ColumnTypeHints 均使用逻辑名，
+// SQL 驱动在生成语句时替换为数据库列名，例如 Request.SetInt64("userId", 1) 写入 user_id。
+func NewSQLSchemaWithColumns(name string, operationConfig SQLOperationConfig, columns ...Column) *SQLSchema {
+	logical := make([]string, len(columns))
+	var dbColumns map[string]string
+	for i, col := range columns {
+		logical[i] = col.Logical
+		if col.DB != "" && col.DB != col.Logical {
+			if dbColumns == nil {
+				dbColumns = make(map[string]string, len(columns))
+			}
+			dbColumns[col.Logical] = col.DB
+		}
+	}
+	schema := NewSQLSchema(name, operationConfig, logical...)
+	schema.dbColumns = dbColumns
+	return schema
+}
+
+// NewSQLSchemaWithDefaults 创建带列默认值的 SQLSchema。请求未设置（而非 SetNull）某个带默认值的列时，
+// flush 组装行数据会填入默认值：普通值作为参数绑定，DefaultExpr（如 "DEFAULT"、"NOW()"）内联到 SQL。
+// 带默认值的列视为已赋值，Request.Validate 不会报缺失。
+func NewSQLSchemaWithDefaults(name string, operationConfig SQLOperationConfig, defaults map[string]any, columns ...string) *SQLSchema {
+	schema := NewSQLSchema(name, operationConfig, columns...)
+	if len(defaults) > 0 {
+		schema.defaults = maps.Clone(defaults)
+	}
+	return schema
+}
+
+// Defaults 返回列默认值的副本
+func (s *SQLSchema) Defaults() map[string]any {
+	return maps.Clone(s.defaults)
+}
+
+// schemaDefaults 返回 schema 的列默认值（只读）；非 SQLSchema 或未配置时为 nil
+func schemaDefaults(schema SchemaInterface) map[string]any {
+	if s, ok := schema.(*SQLSchema); ok && s != nil {
+		return s.defaults
+	}
+	return nil
+}
+
+// DBColumn 返回逻辑列名对应的数据库列名；未映射时原样返回
+func (s *SQLSchema) DBColumn(logical string) string {
+	if db, ok := s.dbColumns[logical]; ok {
+		return db
+	}
+	return logical
+}
+
+// DBColumns 按 Columns() 顺序返回数据库列名
+func (s *SQLSchema) DBColumns() []string {
+	return s.dbColumnNames(s.Columns())
+}
+
+// dbColumnNames 将一组逻辑列名转换为数据库列名；无映射时直接返回入参
+func (s *SQLSchema) dbColumnNames(columns []string) []string {
+	if len(s.dbColumns) == 0 {
+		return columns
+	}
+	out := make([]string, len(columns))
+	for i, col := range columns {
+		out[i] = s.DBColumn(col)
+	}
+	return out
+}
+
 func (s *SQLSchema) OperationConfig() any {
 	return s.operationConfig
 }
@@ -89,6 +261,51 @@ func (c SQLOperationConfig) WithUpdateColumns(cols ...string) SQLOperationConfig
 	return c.withDefaults()
 }
 
+// WithColumnTypeHints sets per-column type casts, e.g. {"payload": "jsonb", "tags": "int[]"}.
+func (c SQLOperationConfig) WithColumnTypeHints(hints map[string]string) SQLOperationConfig {
+	c.ColumnTypeHints = make(map[string]string, len(hints))
+	for col, typ := range hints {
+		c.ColumnTypeHints[col] = typ
+	}
+	return c.withDefaults()
+}
+
+// WithColumnTypes declares per-column value types, e.g. {"id": ColumnTypeInteger, "created_at": ColumnTypeTime}.
+func (c SQLOperationConfig) WithColumnTypes(types map[string]ColumnType) SQLOperationConfig {
+	c.ColumnTypes = maps.Clone(types)
+	return c.withDefaults()
+}
+
+// WithColumnMaxLengths sets per-column length limits, e.g. {"name": 64, "avatar": 1 << 20}.
+func (c SQLOperationConfig) WithColumnMaxLengths(limits map[string]int) SQLOperationConfig {
+	c.ColumnMaxLengths = maps.Clone(limits)
+	return c.withDefaults()
+}
+
+// WithPartitionFunc sets the table-suffix partition function, see PartitionFunc.
+func (c SQLOperationConfig) WithPartitionFunc(fn PartitionFunc) SQLOperationConfig {
+	c.PartitionFunc = fn
+	return c.withDefaults()
+}
+
+// WithSparseColumns enables per-request column subsets, see SparseColumns.
+func (c SQLOperationConfig) WithSparseColumns(enabled bool) SQLOperationConfig {
+	c.SparseColumns = enabled
+	return c.withDefaults()
+}
+
+// WithAppendOnly marks the target as having no unique key, see AppendOnly.
+func (c SQLOperationConfig) WithAppendOnly(enabled bool) SQLOperationConfig {
+	c.AppendOnly = enabled
+	return c.withDefaults()
+}
+
+// WithStrictColumns rejects columns not declared by the schema, see StrictColumns.
+func (c SQLOperationConfig) WithStrictColumns(enabled bool) SQLOperationConfig {
+	c.StrictColumns = enabled
+	return c.withDefaults()
+}
+
 func (c SQLOperationConfig) WithDeduplicateByConflictColumns(enabled bool) SQLOperationConfig {
 	c.DeduplicateByConflictColumns = enabled
 	c.deduplicateConfigured = true
@@ -110,3 +327,36 @@ var ConflictReplaceOperationConfig = SQLOperationConfig{
 var ConflictUpdateOperationConfig = SQLOperationConfig{
 	ConflictStrategy: ConflictUpdate,
 }
+
+var ConflictTouchOperationConfig = SQLOperationConfig{
+	ConflictStrategy: ConflictTouch,
+}
+
+// conflictStrategies 可按名称解析的冲突策略，名称与观测属性 conflict_strategy 一致
+var conflictStrategies = []ConflictStrategy{ConflictIgnore, ConflictReplace, ConflictUpdate, ConflictTouch}
+
+// ParseConflictStrategy 按名称解析冲突策略（忽略大小写与首尾空白）："ignore"、"replace"、"update"、"touch"，
+// 供 YAML/环境变量等配置层使用。未知名称返回 *ConfigError（Field 为 "ConflictStrategy"）。
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	names := make([]string, len(conflictStrategies))
+	for i, strategy := range conflictStrategies {
+		names[i] = conflictStrategyName(strategy)
+		if name == names[i] {
+			return strategy, nil
+		}
+	}
+	return ConflictIgnore, &ConfigError{
+		Field: "ConflictStrategy",
+		Cause: fmt.Errorf("unknown conflict strategy %q (want one of %s)", s, strings.Join(names, ", ")),
+	}
+}
+
+// ParseSQLOperationConfig 按策略名称构造 SQLOperationConfig，其余字段为默认值，可继续链式调用 With* 方法
+func ParseSQLOperationConfig(strategy string) (SQLOperationConfig, error) {
+	parsed, err := ParseConflictStrategy(strategy)
+	if err != nil {
+		return SQLOperationConfig{}, err
+	}
+	return SQLOperationConfig{ConflictStrategy: parsed}.withDefaults(), nil
+}
@@ -0,0 +1,68 @@
+package batchflow_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestSQLSchema_ColumnMappingUsesDBNames(t *testing.T) {
+	cfg := batchflow.ConflictUpdateOperationConfig.WithConflictColumns("userId")
+	schema := batchflow.NewSQLSchemaWithColumns("users", cfg,
+		batchflow.Column{Logical: "userId", DB: "user_id"},
+		batchflow.Column{Logical: "displayName", DB: "display_name"},
+		batchflow.Column{Logical: "age"},
+	)
+
+	if !reflect.DeepEqual(schema.Columns(), []string{"userId", "displayName", "age"}) {
+		t.Fatalf("unexpected logical columns: %v", schema.Columns())
+	}
+	if !reflect.DeepEqual(schema.DBColumns(), []string{"user_id", "display_name", "age"}) {
+		t.Fatalf("unexpected db columns: %v", schema.DBColumns())
+	}
+
+	row := batchflow.NewRequest(schema).
+		SetInt64("userId", 1).
+		SetString("displayName", "alice").
+		SetInt("age", 30).
+		Columns()
+
+	tests := []struct {
+		name   string
+		driver batchflow.SQLDriver
+		want   []string
+	}{
+		{"mysql", batchflow.DefaultMySQLDriver, []string{
+			"INSERT INTO users (user_id, display_name, age)",
+			"ON DUPLICATE KEY UPDATE display_name = VALUES(display_name), age = VALUES(age)",
+		}},
+		{"postgresql", batchflow.DefaultPostgreSQLDriver, []string{
+			"INSERT INTO users (user_id, display_name, age)",
+			"ON CONFLICT (user_id) DO UPDATE SET display_name = EXCLUDED.display_name, age = EXCLUDED.age",
+		}},
+		{"sqlite", batchflow.DefaultSQLiteDriver, []string{
+			"INSERT INTO users (user_id, display_name, age)",
+			"DO UPDATE SET display_name = excluded.display_name, age = excluded.age",
+		}},
+	}
+	for _, tt := range tests {
+		sql, args, err := tt.driver.GenerateInsertSQL(context.Background(), schema, []map[string]any{row})
+		if err != nil {
+			t.Fatalf("%s: GenerateInsertSQL failed: %v", tt.name, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(sql, want) {
+				t.Fatalf("%s: sql %q does not contain %q", tt.name, sql, want)
+			}
+		}
+		if strings.Contains(sql, "userId") || strings.Contains(sql, "displayName") {
+			t.Fatalf("%s: sql leaks logical names: %s", tt.name, sql)
+		}
+		if !reflect.DeepEqual(args, []any{int64(1), "alice", 30}) {
+			t.Fatalf("%s: unexpected args: %v", tt.name, args)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package batchflow_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func defaultsSchema() *batchflow.SQLSchema {
+	return batchflow.NewSQLSchemaWithDefaults("events", batchflow.ConflictIgnoreOperationConfig, map[string]any{
+		"source":     "api",
+		"created_at": batchflow.DefaultExpr("NOW()"),
+	}, "id", "source", "created_at")
+}
+
+func TestSchemaDefaults_FilledDuringAssembly(t *testing.T) {
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    16,
+		FlushSize:     3,
+		FlushInterval: time.Hour,
+	})
+	defer b.Close()
+
+	schema := defaultsSchema()
+	reqs := []*batchflow.Request{
+		batchflow.NewRequest(schema).SetInt64("id", 1),
+		batchflow.NewRequest(schema).SetInt64("id", 2).SetString("source", "batch"),
+		batchflow.NewRequest(schema).SetInt64("id", 3).SetNull("source"),
+	}
+	for _, req := range reqs {
+		if err := req.Validate(); err != nil {
+			t.Fatalf("defaulted columns must pass Validate: %v", err)
+		}
+		if err := b.Submit(ctx, req); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for executedRows(mock) != 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 3 rows, got %d", executedRows(mock))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	rows := mock.SnapshotExecutedBatches()[0]
+	if rows[0]["source"] != "api" {
+		t.Fatalf("expected literal default, got %#v", rows[0]["source"])
+	}
+	if rows[0]["created_at"] != batchflow.DefaultExpr("NOW()") {
+		t.Fatalf("expected expression default, got %#v", rows[0]["created_at"])
+	}
+	if rows[1]["source"] != "batch" {
+		t.Fatalf("explicit value must win over default, got %#v", rows[1]["source"])
+	}
+	if rows[2]["source"] != nil {
+		t.Fatalf("explicit SetNull must stay NULL, got %#v", rows[2]["source"])
+	}
+}
+
+func TestSchemaDefaults_ExpressionInlinedBySQLDrivers(t *testing.T) {
+	schema := defaultsSchema()
+	rows := []map[string]any{
+		{"id": int64(1), "source": "api", "created_at": batchflow.DefaultExpr("NOW()")},
+		{"id": int64(2), "source": "batch", "created_at": batchflow.DefaultExpr("NOW()")},
+	}
+
+	cases := []struct {
+		name   string
+		driver batchflow.SQLDriver
+		values string
+	}{
+		{name: "mysql", driver: batchflow.DefaultMySQLDriver, values: "VALUES (?, ?, NOW()), (?, ?, NOW())"},
+		{name: "postgresql", driver: batchflow.DefaultPostgreSQLDriver, values: "VALUES ($1, $2, NOW()), ($3, $4, NOW())"},
+		{name: "sqlite", driver: batchflow.DefaultSQLiteDriver, values: "VALUES (?, ?, NOW()), (?, ?, NOW())"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args, err := tc.driver.GenerateInsertSQL(context.Background(), schema, rows)
+			if err != nil {
+				t.Fatalf("GenerateInsertSQL failed: %v", err)
+			}
+			if !strings.Contains(sql, tc.values) {
+				t.Fatalf("expected %q in %s", tc.values, sql)
+			}
+			if len(args) != 4 {
+				t.Fatalf("expected 4 args (expressions excluded), got %d: %v", len(args), args)
+			}
+			if args[1] != "api" || args[3] != "batch" {
+				t.Fatalf("unexpected args %v", args)
+			}
+		})
+	}
+}
+
+func TestSchemaDefaults_PostgreSQLTypeHintsKeepArgIndexes(t *testing.T) {
+	schema := batchflow.NewSQLSchemaWithDefaults("events",
+		batchflow.ConflictIgnoreOperationConfig.WithColumnTypeHints(map[string]string{"payload": "jsonb"}),
+		map[string]any{"created_at": batchflow.DefaultExpr("DEFAULT")},
+		"id", "created_at", "payload")
+	rows := []map[string]any{{"id": int64(1), "created_at": batchflow.DefaultExpr("DEFAULT"), "payload": "{}"}}
+
+	sql, args, err := batchflow.DefaultPostgreSQLDriver.GenerateInsertSQL(context.Background(), schema, rows)
+	if err != nil {
+		t.Fatalf("GenerateInsertSQL failed: %v", err)
+	}
+	if !strings.Contains(sql, "VALUES ($1, DEFAULT, $2::jsonb)") {
+		t.Fatalf("unexpected SQL: %s", sql)
+	}
+	if len(args) != 2 {
+		t.Fatalf("expected 2 args, got %d", len(args))
+	}
+}
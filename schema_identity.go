@@ -0,0 +1,88 @@
+package batchflow
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// IdentifiableSchema 可选接口：返回 schema 的逻辑标识。开启 PipelineConfig.GroupSchemasByIdentity 时，
+// 标识相同的不同实例在 flush 中合并为同一批；未实现该接口的 schema 仍按实例（指针）分组。
+type IdentifiableSchema interface {
+	Identity() string
+}
+
+// Identity 由名称与有序列名组成
+func (s *Schema) Identity() string {
+	return fmt.Sprintf("%q%q", s.name, s.columns)
+}
+
+// Identity 由名称、有序列名、数据库列名映射、操作配置与列默认值组成；
+// 任一差异（如冲突策略、冲突列）都会生成不同标识，避免不同写入语义的实例被合并。
+// 各字段逐项按固定顺序写入（map 按键排序），不依赖 %v 对结构体的整体格式化，结果与指针地址、map 遍历顺序无关。
+// 函数无法比较：配置了 PartitionFunc 的实例以自身地址作为标识的一部分，只与自身相同。
+func (s *SQLSchema) Identity() string {
+	var b strings.Builder
+	b.WriteString(s.Schema.Identity())
+	cfg := s.operationConfig
+	fmt.Fprintf(&b, "|db%s", sortedPairs(s.dbColumns, func(v string) string { return strconv.Quote(v) }))
+	fmt.Fprintf(&b, "|strategy=%d|conflict=%q|update=%q", cfg.ConflictStrategy, cfg.ConflictColumns, cfg.UpdateColumns)
+	fmt.Fprintf(&b, "|dedup=%t,%t|sparse=%t|append=%t|strict=%t",
+		cfg.DeduplicateByConflictColumns, cfg.deduplicateConfigured, cfg.SparseColumns, cfg.AppendOnly, cfg.StrictColumns)
+	fmt.Fprintf(&b, "|hints%s", sortedPairs(cfg.ColumnTypeHints, func(v string) string { return strconv.Quote(v) }))
+	fmt.Fprintf(&b, "|types%s", sortedPairs(cfg.ColumnTypes, func(v ColumnType) string { return strconv.Itoa(int(v)) }))
+	fmt.Fprintf(&b, "|maxlen%s", sortedPairs(cfg.ColumnMaxLengths, strconv.Itoa))
+	fmt.Fprintf(&b, "|defaults%s", sortedPairs(s.defaults, func(v any) string { return fmt.Sprintf("%T:%v", v, v) }))
+	if cfg.PartitionFunc != nil {
+		fmt.Fprintf(&b, "|partition=%p", s)
+	}
+	return b.String()
+}
+
+// sortedPairs 按键排序输出 {"k"=v,...}，值由 format 转为字符串
+func sortedPairs[V any](m map[string]V, format func(V) string) string {
+	keys := slices.Sorted(maps.Keys(m))
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Quote(k))
+		b.WriteByte('=')
+		b.WriteString(format(m[k]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// schemaCanonicalizer 在一次 flush 内将标识相同的 schema 实例映射到首次出现的实例
+type schemaCanonicalizer struct {
+	byInstance map[SchemaInterface]SchemaInterface
+	byIdentity map[string]SchemaInterface
+}
+
+func (c *schemaCanonicalizer) canonical(schema SchemaInterface) SchemaInterface {
+	if rep, ok := c.byInstance[schema]; ok {
+		return rep
+	}
+	rep := schema
+	if is, ok := schema.(IdentifiableSchema); ok {
+		if c.byIdentity == nil {
+			c.byIdentity = make(map[string]SchemaInterface)
+		}
+		id := is.Identity()
+		if first, ok := c.byIdentity[id]; ok {
+			rep = first
+		} else {
+			c.byIdentity[id] = schema
+		}
+	}
+	if c.byInstance == nil {
+		c.byInstance = make(map[SchemaInterface]SchemaInterface)
+	}
+	c.byInstance[schema] = rep
+	return rep
+}
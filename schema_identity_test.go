@@ -0,0 +1,88 @@
+package batchflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func submitTwoSchemaInstances(t *testing.T, groupByIdentity bool, second *batchflow.SQLSchema) []int {
+	t.Helper()
+	ctx := context.Background()
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:             100,
+		FlushSize:              6,
+		FlushInterval:          time.Hour,
+		GroupSchemasByIdentity: groupByIdentity,
+	})
+	first := batchflow.NewSQLSchema("test_table", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+	for i := 0; i < 3; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(first).SetInt64("id", int64(i)).SetString("name", "a")); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+		if err := b.Submit(ctx, batchflow.NewRequest(second).SetInt64("id", int64(i+10)).SetString("name", "b")); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	// 恰好满批时 flush 异步触发，Close 不等待它完成
+	waitUntilEmpty(t, b)
+	b.Close()
+
+	var sizes []int
+	for _, batch := range mock.SnapshotExecutedBatches() {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func TestBatchFlow_GroupSchemasByIdentityMergesIdenticalInstances(t *testing.T) {
+	identical := batchflow.NewSQLSchema("test_table", batchflow.ConflictIgnoreOperationConfig, "id", "name")
+
+	if sizes := submitTwoSchemaInstances(t, false, identical); len(sizes) != 2 {
+		t.Fatalf("expected pointer grouping to produce 2 batches, got %v", sizes)
+	}
+	if sizes := submitTwoSchemaInstances(t, true, identical); len(sizes) != 1 || sizes[0] != 6 {
+		t.Fatalf("expected identity grouping to merge into one batch of 6, got %v", sizes)
+	}
+}
+
+func TestBatchFlow_GroupSchemasByIdentityKeepsDifferentConfigsApart(t *testing.T) {
+	differentConflict := batchflow.NewSQLSchema("test_table", batchflow.ConflictUpdateOperationConfig, "id", "name")
+	if sizes := submitTwoSchemaInstances(t, true, differentConflict); len(sizes) != 2 {
+		t.Fatalf("schemas with different conflict config must not merge, got %v", sizes)
+	}
+	reordered := batchflow.NewSQLSchema("test_table", batchflow.ConflictIgnoreOperationConfig, "name", "id")
+	if sizes := submitTwoSchemaInstances(t, true, reordered); len(sizes) != 2 {
+		t.Fatalf("schemas with different column order must not merge, got %v", sizes)
+	}
+}
+
+func TestSQLSchema_IdentityIsDeterministic(t *testing.T) {
+	newSchema := func() *batchflow.SQLSchema {
+		cfg := batchflow.ConflictUpdateOperationConfig.
+			WithConflictColumns("id").
+			WithColumnTypeHints(map[string]string{"payload": "jsonb", "tags": "text[]", "created_at": "timestamptz", "id": "bigint"})
+		defaults := map[string]any{"created_at": batchflow.DefaultExpr("NOW()"), "tags": "none", "payload": "{}"}
+		return batchflow.NewSQLSchemaWithDefaults("events", cfg, defaults, "id", "payload", "tags", "created_at")
+	}
+	want := newSchema().Identity()
+	for i := 0; i < 20; i++ {
+		if got := newSchema().Identity(); got != want {
+			t.Fatalf("identity differs between identical schemas:\n%s\n%s", got, want)
+		}
+	}
+
+	partitioned := func() *batchflow.SQLSchema {
+		cfg := batchflow.ConflictIgnoreOperationConfig.WithPartitionFunc(func(map[string]any) string { return "" })
+		return batchflow.NewSQLSchema("events", cfg, "id")
+	}
+	a, b := partitioned(), partitioned()
+	if a.Identity() != a.Identity() {
+		t.Fatalf("identity of a partitioned schema must be stable")
+	}
+	if a.Identity() == b.Identity() {
+		t.Fatalf("schemas with PartitionFunc must not share an identity")
+	}
+}
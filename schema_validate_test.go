@@ -0,0 +1,56 @@
+package batchflow_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestNewSQLSchemaE_RejectsInvalidSchemas(t *testing.T) {
+	cases := []struct {
+		name    string
+		table   string
+		columns []string
+		want    error
+		column  string
+	}{
+		{name: "empty table", table: "", columns: []string{"id"}, want: batchflow.ErrEmptySchemaName},
+		{name: "no columns", table: "users", columns: nil, want: batchflow.ErrMissingColumn},
+		{name: "empty column", table: "users", columns: []string{"id", ""}, want: batchflow.ErrInvalidSchema},
+		{name: "duplicate column", table: "users", columns: []string{"id", "name", "id"}, want: batchflow.ErrInvalidSchema, column: "id"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			schema, err := batchflow.NewSQLSchemaE(tc.table, batchflow.DefaultOperationConfig, tc.columns...)
+			if schema != nil {
+				t.Fatalf("expected nil schema on error, got %+v", schema)
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+			if tc.column != "" {
+				var colErr *batchflow.ColumnError
+				if !errors.As(err, &colErr) || colErr.Column != tc.column {
+					t.Fatalf("expected ColumnError for column %q, got %v", tc.column, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSQLSchemaE_AcceptsValidSchema(t *testing.T) {
+	schema, err := batchflow.NewSQLSchemaE("users", batchflow.DefaultOperationConfig, "id", "name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if schema.Name() != "users" || len(schema.Columns()) != 2 {
+		t.Fatalf("unexpected schema: %s %v", schema.Name(), schema.Columns())
+	}
+
+	// NewSQLSchema 保持兼容：不校验，由 Validate 显式检查
+	legacy := batchflow.NewSQLSchema("users", batchflow.DefaultOperationConfig, "id", "id")
+	if err := legacy.Validate(); !errors.Is(err, batchflow.ErrInvalidSchema) {
+		t.Fatalf("expected Validate to report duplicate column, got %v", err)
+	}
+}
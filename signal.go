@@ -0,0 +1,87 @@
+package batchflow
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultSignalDrainTimeout DrainOnSignal 默认的排空等待时长
+const DefaultSignalDrainTimeout = 30 * time.Second
+
+// DrainOnSignal 安装信号处理：收到任一信号后调用 flow.Close() 排空缓冲数据，并等待信号前已触发的异步批次完成，
+// 最长等待 DefaultSignalDrainTimeout。未指定信号时监听 os.Interrupt 与 SIGTERM。
+// 返回的 cancel 用于卸载信号处理（不会关闭 flow）。flow.Done() 只表示后台管道已退出，此时异步批次可能仍在执行；
+// 进程退出前应在 <-flow.Done() 之后再调用 flow.WaitUntilEmpty(ctx) 等待排空结束。
+func DrainOnSignal(flow *BatchFlow, sigs ...os.Signal) (cancel func()) {
+	return DrainOnSignalWithTimeout(flow, DefaultSignalDrainTimeout, sigs...)
+}
+
+// DrainOnSignalWithTimeout 同 DrainOnSignal，可指定排空超时（Close 与等待异步批次合计）；timeout <= 0 表示一直等待排空结束。
+// 超时后不再等待，剩余的 flush 仍在后台继续。
+func DrainOnSignalWithTimeout(flow *BatchFlow, timeout time.Duration, sigs ...os.Signal) (cancel func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	stop := make(chan struct{})
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(stop)
+		})
+	}
+
+	go func() {
+		select {
+		case sig := <-ch:
+			cancel()
+			flow.Logger().Info("batchflow draining on signal", "signal", sig.String(), "timeout", timeout)
+			if err := closeWithTimeout(flow, timeout); err != nil {
+				flow.Logger().Warn("batchflow drain on signal incomplete", "signal", sig.String(), "error", err)
+				return
+			}
+			flow.Logger().Info("batchflow drained on signal", "signal", sig.String())
+		case <-stop:
+		}
+	}()
+	return cancel
+}
+
+// errDrainTimeout 排空超时
+type errDrainTimeout struct{ timeout time.Duration }
+
+func (e errDrainTimeout) Error() string { return "drain timed out after " + e.timeout.String() }
+
+// closeWithTimeout 关闭 flow 并等待在途的异步批次：Close 只等待后台管道退出，信号前触发的满批 flush 可能仍在执行
+func closeWithTimeout(flow *BatchFlow, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	done := make(chan error, 1)
+	go func() { done <- flow.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return errDrainTimeout{timeout: timeout}
+	}
+	if err := flow.WaitUntilEmpty(ctx); err != nil {
+		if ctx.Err() != nil {
+			return errDrainTimeout{timeout: timeout}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+//go:build unix
+
+package batchflow_test
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestDrainOnSignal_FlushesBufferedRequests(t *testing.T) {
+	ctx := context.Background()
+	// FlushInterval 足够长，确保数据只能通过信号触发的 Close 被排空
+	b, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    64,
+		FlushSize:     1000,
+		FlushInterval: time.Hour,
+	})
+	cancel := batchflow.DrainOnSignalWithTimeout(b, 5*time.Second, syscall.SIGUSR1)
+	defer cancel()
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 10; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("find process: %v", err)
+	}
+	if err := proc.Signal(syscall.SIGUSR1); err != nil {
+		t.Fatalf("send signal: %v", err)
+	}
+
+	select {
+	case <-b.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("flow did not drain after signal")
+	}
+
+	// Close 不等待异步 flush 完成，轮询执行结果
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rows := 0
+		for _, batch := range mock.SnapshotExecutedBatches() {
+			rows += len(batch)
+		}
+		if rows == 10 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 10 drained rows, got %d", rows)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", 99)); err == nil {
+		t.Fatal("expected submit after drain to be rejected")
+	}
+}
+
+func TestDrainOnSignal_CancelUninstallsHandler(t *testing.T) {
+	ctx := context.Background()
+	b, _ := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    8,
+		FlushSize:     8,
+		FlushInterval: time.Hour,
+	})
+	defer b.Close()
+
+	cancel := batchflow.DrainOnSignal(b, syscall.SIGUSR2)
+	cancel()
+	cancel() // 幂等
+
+	select {
+	case <-b.Done():
+		t.Fatal("flow should not be closed by cancel")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDrainOnSignal_WaitsForInflightAsyncFlushes(t *testing.T) {
+	ctx := context.Background()
+	logger := &capturingLogger{}
+	// 满批（2 行）异步执行且较慢：信号触发的 Close 返回时它们仍在执行
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 16, FlushSize: 2, FlushInterval: time.Hour, Logger: logger},
+		Executor: sizeDelayExecutor{slowSize: 2, delay: 200 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+	var flushed atomic.Int64
+	b.WithAfterFlush(func(_ context.Context, _ batchflow.SchemaInterface, n int, err error) {
+		if err == nil {
+			flushed.Add(int64(n))
+		}
+	})
+	cancel := batchflow.DrainOnSignalWithTimeout(b, 5*time.Second, syscall.SIGUSR1)
+	defer cancel()
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	for i := 0; i < 5; i++ {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("find process: %v", err)
+	}
+	if err := proc.Signal(syscall.SIGUSR1); err != nil {
+		t.Fatalf("send signal: %v", err)
+	}
+
+	drained := func() bool {
+		for _, entry := range logger.byLevel("info") {
+			if entry.msg == "batchflow drained on signal" {
+				return true
+			}
+		}
+		return false
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !drained() {
+		if time.Now().After(deadline) {
+			t.Fatalf("drain did not complete, warnings: %v", logger.byLevel("warn"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := flushed.Load(); n != 5 {
+		t.Fatalf("expected all 5 rows flushed when the drain completes, got %d", n)
+	}
+}
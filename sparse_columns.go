@@ -0,0 +1,70 @@
+package batchflow
+
+import (
+	"slices"
+	"strings"
+)
+
+// schemaPartition 一次 ExecuteBatch 的单元：schema 与其请求
+type schemaPartition struct {
+	schema   SchemaInterface
+	requests []*Request
+}
+
+// sparsePartitions 对启用 SparseColumns 的 SQLSchema，按“已赋值列集合”（含 schema 默认值列）拆分请求，
+// 每个集合使用投影后的 schema 单独执行，从而每种列组合生成一条语句；保持首次出现顺序。
+// 其他 schema 原样返回单个分区。
+func sparsePartitions(schema SchemaInterface, requests []*Request) []schemaPartition {
+	sqlSchema, ok := schema.(*SQLSchema)
+	if !ok || !sqlSchema.operationConfig.SparseColumns {
+		return []schemaPartition{{schema: schema, requests: requests}}
+	}
+	columns := sqlSchema.Columns()
+	var (
+		partitions []schemaPartition
+		index      = make(map[string]int)
+		populated  = make([]string, 0, len(columns))
+	)
+	for _, request := range requests {
+		populated = populated[:0]
+		for _, col := range columns {
+			if _, ok := request.columns[col]; ok {
+				populated = append(populated, col)
+			} else if _, ok := sqlSchema.defaults[col]; ok {
+				populated = append(populated, col)
+			}
+		}
+		key := strings.Join(populated, "\x00")
+		i, exists := index[key]
+		if !exists {
+			i = len(partitions)
+			index[key] = i
+			partitions = append(partitions, schemaPartition{schema: sqlSchema.project(slices.Clone(populated))})
+		}
+		partitions[i].requests = append(partitions[i].requests, request)
+	}
+	return partitions
+}
+
+// project 返回仅包含给定列的 schema 副本：冲突键固定为原 schema 的冲突键，UpdateColumns 裁剪到投影内
+// （裁剪为空时 ConflictUpdate 退化为 ConflictIgnore）
+func (s *SQLSchema) project(columns []string) *SQLSchema {
+	config := s.operationConfig
+	config.ConflictColumns = sqlConflictColumns(s)
+	if len(config.UpdateColumns) > 0 {
+		config.UpdateColumns = slices.DeleteFunc(slices.Clone(config.UpdateColumns), func(col string) bool {
+			return !slices.Contains(columns, col)
+		})
+		// 限定的更新列均未赋值：没有可更新的列，冲突时保留已有行
+		if len(config.UpdateColumns) == 0 && config.ConflictStrategy == ConflictUpdate {
+			config.ConflictStrategy = ConflictIgnore
+		}
+	}
+	return &SQLSchema{
+		Schema:          NewSchema(s.Name(), columns...),
+		operationConfig: config,
+		dbColumns:       s.dbColumns,
+		defaults:        s.defaults,
+		source:          s.Source(),
+	}
+}
@@ -0,0 +1,106 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_SparseColumnsEmitsStatementPerColumnSet(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:batchflow_sparse_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE profiles (id INTEGER PRIMARY KEY, name TEXT NOT NULL DEFAULT 'anonymous', status TEXT NOT NULL DEFAULT 'active')"); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+
+	var (
+		mu         sync.Mutex
+		statements []string
+	)
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).
+		WithStatementSampler(1, func(query string, _ int, _ time.Duration) {
+			mu.Lock()
+			statements = append(statements, query)
+			mu.Unlock()
+		})
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 3, FlushInterval: time.Hour},
+		Executor: batchflow.NewThrottledBatchExecutor(processor),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+
+	schema := batchflow.NewSQLSchema("profiles", batchflow.ConflictIgnoreOperationConfig.WithSparseColumns(true), "id", "name", "status")
+	requests := []*batchflow.Request{
+		batchflow.NewRequest(schema).SetInt64("id", 1).SetString("name", "alice"),
+		batchflow.NewRequest(schema).SetInt64("id", 2).SetString("status", "disabled"),
+		batchflow.NewRequest(schema).SetInt64("id", 3).SetString("name", "carol"),
+	}
+	for _, r := range requests {
+		if err := r.Validate(); err != nil {
+			t.Fatalf("expected sparse request to validate, got %v", err)
+		}
+		if err := b.Submit(ctx, r); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	var count int
+	for {
+		if err := db.QueryRow("SELECT COUNT(*) FROM profiles").Scan(&count); err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		if count == 3 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 rows, got %d", count)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(statements) != 2 {
+		t.Fatalf("expected 2 statements, got %d: %v", len(statements), statements)
+	}
+	if !strings.Contains(statements[0], "(id, name)") || !strings.Contains(statements[1], "(id, status)") {
+		t.Fatalf("expected per-column-set statements, got %v", statements)
+	}
+
+	rows := map[int64][2]string{}
+	result, err := db.Query("SELECT id, name, status FROM profiles")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer result.Close()
+	for result.Next() {
+		var (
+			id           int64
+			name, status string
+		)
+		if err := result.Scan(&id, &name, &status); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		rows[id] = [2]string{name, status}
+	}
+	if rows[1] != [2]string{"alice", "active"} || rows[2] != [2]string{"anonymous", "disabled"} {
+		t.Fatalf("expected database defaults for omitted columns, got %v", rows)
+	}
+}
@@ -0,0 +1,39 @@
+package batchflow
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// sqlExecer 执行数据语句的连接来源：*sql.DB（默认）或 WithMaxConnWait 预先取得的 *sql.Conn
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// WithMaxConnWait 设置执行前获取连接的最长等待：连接池耗尽时 d 内拿不到连接则立即返回包装 ErrPoolExhausted 的
+// 执行阶段错误（重试原因 pool_exhausted，可重试），而不是阻塞到批次超时。取得的连接用于本批全部语句。
+// d <= 0 表示关闭（默认），语句直接在 *sql.DB 上执行。
+func (bp *SQLBatchProcessor) WithMaxConnWait(d time.Duration) *SQLBatchProcessor {
+	bp.maxConnWait = d
+	return bp
+}
+
+// acquireExecer 返回本批使用的执行对象与释放函数
+func (bp *SQLBatchProcessor) acquireExecer(ctx context.Context) (sqlExecer, func(), error) {
+	if bp.maxConnWait <= 0 {
+		return bp.db, func() {}, nil
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, bp.maxConnWait)
+	defer cancel()
+	conn, err := bp.db.Conn(waitCtx)
+	if err != nil {
+		if ctx.Err() == nil && waitCtx.Err() != nil {
+			err = fmt.Errorf("%w: no connection within %s", ErrPoolExhausted, bp.maxConnWait)
+		}
+		return nil, nil, &SQLError{Stage: SQLStageExecute, Cause: err}
+	}
+	return conn, func() { _ = conn.Close() }, nil
+}
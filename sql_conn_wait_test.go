@@ -0,0 +1,54 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestSQLBatchProcessor_MaxConnWaitFailsFastOnPoolExhaustion(t *testing.T) {
+	d := &execModeDriver{}
+	db := sql.OpenDB(execModeConnector{d: d})
+	t.Cleanup(func() { _ = db.Close() })
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	held, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("acquire connection: %v", err)
+	}
+
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultPostgreSQLDriver).
+		WithTimeout(5 * time.Second).
+		WithMaxConnWait(30 * time.Millisecond)
+	executor := batchflow.NewThrottledBatchExecutor(processor)
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+
+	start := time.Now()
+	err = executor.ExecuteBatch(ctx, schema, []map[string]any{{"id": 1}})
+	elapsed := time.Since(start)
+	if !errors.Is(err, batchflow.ErrPoolExhausted) {
+		t.Fatalf("expected ErrPoolExhausted, got %v", err)
+	}
+	if elapsed > time.Second {
+		t.Fatalf("expected fast failure, took %s", elapsed)
+	}
+	if retryable, reason := batchflow.ClassifyError(err); !retryable || reason != batchflow.ErrorReasonPoolExhausted {
+		t.Fatalf("expected retryable pool_exhausted, got %v %q", retryable, reason)
+	}
+	if len(d.queries) != 0 {
+		t.Fatalf("expected no statement to run, got %v", d.queries)
+	}
+
+	_ = held.Close()
+	if err := executor.ExecuteBatch(ctx, schema, []map[string]any{{"id": 2}}); err != nil {
+		t.Fatalf("expected success once a connection is free, got %v", err)
+	}
+	if len(d.queries) != 1 {
+		t.Fatalf("expected one statement after release, got %d", len(d.queries))
+	}
+}
@@ -0,0 +1,231 @@
+package batchflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SQLDeleteGenerator 可选扩展接口：SQL 驱动为 MarkDelete 标记的请求生成按冲突键删除的语句。
+// 内置的 MySQL/PostgreSQL/SQLite/DuckDB/Mock 驱动均已实现；未实现的自定义驱动遇到删除段时返回包装 ErrDeleteUnsupported 的生成错误。
+type SQLDeleteGenerator interface {
+	GenerateDeleteSQL(ctx context.Context, schema *SQLSchema, data []map[string]any) (sql string, args []any, err error)
+}
+
+// MarkDelete 将请求标记为删除（墓碑写入）：flush 时与插入分开，按 schema 的冲突键（未配置 ConflictColumns 时为首列）
+// 生成 DELETE 语句。删除请求只需设置冲突键列；仅 SQLSchema 支持，其他 schema 在 Submit/Validate 时返回 ErrDeleteUnsupported。
+func (r *Request) MarkDelete() *Request {
+	r.deleteMarked = true
+	return r
+}
+
+// IsDelete 返回请求是否通过 MarkDelete 标记为删除
+func (r *Request) IsDelete() bool {
+	return r.deleteMarked
+}
+
+// checkDeleteSupported 删除标记仅对 SQLSchema 生效，避免其他后端静默地把删除当作写入
+func (r *Request) checkDeleteSupported() error {
+	if !r.deleteMarked {
+		return nil
+	}
+	if _, ok := r.schema.(*SQLSchema); ok {
+		return nil
+	}
+	return &SchemaError{SchemaName: r.schema.Name(), Reason: "delete-marked requests require a SQLSchema", Err: ErrDeleteUnsupported}
+}
+
+// validateDelete 删除请求只要求冲突键列已赋值
+func (r *Request) validateDelete() error {
+	if err := r.checkDeleteSupported(); err != nil {
+		return err
+	}
+	for _, colName := range sqlConflictColumns(r.schema.(*SQLSchema)) {
+		if _, exists := r.columns[colName]; !exists {
+			return fmt.Errorf("missing required column: %s", colName)
+		}
+	}
+	return nil
+}
+
+// IsDelete 返回 schema 是否为删除段副本：flush 时 MarkDelete 标记的连续请求以该副本交给执行器，
+// 每行只包含冲突键列。自定义执行器应据此按键删除，而不是把这些行当作写入。
+func (s *SQLSchema) IsDelete() bool {
+	return s.deleteRows
+}
+
+// isDeleteSchema 判断执行器收到的 schema 是否为删除段
+func isDeleteSchema(schema SchemaInterface) bool {
+	s, ok := schema.(*SQLSchema)
+	return ok && s.deleteRows
+}
+
+// forDelete 返回删除段使用的 schema 副本：列固定为冲突键，生成阶段改走 GenerateDeleteSQL
+func (s *SQLSchema) forDelete() *SQLSchema {
+	keys := sqlConflictColumns(s)
+	config := s.operationConfig
+	config.ConflictColumns = keys
+	return &SQLSchema{
+		Schema:          NewSchema(s.Name(), keys...),
+		operationConfig: config,
+		dbColumns:       s.dbColumns,
+		defaults:        s.defaults,
+		deleteRows:      true,
+		source:          s.Source(),
+	}
+}
+
+// deletePartitions 按提交顺序把请求切分为连续的插入段与删除段（先插后删与先删后插的结果不同，不能简单地两两合并）；
+// 没有删除请求时原样返回单个分区
+func deletePartitions(schema SchemaInterface, requests []*Request) []schemaPartition {
+	sqlSchema, ok := schema.(*SQLSchema)
+	if !ok || !hasDeleteRequest(requests) {
+		return []schemaPartition{{schema: schema, requests: requests}}
+	}
+	var (
+		partitions []schemaPartition
+		deleteOnly *SQLSchema
+	)
+	for i := 0; i < len(requests); {
+		j := i + 1
+		for j < len(requests) && requests[j].deleteMarked == requests[i].deleteMarked {
+			j++
+		}
+		partition := schemaPartition{schema: sqlSchema, requests: requests[i:j]}
+		if requests[i].deleteMarked {
+			if deleteOnly == nil {
+				deleteOnly = sqlSchema.forDelete()
+			}
+			partition.schema = deleteOnly
+		}
+		partitions = append(partitions, partition)
+		i = j
+	}
+	return partitions
+}
+
+func hasDeleteRequest(requests []*Request) bool {
+	for _, request := range requests {
+		if request.deleteMarked {
+			return true
+		}
+	}
+	return false
+}
+
+// generateSQLStatement 删除段调用驱动的 GenerateDeleteSQL，其余生成插入语句
+func generateSQLStatement(ctx context.Context, driver SQLDriver, schema *SQLSchema, data []map[string]any) (string, []any, error) {
+	if !schema.IsDelete() {
+		return driver.GenerateInsertSQL(ctx, schema, data)
+	}
+	deleter, ok := driver.(SQLDeleteGenerator)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: driver %T does not implement GenerateDeleteSQL", ErrDeleteUnsupported, driver)
+	}
+	return deleter.GenerateDeleteSQL(ctx, schema, data)
+}
+
+// sqlDeleteStatement 生成 DELETE FROM table WHERE key IN (...)：单键列为 col IN (?, ?)，
+// 复合键使用行值构造 (a, b) IN ((?, ?), (?, ?))。批内重复的键只保留一次；键为 nil 或 SQL 内联表达式时报错。
+func sqlDeleteStatement(ctx context.Context, schema *SQLSchema, table string, keyIdents []string, data []map[string]any, placeholder func(argIndex int) string, bind sqlArgBinder) (string, []any, error) {
+	if len(data) == 0 {
+		return "", nil, nil
+	}
+	keys := sqlConflictColumns(schema)
+	if len(keys) == 0 {
+		return "", nil, errors.New("no conflict columns defined for delete")
+	}
+	composite := len(keys) > 1
+	seen := make(map[string]struct{}, len(data))
+	args := make([]any, 0, len(data)*len(keys))
+	var sb strings.Builder
+	for _, row := range data {
+		if ctx.Err() != nil {
+			return "", nil, ctx.Err()
+		}
+		key, ok := dedupKey(row, keys)
+		if !ok {
+			return "", nil, fmt.Errorf("delete key columns %s must not be nil or SQL expressions", strings.Join(keys, ", "))
+		}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		if len(args) > 0 {
+			sb.WriteString(", ")
+		}
+		if composite {
+			sb.WriteByte('(')
+		}
+		for j, col := range keys {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			value, err := bind(col, row[col])
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, value)
+			sb.WriteString(placeholder(len(args)))
+		}
+		if composite {
+			sb.WriteByte(')')
+		}
+	}
+	target := keyIdents[0]
+	if composite {
+		target = "(" + strings.Join(keyIdents, ", ") + ")"
+	}
+	return fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", table, target, sb.String()), args, nil
+}
+
+func questionDeletePlaceholder(int) string { return "?" }
+
+func dollarDeletePlaceholder(argIndex int) string { return fmt.Sprintf("$%d", argIndex) }
+
+var (
+	_ SQLDeleteGenerator = (*MySQLDriver)(nil)
+	_ SQLDeleteGenerator = (*PostgreSQLDriver)(nil)
+	_ SQLDeleteGenerator = (*SQLiteDriver)(nil)
+	_ SQLDeleteGenerator = (*DuckDBDriver)(nil)
+	_ SQLDeleteGenerator = (*MockDriver)(nil)
+)
+
+// GenerateDeleteSQL 生成MySQL按冲突键批量删除SQL（遵循 WithMySQLQuotedIdentifiers）
+func (d *MySQLDriver) GenerateDeleteSQL(ctx context.Context, schema *SQLSchema, data []map[string]any) (string, []any, error) {
+	return sqlDeleteStatement(ctx, schema, d.table(schema), d.identifiers(schema, sqlConflictColumns(schema)), data, questionDeletePlaceholder, rejectSQLArrayArg("mysql"))
+}
+
+// GenerateDeleteSQL 生成PostgreSQL按冲突键批量删除SQL
+func (d *PostgreSQLDriver) GenerateDeleteSQL(ctx context.Context, schema *SQLSchema, data []map[string]any) (string, []any, error) {
+	return sqlDeleteStatement(ctx, schema, schema.Name(), schema.dbColumnNames(sqlConflictColumns(schema)), data, dollarDeletePlaceholder, bindPostgreSQLArrayArg)
+}
+
+// GenerateDeleteSQL 生成SQLite按冲突键批量删除SQL（复合键的行值比较需要 SQLite 3.15+）
+func (d *SQLiteDriver) GenerateDeleteSQL(ctx context.Context, schema *SQLSchema, data []map[string]any) (string, []any, error) {
+	return sqlDeleteStatement(ctx, schema, schema.Name(), schema.dbColumnNames(sqlConflictColumns(schema)), data, questionDeletePlaceholder, rejectSQLArrayArg("sqlite"))
+}
+
+// GenerateDeleteSQL 生成DuckDB按冲突键批量删除SQL
+func (d *DuckDBDriver) GenerateDeleteSQL(ctx context.Context, schema *SQLSchema, data []map[string]any) (string, []any, error) {
+	return sqlDeleteStatement(ctx, schema, schema.Name(), schema.dbColumnNames(sqlConflictColumns(schema)), data, questionDeletePlaceholder, rejectSQLArrayArg("duckdb"))
+}
+
+// GenerateDeleteSQL 生成模拟删除SQL：postgresql 使用 $n 占位符，其余类型使用 ?；同样受 FailGeneration 影响
+func (d *MockDriver) GenerateDeleteSQL(ctx context.Context, schema *SQLSchema, data []map[string]any) (string, []any, error) {
+	if len(data) == 0 {
+		return "", nil, nil
+	}
+	d.mu.RLock()
+	failErr := d.failErr
+	d.mu.RUnlock()
+	if failErr != nil {
+		return "", nil, failErr
+	}
+	placeholder := questionDeletePlaceholder
+	if d.databaseType == "postgresql" {
+		placeholder = dollarDeletePlaceholder
+	}
+	return sqlDeleteStatement(ctx, schema, schema.Name(), schema.dbColumnNames(sqlConflictColumns(schema)), data, placeholder, d.bindArg)
+}
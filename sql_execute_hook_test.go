@@ -0,0 +1,54 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestSQLBatchProcessor_ExecuteHookObservesStatement(t *testing.T) {
+	db, d := openFlakyDB(t, 0)
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "email")
+	data := []map[string]any{{"id": 1, "email": "a@example.com"}, {"id": 2, "email": "b@example.com"}}
+
+	var seenSQL string
+	var seenArgs []any
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultMySQLDriver).
+		WithExecuteHook(func(ctx context.Context, sql string, args []any, next func() error) error {
+			seenSQL, seenArgs = sql, args
+			return next()
+		})
+	if err := batchflow.NewThrottledBatchExecutor(processor).ExecuteBatch(context.Background(), schema, data); err != nil {
+		t.Fatalf("ExecuteBatch failed: %v", err)
+	}
+	if !strings.HasPrefix(seenSQL, "INSERT IGNORE INTO users (id, email)") || len(seenArgs) != 4 || seenArgs[1] != "a@example.com" {
+		t.Fatalf("hook saw unexpected statement: %q %v", seenSQL, seenArgs)
+	}
+	if got := d.execs.Load(); got != 1 {
+		t.Fatalf("expected next() to reach the database once, got %d execs", got)
+	}
+}
+
+func TestSQLBatchProcessor_ExecuteHookInjectsFailure(t *testing.T) {
+	db, d := openFlakyDB(t, 0)
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	injected := errors.New("rate limited")
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultMySQLDriver).
+		WithExecuteHook(func(context.Context, string, []any, func() error) error {
+			return injected // 不调用 next：短路
+		})
+	err := batchflow.NewThrottledBatchExecutor(processor).ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}})
+	if !errors.Is(err, injected) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	var sqlErr *batchflow.SQLError
+	if !errors.As(err, &sqlErr) || sqlErr.Stage != batchflow.SQLStageExecute {
+		t.Fatalf("expected execute-stage SQLError, got %v", err)
+	}
+	if got := d.execs.Load(); got != 0 {
+		t.Fatalf("short-circuited hook should skip the database, got %d execs", got)
+	}
+}
@@ -0,0 +1,43 @@
+package batchflow_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestSQLExpr_InlinedIntoValuesAndExcludedFromArgs(t *testing.T) {
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id", "payload", "created_at")
+	req1 := batchflow.NewRequest(schema).
+		Set("id", batchflow.SQLExpr("UUID()")).
+		SetString("payload", "a").
+		Set("created_at", batchflow.SQLExpr("CURRENT_TIMESTAMP"))
+	req2 := batchflow.NewRequest(schema).
+		Set("id", batchflow.SQLExpr("UUID()")).
+		SetString("payload", "b").
+		Set("created_at", batchflow.SQLExpr("CURRENT_TIMESTAMP"))
+	rows := []map[string]any{req1.Columns(), req2.Columns()}
+
+	cases := []struct {
+		driver batchflow.SQLDriver
+		values string
+	}{
+		{batchflow.DefaultMySQLDriver, "(UUID(), ?, CURRENT_TIMESTAMP), (UUID(), ?, CURRENT_TIMESTAMP)"},
+		{batchflow.DefaultPostgreSQLDriver, "(UUID(), $1, CURRENT_TIMESTAMP), (UUID(), $2, CURRENT_TIMESTAMP)"},
+		{batchflow.DefaultSQLiteDriver, "(UUID(), ?, CURRENT_TIMESTAMP), (UUID(), ?, CURRENT_TIMESTAMP)"},
+	}
+	for _, tc := range cases {
+		sql, args, err := tc.driver.GenerateInsertSQL(context.Background(), schema, rows)
+		if err != nil {
+			t.Fatalf("%T: generate failed: %v", tc.driver, err)
+		}
+		if !strings.Contains(sql, tc.values) {
+			t.Fatalf("%T: expected inline expressions %q in %q", tc.driver, tc.values, sql)
+		}
+		if len(args) != 2 || args[0] != "a" || args[1] != "b" {
+			t.Fatalf("%T: expected only payload args, got %v", tc.driver, args)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/rushairer/batchflow/v2"
+)
+
+// flakyConnDriver 模拟数据库重启后的失效连接：前 failExecs 次 Exec 返回 invalid connection
+type flakyConnDriver struct {
+	failExecs atomic.Int32
+	execs     atomic.Int32
+	pings     atomic.Int32
+}
+
+func (d *flakyConnDriver) Open(string) (driver.Conn, error) { return &flakyConn{d: d}, nil }
+
+type flakyConn struct{ d *flakyConnDriver }
+
+func (c *flakyConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *flakyConn) Close() error                        { return nil }
+func (c *flakyConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *flakyConn) Ping(context.Context) error {
+	c.d.pings.Add(1)
+	return nil
+}
+
+func (c *flakyConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	c.d.execs.Add(1)
+	if c.d.failExecs.Add(-1) >= 0 {
+		return nil, mysqlDriver.ErrInvalidConn
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func openFlakyDB(t *testing.T, failExecs int32) (*sql.DB, *flakyConnDriver) {
+	t.Helper()
+	d := &flakyConnDriver{}
+	d.failExecs.Store(failExecs)
+	db := sql.OpenDB(flakyConnector{d: d})
+	t.Cleanup(func() { _ = db.Close() })
+	return db, d
+}
+
+type flakyConnector struct{ d *flakyConnDriver }
+
+func (c flakyConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c flakyConnector) Driver() driver.Driver                        { return c.d }
+
+func TestClassifyError_BadConnRetryable(t *testing.T) {
+	for _, err := range []error{driver.ErrBadConn, mysqlDriver.ErrInvalidConn} {
+		retryable, reason := batchflow.ClassifyError(&batchflow.SQLError{Stage: batchflow.SQLStageExecute, Cause: err})
+		if !retryable || reason != batchflow.ErrorReasonConnection {
+			t.Fatalf("%v: expected retryable connection error, got retryable=%v reason=%s", err, retryable, reason)
+		}
+	}
+}
+
+func TestSQLBatchProcessor_HealthCheckThenRetrySucceeds(t *testing.T) {
+	db, d := openFlakyDB(t, 1)
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultMySQLDriver).WithHealthCheck(time.Nanosecond)
+	executor := batchflow.NewThrottledBatchExecutor(processor).WithRetryConfig(batchflow.RetryConfig{
+		Enabled:     true,
+		MaxAttempts: 3,
+		BackoffBase: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id")
+	err := executor.ExecuteBatch(context.Background(), schema, []map[string]any{{"id": 1}})
+	if err != nil {
+		t.Fatalf("expected retry after bad connection to succeed, got %v", err)
+	}
+	if got := d.execs.Load(); got != 2 {
+		t.Fatalf("expected 2 exec attempts, got %d", got)
+	}
+	if got := d.pings.Load(); got < 2 {
+		t.Fatalf("expected a health check ping before each idle attempt, got %d", got)
+	}
+}
+
+func TestSQLBatchProcessor_HealthCheckSkippedWhenRecentlyUsed(t *testing.T) {
+	db, d := openFlakyDB(t, 0)
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultMySQLDriver).WithHealthCheck(time.Hour)
+	ops := batchflow.Operations{"INSERT INTO users (id) VALUES (?)", 1}
+
+	for i := 0; i < 3; i++ {
+		if err := processor.ExecuteOperations(context.Background(), ops); err != nil {
+			t.Fatalf("exec %d failed: %v", i, err)
+		}
+	}
+	if got := d.pings.Load(); got != 1 {
+		t.Fatalf("expected only the first execution to ping, got %d pings", got)
+	}
+}
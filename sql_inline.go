@@ -0,0 +1,52 @@
+package batchflow
+
+import (
+	"strings"
+)
+
+// inlineSQLValue 直接写入 VALUES 元组的 SQL 片段，不生成占位符、不进入 args
+type inlineSQLValue interface {
+	inlineSQL() string
+}
+
+// DefaultExpr 作为 NewSQLSchemaWithDefaults 的默认值时，按 SQL 表达式内联到 VALUES 中，
+// 例如 DefaultExpr("DEFAULT")、DefaultExpr("NOW()")、DefaultExpr("CURRENT_TIMESTAMP")。
+// 表达式不做任何转义，且方言相关（SQLite 不支持 VALUES 中的 DEFAULT 关键字），只应使用常量。
+type DefaultExpr string
+
+func (e DefaultExpr) inlineSQL() string { return string(e) }
+
+// SQLExpr 作为请求列值时，SQL 驱动将其原样内联到 VALUES 元组（不生成占位符、不计入 args），
+// 用于逐行的 SQL 常量或函数，例如 Request.Set("id", SQLExpr("UUID()"))、SQLExpr("CURRENT_TIMESTAMP")。
+// 警告：表达式不做任何转义或校验，切勿拼接用户输入，否则会造成 SQL 注入。
+type SQLExpr string
+
+func (e SQLExpr) inlineSQL() string { return string(e) }
+
+// sqlValuesClause 逐格生成 VALUES 元组：内联值原样写入，其余单元格调用 placeholder 生成占位符。
+// argIndex 从 1 开始，只对参数化单元格递增，与 prepareSQLRowsAndArgs 生成的 args 顺序一致。
+func sqlValuesClause(columns []string, rows []map[string]any, placeholder func(argIndex int, column string) string) string {
+	var sb strings.Builder
+	argIndex := 0
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteByte('(')
+		for j, col := range columns {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			if v, ok := row[col].(inlineSQLValue); ok {
+				sb.WriteString(v.inlineSQL())
+				continue
+			}
+			argIndex++
+			sb.WriteString(placeholder(argIndex, col))
+		}
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
+
+func questionPlaceholder(int, string) string { return "?" }
@@ -29,6 +29,7 @@ type SQLPreview struct {
 	UpdateColumns    []string
 	DedupStats       SQLDedupStats
 	Fingerprint      string
+	Delete           bool
 }
 
 // SQLStage identifies where a SQL batch failed.
@@ -88,7 +89,10 @@ func GenerateSQLPreview(ctx context.Context, driver SQLDriver, schema *SQLSchema
 	}
 
 	stats := analyzeSQLDedup(schema, data)
-	sqlText, args, err := driver.GenerateInsertSQL(ctx, schema, data)
+	sqlText, args, err := generateSQLStatement(ctx, driver, schema, data)
+	if err == nil {
+		err = checkSQLParamLimit(driver, schema, len(args))
+	}
 	preview := SQLPreview{
 		Table:            schema.Name(),
 		SQL:              sqlText,
@@ -100,6 +104,7 @@ func GenerateSQLPreview(ctx context.Context, driver SQLDriver, schema *SQLSchema
 		UpdateColumns:    sqlUpdateColumnsForPreview(schema),
 		DedupStats:       stats,
 		Fingerprint:      FingerprintSQL(sqlText),
+		Delete:           schema.IsDelete(),
 	}
 	if err != nil {
 		return preview, &SQLError{
@@ -141,9 +146,12 @@ func analyzeSQLDedup(schema *SQLSchema, data []map[string]any) SQLDedupStats {
 func (p SQLPreview) OperationPreview() OperationPreview {
 	operation := OperationInsert
 	switch p.ConflictStrategy {
-	case ConflictIgnore, ConflictReplace, ConflictUpdate:
+	case ConflictIgnore, ConflictReplace, ConflictUpdate, ConflictTouch:
 		operation = OperationUpsert
 	}
+	if p.Delete {
+		operation = OperationDelete
+	}
 	return OperationPreview{
 		Backend:     BackendSQL,
 		Operation:   operation,
@@ -171,6 +179,8 @@ func conflictStrategyName(strategy ConflictStrategy) string {
 		return "replace"
 	case ConflictUpdate:
 		return "update"
+	case ConflictTouch:
+		return "touch"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,160 @@
+package batchflow
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// DeadLetterFunc 接收逐行保存点模式下被回滚的行及其错误。
+// row 仅在回调期间有效；如需异步处理请自行拷贝。
+type DeadLetterFunc func(schema SchemaInterface, row map[string]any, err error)
+
+// TaggedDeadLetterFunc 与 DeadLetterFunc 相同，额外携带该行请求通过 Request.WithTag 附加的标签（无标签时为 nil）。
+type TaggedDeadLetterFunc func(schema SchemaInterface, row map[string]any, tags map[string]string, err error)
+
+// 逐行保存点使用的固定名称：同一事务内顺序执行，释放后可复用
+const rowSavepointName = "batchflow_row"
+
+// savepointStatement 单行对应的 INSERT 语句
+type savepointStatement struct {
+	sql  string
+	args []any
+	row  map[string]any
+}
+
+// savepointOperations 逐行保存点模式下 GenerateOperations 产出的唯一操作
+type savepointOperations struct {
+	schema     *SQLSchema
+	statements []savepointStatement
+}
+
+// WithRowSavepoints 启用逐行保存点模式（尽力写入）：整批在一个事务内执行，每行单独生成 INSERT 并包裹在
+// SAVEPOINT 中，失败的行 ROLLBACK TO SAVEPOINT 后继续处理后续行，事务提交成功后再将失败行逐一交给 deadLetter。
+// 该模式逐行执行语句，吞吐明显低于多行 INSERT，且不做批内冲突键合并；事务级错误（BEGIN/COMMIT、回滚保存点失败）
+// 仍按整批失败返回并参与重试。deadLetter 为 nil 时关闭。
+func (bp *SQLBatchProcessor) WithRowSavepoints(deadLetter DeadLetterFunc) *SQLBatchProcessor {
+	if deadLetter == nil {
+		bp.deadLetter = nil
+		return bp
+	}
+	bp.deadLetter = func(schema SchemaInterface, row map[string]any, _ map[string]string, err error) {
+		deadLetter(schema, row, err)
+	}
+	return bp
+}
+
+// WithRowSavepointsTagged 与 WithRowSavepoints 相同，死信回调额外收到失败行对应请求的标签，
+// 便于定位产生该行的逻辑来源。deadLetter 为 nil 时关闭。
+func (bp *SQLBatchProcessor) WithRowSavepointsTagged(deadLetter TaggedDeadLetterFunc) *SQLBatchProcessor {
+	bp.deadLetter = deadLetter
+	return bp
+}
+
+// WithSavepointDeadlockRetries 在逐行保存点模式下，语句因死锁失败（ClassifyError 归为 ErrorReasonDeadlock）时
+// 回滚到该语句前的保存点并在同一事务内重新执行，最多重试 n 次，避免为单条语句回滚整个事务。
+// 重试耗尽后整个事务失败并返回错误（可被批次级 Retry 重试），该行不投递死信。
+// 注意：MySQL InnoDB 的死锁会回滚整个事务，此时回滚保存点失败，同样按整批失败处理。n <= 0 表示关闭（默认，死锁行投递死信）。
+func (bp *SQLBatchProcessor) WithSavepointDeadlockRetries(n int) *SQLBatchProcessor {
+	bp.savepointDeadlockRetries = n
+	return bp
+}
+
+// isDeadlockError 判断错误是否为死锁
+func isDeadlockError(err error) bool {
+	_, reason := ClassifyError(err)
+	return reason == ErrorReasonDeadlock
+}
+
+func (bp *SQLBatchProcessor) generateSavepointOperations(ctx context.Context, schema *SQLSchema, data []map[string]any) (Operations, error) {
+	ops := &savepointOperations{schema: schema, statements: make([]savepointStatement, 0, len(data))}
+	for _, row := range data {
+		preview, err := bp.GenerateSQLPreview(ctx, schema, []map[string]any{row})
+		if err != nil {
+			return nil, err
+		}
+		ops.statements = append(ops.statements, savepointStatement{sql: preview.SQL, args: preview.Args, row: row})
+	}
+	return Operations{ops}, nil
+}
+
+// executeSavepoints 在单个事务中逐行执行，行级失败回滚到保存点并在提交后投递死信
+func (bp *SQLBatchProcessor) executeSavepoints(ctx context.Context, execer sqlExecer, ops *savepointOperations) error {
+	wrap := func(cause error) error {
+		if errors.Is(cause, context.DeadlineExceeded) {
+			if c := context.Cause(ctx); c != nil {
+				cause = c
+			}
+		}
+		return &SQLError{Stage: SQLStageExecute, Table: ops.schema.Name(), BatchSize: len(ops.statements), Cause: cause}
+	}
+
+	tx, err := execer.BeginTx(ctx, nil)
+	if err != nil {
+		return wrap(fmt.Errorf("begin transaction: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	type failedRow struct {
+		row map[string]any
+		err error
+	}
+	var failed []failedRow
+	for _, stmt := range ops.statements {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT "+rowSavepointName); err != nil {
+			return wrap(fmt.Errorf("savepoint: %w", err))
+		}
+		err := bp.execTx(ctx, tx, stmt.sql, stmt.args)
+		for attempt := 0; err != nil; attempt++ {
+			if ctx.Err() != nil {
+				return wrap(err)
+			}
+			if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+rowSavepointName); rbErr != nil {
+				return wrap(fmt.Errorf("rollback to savepoint: %w", errors.Join(err, rbErr)))
+			}
+			if bp.savepointDeadlockRetries <= 0 || !isDeadlockError(err) {
+				break
+			}
+			if attempt >= bp.savepointDeadlockRetries {
+				// 死锁是瞬时错误，不投递死信：整个事务失败，交由批次级重试
+				return wrap(fmt.Errorf("deadlock persisted after %d savepoint retries: %w", attempt, err))
+			}
+			// ROLLBACK TO SAVEPOINT 保留保存点，直接在其后重新执行该语句
+			err = bp.execTx(ctx, tx, stmt.sql, stmt.args)
+		}
+		if err != nil {
+			if _, relErr := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+rowSavepointName); relErr != nil {
+				return wrap(fmt.Errorf("release savepoint: %w", relErr))
+			}
+			failed = append(failed, failedRow{row: stmt.row, err: &SQLError{
+				Stage:          SQLStageExecute,
+				Table:          ops.schema.Name(),
+				BatchSize:      1,
+				SQLFingerprint: FingerprintSQL(stmt.sql),
+				ArgsCount:      len(stmt.args),
+				Cause:          err,
+			}})
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+rowSavepointName); err != nil {
+			return wrap(fmt.Errorf("release savepoint: %w", err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return wrap(fmt.Errorf("commit: %w", err))
+	}
+
+	for _, f := range failed {
+		bp.deadLetter(ops.schema, f.row, RowTags(ctx, f.row), f.err)
+	}
+	return nil
+}
+
+// execTx 在事务内执行语句，经过执行钩子，并在命中采样时上报语句与耗时
+func (bp *SQLBatchProcessor) execTx(ctx context.Context, tx *sql.Tx, query string, args []any) error {
+	return bp.runExec(ctx, query, args, func() error {
+		_, err := tx.ExecContext(ctx, query, bp.execArgs(args)...)
+		return err
+	})
+}
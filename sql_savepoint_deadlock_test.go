@@ -0,0 +1,141 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+var errFakeDeadlock = errors.New("Deadlock found when trying to get lock; try restarting transaction")
+
+// deadlockingHook 让 id 为 victim 的语句在前 times 次执行时以死锁失败（不执行），之后正常执行
+func deadlockingHook(victim int64, times int, attempts *int) batchflow.ExecuteHook {
+	return func(_ context.Context, _ string, args []any, next func() error) error {
+		if len(args) > 0 && args[0] == victim {
+			*attempts++
+			if *attempts <= times {
+				return errFakeDeadlock
+			}
+		}
+		return next()
+	}
+}
+
+func openSavepointLedger(t *testing.T, name string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file:"+name+"?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE ledger (id INTEGER PRIMARY KEY, amount INTEGER NOT NULL)"); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+	return db
+}
+
+func ledgerRows(n int) []map[string]any {
+	rows := make([]map[string]any, 0, n)
+	for i := 1; i <= n; i++ {
+		rows = append(rows, map[string]any{"id": int64(i), "amount": int64(i * 10)})
+	}
+	return rows
+}
+
+func countLedger(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM ledger").Scan(&n); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	return n
+}
+
+func TestSQLBatchProcessor_SavepointDeadlockRetryCommits(t *testing.T) {
+	db := openSavepointLedger(t, "batchflow_savepoint_deadlock_retry")
+
+	var attempts int
+	var dead []any
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).
+		WithRowSavepoints(func(_ batchflow.SchemaInterface, row map[string]any, _ error) {
+			dead = append(dead, row["id"])
+		}).
+		WithSavepointDeadlockRetries(2).
+		WithExecuteHook(deadlockingHook(3, 1, &attempts))
+	executor := batchflow.NewThrottledBatchExecutor(processor)
+
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig, "id", "amount")
+	if err := executor.ExecuteBatch(context.Background(), schema, ledgerRows(5)); err != nil {
+		t.Fatalf("expected transaction to commit after deadlock retry, got %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected deadlocked statement to run twice, got %d", attempts)
+	}
+	if got := countLedger(t, db); got != 5 {
+		t.Fatalf("expected 5 committed rows, got %d", got)
+	}
+	if len(dead) != 0 {
+		t.Fatalf("expected no dead letters, got %v", dead)
+	}
+}
+
+func TestSQLBatchProcessor_SavepointDeadlockRetriesExhaustedFailsTransaction(t *testing.T) {
+	db := openSavepointLedger(t, "batchflow_savepoint_deadlock_exhausted")
+
+	var attempts int
+	var dead []any
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).
+		WithRowSavepoints(func(_ batchflow.SchemaInterface, row map[string]any, _ error) {
+			dead = append(dead, row["id"])
+		}).
+		WithSavepointDeadlockRetries(2).
+		WithExecuteHook(deadlockingHook(3, 100, &attempts))
+	executor := batchflow.NewThrottledBatchExecutor(processor)
+
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig, "id", "amount")
+	err := executor.ExecuteBatch(context.Background(), schema, ledgerRows(5))
+	if !errors.Is(err, errFakeDeadlock) {
+		t.Fatalf("expected deadlock error after retries exhausted, got %v", err)
+	}
+	if _, reason := batchflow.ClassifyError(err); reason != batchflow.ErrorReasonDeadlock {
+		t.Fatalf("expected deadlock classification, got %q", reason)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 1 attempt + 2 retries, got %d", attempts)
+	}
+	if got := countLedger(t, db); got != 0 {
+		t.Fatalf("expected transaction rolled back, got %d rows", got)
+	}
+	if len(dead) != 0 {
+		t.Fatalf("expected no dead letters for deadlock, got %v", dead)
+	}
+}
+
+func TestSQLBatchProcessor_SavepointDeadlockWithoutRetriesDeadLetters(t *testing.T) {
+	db := openSavepointLedger(t, "batchflow_savepoint_deadlock_default")
+
+	var attempts int
+	var dead []any
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).
+		WithRowSavepoints(func(_ batchflow.SchemaInterface, row map[string]any, _ error) {
+			dead = append(dead, row["id"])
+		}).
+		WithExecuteHook(deadlockingHook(3, 1, &attempts))
+	executor := batchflow.NewThrottledBatchExecutor(processor)
+
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig, "id", "amount")
+	if err := executor.ExecuteBatch(context.Background(), schema, ledgerRows(5)); err != nil {
+		t.Fatalf("ExecuteBatch: %v", err)
+	}
+	if attempts != 1 || len(dead) != 1 || dead[0] != int64(3) {
+		t.Fatalf("expected row 3 dead-lettered without retry, attempts=%d dead=%v", attempts, dead)
+	}
+	if got := countLedger(t, db); got != 4 {
+		t.Fatalf("expected 4 committed rows, got %d", got)
+	}
+}
@@ -0,0 +1,66 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestSQLBatchProcessor_RowSavepointsDeadLetterPoisonRow(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:batchflow_savepoint_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE ledger (id INTEGER PRIMARY KEY, amount INTEGER NOT NULL CHECK (amount >= 0))"); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+
+	type deadLetter struct {
+		id  any
+		err error
+	}
+	var dead []deadLetter
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).
+		WithRowSavepoints(func(schema batchflow.SchemaInterface, row map[string]any, err error) {
+			if schema.Name() != "ledger" {
+				t.Errorf("unexpected schema %q", schema.Name())
+			}
+			dead = append(dead, deadLetter{id: row["id"], err: err})
+		})
+	executor := batchflow.NewThrottledBatchExecutor(processor)
+
+	schema := batchflow.NewSQLSchema("ledger", batchflow.ConflictUpdateOperationConfig, "id", "amount")
+	rows := make([]map[string]any, 0, 10)
+	for i := 1; i <= 10; i++ {
+		amount := int64(i * 10)
+		if i == 4 {
+			amount = -1 // 违反 CHECK 约束
+		}
+		rows = append(rows, map[string]any{"id": int64(i), "amount": amount})
+	}
+
+	if err := executor.ExecuteBatch(context.Background(), schema, rows); err != nil {
+		t.Fatalf("expected poison row to be isolated, got %v", err)
+	}
+
+	var committed int
+	if err := db.QueryRow("SELECT COUNT(*) FROM ledger").Scan(&committed); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if committed != 9 {
+		t.Fatalf("expected 9 committed rows, got %d", committed)
+	}
+	if len(dead) != 1 || dead[0].id != int64(4) {
+		t.Fatalf("expected row 4 dead-lettered, got %+v", dead)
+	}
+	var sqlErr *batchflow.SQLError
+	if !errors.As(dead[0].err, &sqlErr) || sqlErr.Stage != batchflow.SQLStageExecute {
+		t.Fatalf("expected execute-stage SQLError, got %v", dead[0].err)
+	}
+}
@@ -0,0 +1,57 @@
+package batchflow_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestSQLBatchProcessor_StatementSampler(t *testing.T) {
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig, "id", "email")
+	data := []map[string]any{
+		{"id": 1, "email": "a@example.com"},
+		{"id": 2, "email": "b@example.com"},
+	}
+
+	type sample struct {
+		sql      string
+		argCount int
+		d        time.Duration
+	}
+
+	run := func(rate float64) []sample {
+		db, _ := openFlakyDB(t, 0)
+		var samples []sample
+		processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultMySQLDriver).
+			WithStatementSampler(rate, func(sql string, argCount int, d time.Duration) {
+				samples = append(samples, sample{sql, argCount, d})
+			})
+		exec := batchflow.NewThrottledBatchExecutor(processor)
+		for i := 0; i < 3; i++ {
+			if err := exec.ExecuteBatch(context.Background(), schema, data); err != nil {
+				t.Fatalf("ExecuteBatch failed: %v", err)
+			}
+		}
+		return samples
+	}
+
+	all := run(1.0)
+	if len(all) != 3 {
+		t.Fatalf("rate=1.0: expected 3 samples, got %d", len(all))
+	}
+	for _, s := range all {
+		if !strings.HasPrefix(s.sql, "INSERT IGNORE INTO users (id, email)") || s.argCount != 4 {
+			t.Fatalf("unexpected sample: %+v", s)
+		}
+		if strings.Contains(s.sql, "example.com") {
+			t.Fatalf("sampled SQL must not contain argument values: %s", s.sql)
+		}
+	}
+
+	if none := run(0.0); len(none) != 0 {
+		t.Fatalf("rate=0.0: expected no samples, got %d", len(none))
+	}
+}
@@ -0,0 +1,86 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestSQLiteBatchFlow_SerializesConcurrentBatches(t *testing.T) {
+	// shared-cache 内存库：多个连接共享同一数据库，并发写会立即返回 SQLITE_LOCKED/BUSY
+	db, err := sql.Open("sqlite3", "file:batchflow_serial_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE events (id INTEGER PRIMARY KEY, payload TEXT)"); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+
+	ctx := context.Background()
+	flow := batchflow.NewSQLiteBatchFlow(ctx, db, batchflow.PipelineConfig{
+		BufferSize:    256,
+		FlushSize:     5,
+		FlushInterval: 10 * time.Millisecond,
+		Retry: batchflow.RetryConfig{
+			Enabled:     true,
+			MaxAttempts: 3,
+			BackoffBase: 5 * time.Millisecond,
+			MaxBackoff:  20 * time.Millisecond,
+		},
+	})
+	errCh := flow.ErrorChan(256)
+
+	schema := batchflow.NewSQLSchema("events", batchflow.ConflictIgnoreOperationConfig, "id", "payload")
+	const workers, perWorker = 8, 25
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				req := batchflow.NewRequest(schema).
+					SetInt64("id", int64(w*perWorker+i)).
+					SetString("payload", "event")
+				if err := flow.Submit(ctx, req); err != nil {
+					t.Errorf("submit failed: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := flow.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	// Close 仅等待最终 flush；此前异步派发的批次可能仍在执行，按行数轮询等待
+	want := workers * perWorker
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		select {
+		case err := <-errCh:
+			if strings.Contains(strings.ToLower(err.Error()), "locked") {
+				t.Fatalf("unexpected SQLITE_BUSY/LOCKED surfaced: %v", err)
+			}
+			t.Fatalf("unexpected flush error: %v", err)
+		default:
+		}
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM events").Scan(&count); err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		if count == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d rows, got %d", want, count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
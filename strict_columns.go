@@ -0,0 +1,33 @@
+package batchflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// checkUnknownColumns 在 schema 启用 StrictColumns 时检查请求是否设置了未声明的列；
+// 未启用时这些列在 flush 组装时被忽略
+func (r *Request) checkUnknownColumns() error {
+	s, ok := r.schema.(*SQLSchema)
+	if !ok || s == nil || !s.operationConfig.StrictColumns {
+		return nil
+	}
+	declared := s.Columns()
+	var unknown []string
+	for colName := range r.columns {
+		if !slices.Contains(declared, colName) {
+			unknown = append(unknown, colName)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	slices.Sort(unknown)
+	return &ColumnError{
+		SchemaName: s.Name(),
+		Column:     unknown[0],
+		Reason:     fmt.Sprintf("column(s) not declared by schema: %s", strings.Join(unknown, ", ")),
+		Err:        ErrUnknownColumn,
+	}
+}
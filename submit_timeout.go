@@ -0,0 +1,24 @@
+package batchflow
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SubmitWithTimeout 与 Submit 相同，但最多等待 timeout 让缓冲区接受请求；超时返回 ErrSubmitTimeout，
+// 便于延迟敏感的调用方限定等待时间而无需自行派生 context。
+// ctx 先于超时被取消时返回 ctx 的错误；timeout <= 0 时等同于 Submit。
+// 超时拒绝以 reason "buffer_full" 上报 IncSubmitRejected，便于与调用方 ctx 取消区分、观测降载。
+func (b *BatchFlow) SubmitWithTimeout(ctx context.Context, request *Request, timeout time.Duration) error {
+	if timeout <= 0 {
+		return b.Submit(ctx, request)
+	}
+	submitCtx, cancel := context.WithTimeoutCause(ctx, timeout, ErrSubmitTimeout)
+	defer cancel()
+	err := b.Submit(submitCtx, request)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return ErrSubmitTimeout
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package batchflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+func newValidationBatchFlow(t *testing.T) *batchflow.BatchFlow {
+	t.Helper()
+	b, _ := batchflow.NewBatchFlowWithMock(context.Background(), batchflow.PipelineConfig{
+		BufferSize:    16,
+		FlushSize:     8,
+		FlushInterval: 50 * time.Millisecond,
+	})
+	t.Cleanup(func() { _ = b.Close() })
+	return b
+}
+
+func TestBatchFlow_Submit_SchemaErrors(t *testing.T) {
+	ctx := context.Background()
+	b := newValidationBatchFlow(t)
+
+	cases := []struct {
+		name     string
+		req      *batchflow.Request
+		sentinel error
+	}{
+		{"nil request", nil, batchflow.ErrEmptyRequest},
+		{"nil schema", batchflow.NewRequest(nil), batchflow.ErrInvalidSchema},
+		{"empty schema name", batchflow.NewRequest(batchflow.NewSQLSchema("", batchflow.ConflictIgnoreOperationConfig, "id")), batchflow.ErrEmptySchemaName},
+	}
+	for _, tc := range cases {
+		err := b.Submit(ctx, tc.req)
+		if !errors.Is(err, tc.sentinel) {
+			t.Fatalf("%s: expected errors.Is(%v), got %v", tc.name, tc.sentinel, err)
+		}
+		var schemaErr *batchflow.SchemaError
+		if !errors.As(err, &schemaErr) {
+			t.Fatalf("%s: expected *SchemaError, got %T", tc.name, err)
+		}
+		if schemaErr.Reason == "" {
+			t.Fatalf("%s: expected non-empty reason", tc.name)
+		}
+	}
+}
+
+func TestBatchFlow_Submit_ColumnError(t *testing.T) {
+	ctx := context.Background()
+	b := newValidationBatchFlow(t)
+
+	schema := batchflow.NewSQLSchema("users", batchflow.ConflictIgnoreOperationConfig)
+	err := b.Submit(ctx, batchflow.NewRequest(schema))
+	if !errors.Is(err, batchflow.ErrMissingColumn) {
+		t.Fatalf("expected ErrMissingColumn, got %v", err)
+	}
+	var colErr *batchflow.ColumnError
+	if !errors.As(err, &colErr) {
+		t.Fatalf("expected *ColumnError, got %T", err)
+	}
+	if colErr.SchemaName != "users" {
+		t.Fatalf("expected schema name users, got %q", colErr.SchemaName)
+	}
+	var schemaErr *batchflow.SchemaError
+	if errors.As(err, &schemaErr) {
+		t.Fatalf("column error should not match *SchemaError")
+	}
+}
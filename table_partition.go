@@ -0,0 +1,64 @@
+package batchflow
+
+// PartitionFunc 根据行数据计算目标表名后缀（如按月分表返回 "_202401"）。
+// 返回空字符串表示写入 schema 原表。row 为请求的原始列数据（未填充 schema 默认值），只读。
+type PartitionFunc func(row map[string]any) (tableSuffix string)
+
+// tablePartitions 对配置了 PartitionFunc 的 SQLSchema，按计算出的表名后缀拆分请求，
+// 每个后缀使用表名为 schema.Name()+suffix 的 schema 副本单独执行；保持首次出现顺序。
+// 其他 schema 原样返回单个分区。
+func tablePartitions(schema SchemaInterface, requests []*Request) []schemaPartition {
+	sqlSchema, ok := schema.(*SQLSchema)
+	if !ok || sqlSchema.operationConfig.PartitionFunc == nil {
+		return []schemaPartition{{schema: schema, requests: requests}}
+	}
+	partitionFunc := sqlSchema.operationConfig.PartitionFunc
+	var (
+		partitions []schemaPartition
+		index      = make(map[string]int)
+	)
+	for _, request := range requests {
+		suffix := partitionFunc(request.columns)
+		i, exists := index[suffix]
+		if !exists {
+			i = len(partitions)
+			index[suffix] = i
+			partitions = append(partitions, schemaPartition{schema: sqlSchema.withTableSuffix(suffix)})
+		}
+		partitions[i].requests = append(partitions[i].requests, request)
+	}
+	return partitions
+}
+
+// groupPartitions 依次应用按表分区、删除段拆分与稀疏列分区（删除段只含冲突键，不再做稀疏拆分）
+func groupPartitions(schema SchemaInterface, requests []*Request) []schemaPartition {
+	tables := tablePartitions(schema, requests)
+	if len(tables) == 1 && !hasDeleteRequest(requests) {
+		return sparsePartitions(tables[0].schema, tables[0].requests)
+	}
+	var partitions []schemaPartition
+	for _, table := range tables {
+		for _, segment := range deletePartitions(table.schema, table.requests) {
+			if isDeleteSchema(segment.schema) {
+				partitions = append(partitions, segment)
+				continue
+			}
+			partitions = append(partitions, sparsePartitions(segment.schema, segment.requests)...)
+		}
+	}
+	return partitions
+}
+
+// withTableSuffix 返回表名追加 suffix 的 schema 副本；suffix 为空时返回自身
+func (s *SQLSchema) withTableSuffix(suffix string) *SQLSchema {
+	if suffix == "" {
+		return s
+	}
+	return &SQLSchema{
+		Schema:          NewSchema(s.Name()+suffix, s.Columns()...),
+		operationConfig: s.operationConfig,
+		dbColumns:       s.dbColumns,
+		defaults:        s.defaults,
+		source:          s.Source(),
+	}
+}
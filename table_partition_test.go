@@ -0,0 +1,82 @@
+package batchflow_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rushairer/batchflow/v2"
+)
+
+func TestBatchFlow_PartitionFuncRoutesRowsByMonth(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:batchflow_table_partition_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	for _, table := range []string{"events_202401", "events_202402"} {
+		if _, err := db.Exec("CREATE TABLE " + table + " (id INTEGER PRIMARY KEY, created_at DATETIME)"); err != nil {
+			t.Fatalf("create table failed: %v", err)
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	processor := batchflow.NewSQLBatchProcessor(db, batchflow.DefaultSQLiteDriver).
+		WithExecuteHook(func(_ context.Context, query string, args []any, next func() error) error {
+			mu.Lock()
+			queries = append(queries, query)
+			mu.Unlock()
+			return next()
+		})
+	ctx := context.Background()
+	b, err := batchflow.NewBatchFlowWithConfig(ctx, batchflow.BatchFlowConfig{
+		Pipeline: batchflow.PipelineConfig{BufferSize: 10, FlushSize: 5, FlushInterval: time.Hour},
+		Executor: batchflow.NewThrottledBatchExecutor(processor),
+	})
+	if err != nil {
+		t.Fatalf("new batchflow failed: %v", err)
+	}
+
+	config := batchflow.ConflictIgnoreOperationConfig.WithPartitionFunc(func(row map[string]any) string {
+		return row["created_at"].(time.Time).Format("_200601")
+	})
+	schema := batchflow.NewSQLSchema("events", config, "id", "created_at")
+	days := []time.Time{
+		time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for i, day := range days {
+		if err := b.Submit(ctx, batchflow.NewRequest(schema).SetInt("id", i+1).SetTime("created_at", day)); err != nil {
+			t.Fatalf("submit failed: %v", err)
+		}
+	}
+	// 恰好满批时 flush 异步触发，Close 不等待它完成
+	waitUntilEmpty(t, b)
+	b.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(queries) != 2 || !strings.Contains(queries[0], "events_202401") || !strings.Contains(queries[1], "events_202402") {
+		t.Fatalf("expected one statement per monthly table in first-seen order, got %v", queries)
+	}
+	for table, want := range map[string]int{"events_202401": 3, "events_202402": 2} {
+		var got int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&got); err != nil {
+			t.Fatalf("count %s failed: %v", table, err)
+		}
+		if got != want {
+			t.Fatalf("%s: expected %d rows, got %d", table, want, got)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package batchflow_test
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// benchBaseline 单个基准的参考值，来自 testdata/baseline.json
+type benchBaseline struct {
+	NsPerOp     int64 `json:"ns_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+}
+
+// defaultBaselineTolerance 允许的劣化倍数；微基准噪声较大，只拦截数量级回退
+const defaultBaselineTolerance = 2.0
+
+func loadBenchBaselines(t *testing.T) map[string]benchBaseline {
+	t.Helper()
+	raw, err := os.ReadFile("testdata/baseline.json")
+	if err != nil {
+		t.Fatalf("read baseline: %v", err)
+	}
+	baselines := make(map[string]benchBaseline)
+	if err := json.Unmarshal(raw, &baselines); err != nil {
+		t.Fatalf("parse baseline: %v", err)
+	}
+	return baselines
+}
+
+// assertBenchmarkBaseline 对比基准结果与基线，ns/op 或 allocs/op 超过 基线×tolerance 时失败
+func assertBenchmarkBaseline(t *testing.T, name string, result testing.BenchmarkResult, base benchBaseline, tolerance float64) {
+	t.Helper()
+	t.Logf("%s: %s %s (baseline %d ns/op, %d allocs/op)", name, result.String(), result.MemString(), base.NsPerOp, base.AllocsPerOp)
+	if base.NsPerOp > 0 && float64(result.NsPerOp()) > float64(base.NsPerOp)*tolerance {
+		t.Errorf("%s regressed: %d ns/op > %.1fx baseline %d", name, result.NsPerOp(), tolerance, base.NsPerOp)
+	}
+	if base.AllocsPerOp > 0 && float64(result.AllocsPerOp()) > float64(base.AllocsPerOp)*tolerance {
+		t.Errorf("%s regressed: %d allocs/op > %.1fx baseline %d", name, result.AllocsPerOp(), tolerance, base.AllocsPerOp)
+	}
+}
+
+// TestBenchmarkBaselines 在 CI 中检测 Submit/flush 热路径的明显性能回退。
+// 默认跳过；设置 BATCHFLOW_BENCH_BASELINE=1 启用，BATCHFLOW_BENCH_TOLERANCE 调整容忍倍数。
+func TestBenchmarkBaselines(t *testing.T) {
+	if os.Getenv("BATCHFLOW_BENCH_BASELINE") != "1" {
+		t.Skip("set BATCHFLOW_BENCH_BASELINE=1 to compare benchmarks against testdata/baseline.json")
+	}
+	tolerance := defaultBaselineTolerance
+	if v := os.Getenv("BATCHFLOW_BENCH_TOLERANCE"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 1 {
+			t.Fatalf("invalid BATCHFLOW_BENCH_TOLERANCE %q", v)
+		}
+		tolerance = parsed
+	}
+
+	baselines := loadBenchBaselines(t)
+	benches := map[string]func(*testing.B){
+		"BenchmarkSubmit/schemas=1":        func(b *testing.B) { benchmarkSubmit(b, 1) },
+		"BenchmarkSubmit/schemas=8":        func(b *testing.B) { benchmarkSubmit(b, 8) },
+		"BenchmarkFlushAssembly/schemas=1": func(b *testing.B) { benchmarkFlushAssembly(b, 1) },
+		"BenchmarkFlushAssembly/schemas=8": func(b *testing.B) { benchmarkFlushAssembly(b, 8) },
+	}
+	for name, fn := range benches {
+		base, ok := baselines[name]
+		if !ok {
+			t.Errorf("missing baseline for %s", name)
+			continue
+		}
+		assertBenchmarkBaseline(t, name, testing.Benchmark(fn), base, tolerance)
+	}
+}
@@ -0,0 +1,99 @@
+package batchflow_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rushairer/batchflow/v2"
+)
+
+// benchSchemas 构造 n 个列结构相同的 schema，用于对比单 schema 与多 schema 分组开销
+func benchSchemas(n int) []*batchflow.SQLSchema {
+	schemas := make([]*batchflow.SQLSchema, n)
+	for i := range schemas {
+		schemas[i] = batchflow.NewSQLSchema(fmt.Sprintf("events_%d", i), batchflow.ConflictIgnoreOperationConfig, "id", "name", "score", "created_at")
+	}
+	return schemas
+}
+
+func benchRequest(schema *batchflow.SQLSchema, i int, now time.Time) *batchflow.Request {
+	return batchflow.NewRequest(schema).
+		SetInt64("id", int64(i)).
+		SetString("name", "user").
+		SetFloat64("score", float64(i)).
+		SetTime("created_at", now)
+}
+
+// BenchmarkSubmit 测量 Submit 热路径（构造请求 + 入队），后台 flush 使用 MockExecutor
+func BenchmarkSubmit(b *testing.B) {
+	for _, n := range []int{1, 8} {
+		b.Run(fmt.Sprintf("schemas=%d", n), func(b *testing.B) {
+			benchmarkSubmit(b, n)
+		})
+	}
+}
+
+func benchmarkSubmit(b *testing.B, schemaCount int) {
+	ctx := context.Background()
+	flow, _ := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    10000,
+		FlushSize:     1000,
+		FlushInterval: 50 * time.Millisecond,
+	})
+	b.Cleanup(func() { _ = flow.Close() })
+	schemas := benchSchemas(schemaCount)
+	now := time.Now()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := flow.Submit(ctx, benchRequest(schemas[i%schemaCount], i, now)); err != nil {
+			b.Fatalf("Submit failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkFlushAssembly 测量一次完整 flush：提交 flushSize 条请求并等待 MockExecutor 收到全部行，
+// 覆盖按 schema 分组、行组装与执行器调用。每个 op 对应一次 flush。
+func BenchmarkFlushAssembly(b *testing.B) {
+	for _, n := range []int{1, 8} {
+		b.Run(fmt.Sprintf("schemas=%d", n), func(b *testing.B) {
+			benchmarkFlushAssembly(b, n)
+		})
+	}
+}
+
+func benchmarkFlushAssembly(b *testing.B, schemaCount int) {
+	const flushSize = 1000
+	ctx := context.Background()
+	flow, mock := batchflow.NewBatchFlowWithMock(ctx, batchflow.PipelineConfig{
+		BufferSize:    flushSize * 2,
+		FlushSize:     flushSize,
+		FlushInterval: time.Hour,
+	})
+	b.Cleanup(func() { _ = flow.Close() })
+	var executed atomic.Int64
+	mock.WithOnBatchSuccess(func(_ batchflow.SchemaInterface, rows []map[string]any) {
+		executed.Add(int64(len(rows)))
+	})
+	schemas := benchSchemas(schemaCount)
+	now := time.Now()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < flushSize; j++ {
+			if err := flow.Submit(ctx, benchRequest(schemas[j%schemaCount], j, now)); err != nil {
+				b.Fatalf("Submit failed: %v", err)
+			}
+		}
+		want := int64(i+1) * flushSize
+		for executed.Load() < want {
+			time.Sleep(10 * time.Microsecond)
+		}
+	}
+	b.ReportMetric(float64(flushSize*b.N)/b.Elapsed().Seconds(), "rows/s")
+}
@@ -0,0 +1,83 @@
+package batchflow
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// errImmutableAtRuntime 字段在管道启动后无法调整
+var errImmutableAtRuntime = errors.New("cannot be changed at runtime")
+
+// PipelineConfigPatch 运行时配置调整（BatchFlow.UpdateConfig）。nil 字段保持不变。
+// BufferSize 与 MaxConcurrentFlushes 由 go-pipeline 在启动时固定，设置后 UpdateConfig 返回 *ConfigError。
+type PipelineConfigPatch struct {
+	FlushSize        *uint32
+	FlushInterval    *time.Duration
+	ConcurrencyLimit *int
+
+	BufferSize           *uint32 // 不可变，仅用于明确报错
+	MaxConcurrentFlushes *uint32 // 不可变，仅用于明确报错
+}
+
+// UpdateConfig 在运行时调整主通道的 FlushSize/FlushInterval 与执行器并发上限，无需重建 BatchFlow。
+// 先校验全部字段，任一字段非法时不做任何修改并返回 *ConfigError；调整 ConcurrencyLimit 要求执行器实现
+// ConcurrencyLimitUpdater（ThrottledBatchExecutor 已实现）。
+// FlushInterval 会重新应用 FlushIntervalJitter；内存压力或 MaxBatchAge 强制 flush 期间，新值在其恢复时生效。
+// 优先通道（PriorityFlushInterval）不受影响。
+func (b *BatchFlow) UpdateConfig(patch PipelineConfigPatch) error {
+	if patch.BufferSize != nil {
+		return &ConfigError{Field: "BufferSize", Cause: errImmutableAtRuntime}
+	}
+	if patch.MaxConcurrentFlushes != nil {
+		return &ConfigError{Field: "MaxConcurrentFlushes", Cause: errImmutableAtRuntime}
+	}
+	if patch.FlushSize != nil {
+		if *patch.FlushSize == 0 {
+			return &ConfigError{Field: "FlushSize", Cause: errors.New("must be > 0")}
+		}
+		if *patch.FlushSize > b.bufferSize {
+			return &ConfigError{Field: "FlushSize", Cause: fmt.Errorf("%d exceeds BufferSize %d", *patch.FlushSize, b.bufferSize)}
+		}
+	}
+	if patch.FlushInterval != nil && *patch.FlushInterval <= 0 {
+		return &ConfigError{Field: "FlushInterval", Cause: errors.New("must be > 0")}
+	}
+	var limiter ConcurrencyLimitUpdater
+	if patch.ConcurrencyLimit != nil {
+		if *patch.ConcurrencyLimit < 0 {
+			return &ConfigError{Field: "ConcurrencyLimit", Cause: errors.New("must be >= 0")}
+		}
+		var ok bool
+		if limiter, ok = b.executor.(ConcurrencyLimitUpdater); !ok || !forwardsConcurrencyLimit(b.executor) {
+			return &ConfigError{Field: "ConcurrencyLimit", Cause: fmt.Errorf("executor %T does not implement ConcurrencyLimitUpdater", b.executor)}
+		}
+	}
+
+	if patch.FlushSize != nil {
+		b.flushSize.Store(*patch.FlushSize)
+		if !b.memPressure.Load() {
+			b.pipeline.UpdateFlushSize(*patch.FlushSize)
+		}
+	}
+	if patch.FlushInterval != nil {
+		interval := jitterFlushInterval(*patch.FlushInterval, b.flushJitter)
+		b.effectiveFlushInterval.Store(int64(interval))
+		if !b.memPressure.Load() && !b.ageForcing.Load() {
+			b.pipeline.UpdateFlushInterval(interval)
+		}
+	}
+	if limiter != nil {
+		limiter.UpdateConcurrencyLimit(*patch.ConcurrencyLimit)
+		b.concurrencyLimit.Store(int64(*patch.ConcurrencyLimit))
+	}
+
+	if cmr, ok := b.metricsReporter.(ConfigMetricsReporter); ok && cmr != nil {
+		cmr.SetConfig(b.bufferSize, b.flushSize.Load(), b.EffectiveFlushInterval(), int(b.concurrencyLimit.Load()))
+	}
+	b.logger.Info("batchflow config updated",
+		"flush_size", b.flushSize.Load(),
+		"flush_interval", b.EffectiveFlushInterval(),
+		"concurrency_limit", b.concurrencyLimit.Load())
+	return nil
+}
@@ -0,0 +1,112 @@
+package batchflow
+
+import (
+	"context"
+	"sync"
+)
+
+// pendingTracker 统计已入队但尚未处理完成的请求数，归零时唤醒 WaitUntilEmpty 的等待者
+type pendingTracker struct {
+	mu      sync.Mutex
+	n       int
+	emptyCh chan struct{} // 非 nil 表示有等待者；计数归零时关闭
+	freedCh chan struct{} // 非 nil 表示有 tryAdd 等待者；计数减少时关闭
+}
+
+func (p *pendingTracker) add(n int) {
+	p.mu.Lock()
+	p.n += n
+	p.mu.Unlock()
+}
+
+// tryAdd 计数低于 limit 时加一并返回 true；否则返回在计数下次减少时关闭的通道
+func (p *pendingTracker) tryAdd(limit int) (<-chan struct{}, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.n < limit {
+		p.n++
+		return nil, true
+	}
+	if p.freedCh == nil {
+		p.freedCh = make(chan struct{})
+	}
+	return p.freedCh, false
+}
+
+func (p *pendingTracker) done(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.n -= n
+	if p.freedCh != nil {
+		close(p.freedCh)
+		p.freedCh = nil
+	}
+	if p.n <= 0 {
+		p.n = 0
+		if p.emptyCh != nil {
+			close(p.emptyCh)
+			p.emptyCh = nil
+		}
+	}
+}
+
+// wait 返回计数归零时关闭的通道；当前已为空时返回 nil
+func (p *pendingTracker) wait() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.n == 0 {
+		return nil
+	}
+	if p.emptyCh == nil {
+		p.emptyCh = make(chan struct{})
+	}
+	return p.emptyCh
+}
+
+// WaitUntilEmpty 阻塞直到缓冲区排空且没有正在执行的批次（含 AfterFlush 回调），或 ctx 结束。
+// 适合在测试中替代 time.Sleep 等待 flush，以及在关闭前确认数据已落库。
+// 它不会触发 flush：剩余请求仍按 FlushSize/FlushInterval 节奏处理。
+// 批次执行失败同样视为处理完成，失败原因仍通过 ErrorChan/OnError 获取。
+// 经 Close 正常退出后仍会等待在途的异步批次；因 ctx 取消在排空前退出时返回其运行结果（见 Wait）。
+func (b *BatchFlow) WaitUntilEmpty(ctx context.Context) error {
+	ch := b.pending.wait()
+	if ch == nil {
+		return nil
+	}
+	select {
+	case <-ch:
+		return nil
+	case <-b.done:
+		if err := b.getRunErr(); err != nil && b.pending.wait() != nil {
+			return err
+		}
+		ch = b.pending.wait()
+		if ch == nil {
+			return nil
+		}
+		select {
+		case <-ch:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// awaitFlushes 须在后台管道退出后调用：正常退出（Close 关闭数据通道）时剩余请求都已交给 flush，
+// 等待其中仍在执行的异步批次完成、且失败批次的错误已写入错误通道；
+// 因 ctx 取消退出时缓冲数据可能被丢弃、计数不会归零，直接返回
+func (b *BatchFlow) awaitFlushes() {
+	if b.getRunErr() != nil {
+		return
+	}
+	if ch := b.pending.wait(); ch != nil {
+		<-ch
+	}
+	// 错误计数先于待处理计数扣减前增加，此时已包含全部失败批次
+	if ch := b.errDelivery.wait(); ch != nil {
+		<-ch
+	}
+}